@@ -8,6 +8,8 @@ type Config struct {
 	Count      int                `json:"count" yaml:"count"`
 	Check      string             `json:"check" yaml:"check"`
 	Period     string             `json:"period" yaml:"period"`
+	Jitter     string             `json:"jitter" yaml:"jitter"`
+	JitterSeed int64              `json:"jitter_seed" yaml:"jitter_seed"`
 	Processors []processor.Config `json:"processors" yaml:"processors"`
 }
 
@@ -18,6 +20,8 @@ func NewConfig() Config {
 		Count:      0,
 		Check:      "",
 		Period:     "",
+		Jitter:     "",
+		JitterSeed: 0,
 		Processors: []processor.Config{},
 	}
 }
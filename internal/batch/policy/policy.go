@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"strconv"
 	"time"
 
@@ -24,6 +25,9 @@ type Batcher struct {
 	byteSize  int
 	count     int
 	period    time.Duration
+	jitter    time.Duration
+	rand      *rand.Rand
+	untilNext time.Duration
 	check     *mapping.Executor
 	procs     []iprocessor.V1
 	sizeTally int
@@ -36,6 +40,7 @@ type Batcher struct {
 	mCountBatch  metrics.StatCounter
 	mPeriodBatch metrics.StatCounter
 	mCheckBatch  metrics.StatCounter
+	mPending     metrics.StatGauge
 }
 
 // New creates an empty policy with default rules.
@@ -59,6 +64,22 @@ func New(conf batchconfig.Config, mgr bundle.NewManagement) (*Batcher, error) {
 			return nil, fmt.Errorf("failed to parse duration string: %v", err)
 		}
 	}
+	var jitter time.Duration
+	if len(conf.Jitter) > 0 {
+		if jitter, err = time.ParseDuration(conf.Jitter); err != nil {
+			return nil, fmt.Errorf("failed to parse jitter duration string: %v", err)
+		}
+		if jitter < 0 {
+			return nil, fmt.Errorf("jitter must not be negative, got %v", jitter)
+		}
+		if jitter > 0 && period <= 0 {
+			return nil, errors.New("jitter requires a period to be set")
+		}
+	}
+	seed := conf.JitterSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
 	var procs []iprocessor.V1
 	for i, pconf := range conf.Processors {
 		pMgr := mgr.IntoPath("processors", strconv.Itoa(i))
@@ -70,12 +91,14 @@ func New(conf batchconfig.Config, mgr bundle.NewManagement) (*Batcher, error) {
 	}
 
 	batchOn := mgr.Metrics().GetCounterVec("batch_created", "mechanism")
-	return &Batcher{
+	p := &Batcher{
 		log: mgr.Logger(),
 
 		byteSize: conf.ByteSize,
 		count:    conf.Count,
 		period:   period,
+		jitter:   jitter,
+		rand:     rand.New(rand.NewSource(seed)),
 		check:    check,
 		procs:    procs,
 
@@ -85,7 +108,19 @@ func New(conf batchconfig.Config, mgr bundle.NewManagement) (*Batcher, error) {
 		mCountBatch:  batchOn.With("count"),
 		mPeriodBatch: batchOn.With("period"),
 		mCheckBatch:  batchOn.With("check"),
-	}, nil
+		mPending:     mgr.Metrics().GetGauge("batch_pending"),
+	}
+	p.resetPeriod()
+	return p, nil
+}
+
+// resetPeriod chooses the period of the next batch, which is the configured
+// period plus a random duration up to the jitter.
+func (p *Batcher) resetPeriod() {
+	p.untilNext = p.period
+	if p.jitter > 0 {
+		p.untilNext += time.Duration(p.rand.Int63n(int64(p.jitter) + 1))
+	}
 }
 
 //------------------------------------------------------------------------------
@@ -99,6 +134,7 @@ func (p *Batcher) Add(part *message.Part) bool {
 		p.sizeTally += len(part.AsBytes())
 	}
 	p.parts = append(p.parts, part)
+	p.mPending.Set(int64(len(p.parts)))
 
 	if !p.triggered && p.count > 0 && len(p.parts) >= p.count {
 		p.triggered = true
@@ -123,7 +159,7 @@ func (p *Batcher) Add(part *message.Part) bool {
 			p.log.Traceln("Batching based on check query")
 		}
 	}
-	return p.triggered || (p.period > 0 && time.Since(p.lastBatch) > p.period)
+	return p.triggered || (p.period > 0 && time.Since(p.lastBatch) > p.untilNext)
 }
 
 // Flush clears all messages stored by this batch policy. Returns nil if the
@@ -148,16 +184,18 @@ func (p *Batcher) Flush(ctx context.Context) message.Batch {
 func (p *Batcher) flushAny(ctx context.Context) []message.Batch {
 	var newMsg message.Batch
 	if len(p.parts) > 0 {
-		if !p.triggered && p.period > 0 && time.Since(p.lastBatch) > p.period {
+		if !p.triggered && p.period > 0 && time.Since(p.lastBatch) > p.untilNext {
 			p.mPeriodBatch.Incr(1)
 			p.log.Traceln("Batching based on period")
 		}
 		newMsg = message.Batch(p.parts)
 	}
 	p.parts = nil
+	p.mPending.Set(0)
 	p.sizeTally = 0
 	p.lastBatch = time.Now()
 	p.triggered = false
+	p.resetPeriod()
 
 	if newMsg == nil {
 		return nil
@@ -188,7 +226,7 @@ func (p *Batcher) UntilNext() time.Duration {
 	if p.period <= 0 {
 		return -1
 	}
-	return time.Until(p.lastBatch.Add(p.period))
+	return time.Until(p.lastBatch.Add(p.untilNext))
 }
 
 //------------------------------------------------------------------------------
@@ -12,6 +12,7 @@ import (
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/manager/mock"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -90,6 +91,31 @@ func TestPolicyBasic(t *testing.T) {
 	}
 }
 
+func TestPolicyPendingGauge(t *testing.T) {
+	conf := batchconfig.NewConfig()
+	conf.Count = 3
+
+	stats := metrics.NewLocal()
+	mgr := mock.NewManager()
+	mgr.M = stats
+
+	pol, err := policy.New(conf, mgr)
+	require.NoError(t, err)
+
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*30)
+	t.Cleanup(func() {
+		require.NoError(t, pol.Close(tCtx))
+		done()
+	})
+
+	assert.False(t, pol.Add(message.NewPart([]byte("foo"))))
+	assert.False(t, pol.Add(message.NewPart([]byte("bar"))))
+	assert.Equal(t, int64(2), stats.GetCounters()["batch_pending"])
+
+	require.Len(t, pol.Flush(tCtx), 2)
+	assert.Equal(t, int64(0), stats.GetCounters()["batch_pending"])
+}
+
 func TestPolicyPeriod(t *testing.T) {
 	conf := batchconfig.NewConfig()
 	conf.Period = "300ms"
@@ -127,6 +153,58 @@ func TestPolicyPeriod(t *testing.T) {
 	}
 }
 
+func TestPolicyPeriodJitter(t *testing.T) {
+	conf := batchconfig.NewConfig()
+	conf.Period = "1h"
+	conf.Jitter = "1h"
+	conf.JitterSeed = 10
+
+	untilNexts := func() (durs []time.Duration) {
+		pol, err := policy.New(conf, mock.NewManager())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, pol.Close(context.Background()))
+		})
+
+		for i := 0; i < 5; i++ {
+			v := pol.UntilNext()
+			assert.Greater(t, v, time.Minute*59)
+			assert.LessOrEqual(t, v, time.Hour*2)
+			durs = append(durs, v.Round(time.Minute))
+
+			pol.Add(message.NewPart(nil))
+			pol.Flush(context.Background())
+		}
+		return
+	}
+
+	first := untilNexts()
+	assert.Equal(t, first, untilNexts(), "periods of the same seed should match")
+
+	var varied bool
+	for _, d := range first[1:] {
+		if d != first[0] {
+			varied = true
+		}
+	}
+	assert.True(t, varied, "periods should vary between batches: %v", first)
+}
+
+func TestPolicyJitterErrors(t *testing.T) {
+	conf := batchconfig.NewConfig()
+	conf.Count = 10
+	conf.Jitter = "1s"
+
+	_, err := policy.New(conf, mock.NewManager())
+	require.EqualError(t, err, "jitter requires a period to be set")
+
+	conf.Period = "1s"
+	conf.Jitter = "-1s"
+
+	_, err = policy.New(conf, mock.NewManager())
+	require.EqualError(t, err, "jitter must not be negative, got -1s")
+}
+
 func TestPolicySize(t *testing.T) {
 	conf := batchconfig.NewConfig()
 	conf.ByteSize = 10
@@ -67,6 +67,15 @@ func (e *Expression) NumDynamicExpressions() int {
 	return e.dynamicExpressions
 }
 
+// Resolvers returns the resolvers that the expression is composed of, where an
+// expression that is entirely static is returned as a single static resolver.
+func (e *Expression) Resolvers() []Resolver {
+	if len(e.resolvers) == 0 {
+		return []Resolver{StaticResolver(e.static)}
+	}
+	return e.resolvers
+}
+
 // Bytes returns a byte slice representing the expression resolved for a message
 // of a batch.
 func (e *Expression) Bytes(index int, msg Message) []byte {
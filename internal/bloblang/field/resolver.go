@@ -44,6 +44,12 @@ func NewQueryResolver(fn query.Function) *QueryResolver {
 	return &QueryResolver{fn}
 }
 
+// QueryTargets returns the targets that the query of the resolver references.
+func (q QueryResolver) QueryTargets(ctx query.TargetsContext) []query.TargetPath {
+	_, paths := q.fn.QueryTargets(ctx)
+	return paths
+}
+
 // ResolveString returns a string.
 func (q QueryResolver) ResolveString(index int, msg Message, escaped bool) string {
 	if msg == nil {
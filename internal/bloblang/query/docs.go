@@ -120,6 +120,12 @@ func (s FunctionSpec) Param(def ParamDefinition) FunctionSpec {
 	return s
 }
 
+// VariadicParams configures the function spec to allow variadic parameters.
+func (s FunctionSpec) VariadicParams() FunctionSpec {
+	s.Params = VariadicParams()
+	return s
+}
+
 // NewDeprecatedFunctionSpec creates a new function spec that is deprecated.
 func NewDeprecatedFunctionSpec(name, description string, examples ...ExampleSpec) FunctionSpec {
 	return FunctionSpec{
@@ -2,10 +2,19 @@ package query
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
+	"hash/crc32"
 	"math"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -230,6 +239,48 @@ var _ = registerSimpleFunction(
 
 //------------------------------------------------------------------------------
 
+var contentHashers = map[string]func() hash.Hash{
+	"crc32":  func() hash.Hash { return crc32.NewIEEE() },
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryMessage, "content_hash",
+		"Returns a hex encoded hash of the raw contents of the message, which is useful for deriving object keys from the contents of messages in order to deduplicate them. The contents are written directly to the hashing algorithm rather than copied into the mapping, and the result is equivalent to `content().hash(algorithm).encode(\"hex\")`. Available algorithms are: `crc32`, `md5`, `sha1`, `sha256`, `sha512`.",
+		NewExampleSpec("",
+			`root.key = content_hash()`,
+			`hello world`,
+			`{"key":"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}`,
+		),
+		NewExampleSpec("When used within the `path` of an object storage output messages with identical contents are written to the same object.",
+			`root = "archive/" + content_hash("md5") + ".json"`,
+			`{"foo":"bar"}`,
+			`archive/9bb58f26192e4ba00f01e2e7b136bbd8.json`,
+		),
+	).Param(ParamString("algorithm", "The hashing algorithm to use.").Default("sha256")).AtVersion("4.11.0"),
+	func(args *ParsedParams) (Function, error) {
+		algorithm, err := args.FieldString("algorithm")
+		if err != nil {
+			return nil, err
+		}
+		newHasher, exists := contentHashers[algorithm]
+		if !exists {
+			return nil, fmt.Errorf("unrecognized hash type: %v", algorithm)
+		}
+		return ClosureFunction("function content_hash", func(ctx FunctionContext) (any, error) {
+			hasher := newHasher()
+			_, _ = hasher.Write(ctx.MsgBatch.Get(ctx.Index).AsBytes())
+			return hex.EncodeToString(hasher.Sum(nil)), nil
+		}, nil), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
 var _ = registerSimpleFunction(
 	NewFunctionSpec(
 		FunctionCategoryMessage, "tracing_span",
@@ -629,6 +680,57 @@ var _ = registerFunction(
 
 //------------------------------------------------------------------------------
 
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryGeneral, "path_join",
+		"Joins any number of path segments into a single path where each segment is separated by exactly one forward slash, regardless of any leading or trailing slashes of the segments. This is useful for constructing the keys of objects from parts that may or may not contain their own separators. A leading slash of the first segment is preserved, and segments that are empty or `null` are ignored. Segments are not otherwise interpreted, and therefore relative elements such as `..` are preserved.",
+		NewExampleSpec("",
+			`root.path = path_join(this.dir, this.sub, this.file)`,
+			`{"dir":"foo/","sub":"/bar//baz/","file":"/qux.txt"}`,
+			`{"path":"foo/bar/baz/qux.txt"}`,
+			`{"dir":"/foo","sub":"","file":"qux.txt"}`,
+			`{"path":"/foo/qux.txt"}`,
+		),
+	).VariadicParams().AtVersion("4.11.0"),
+	func(args *ParsedParams) (Function, error) {
+		segments := args.Raw()
+		return ClosureFunction("function path_join", func(ctx FunctionContext) (any, error) {
+			return pathJoin(segments)
+		}, nil), nil
+	},
+)
+
+func pathJoin(segments []any) (string, error) {
+	var parts []string
+	var leadingSlash bool
+	for _, seg := range segments {
+		var str string
+		switch t := seg.(type) {
+		case nil:
+			continue
+		case string, []byte, int64, uint64, float64, json.Number:
+			str = IToString(t)
+		default:
+			return "", NewTypeError(seg, ValueString, ValueNumber)
+		}
+		if len(parts) == 0 && strings.HasPrefix(str, "/") {
+			leadingSlash = true
+		}
+		for _, p := range strings.Split(str, "/") {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+	}
+	joined := strings.Join(parts, "/")
+	if leadingSlash {
+		joined = "/" + joined
+	}
+	return joined, nil
+}
+
+//------------------------------------------------------------------------------
+
 var _ = registerFunction(
 	NewFunctionSpec(
 		FunctionCategoryGeneral, "random_int",
@@ -81,6 +81,20 @@ func TestFunctions(t *testing.T) {
 			vars:  map[string]any{},
 			err:   `variable 'foo' undefined`,
 		},
+		"check content_hash function": {
+			input:  mustFunc("content_hash"),
+			output: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			messages: []easyMsg{
+				{content: "hello world"},
+			},
+		},
+		"check content_hash function md5": {
+			input:  mustFunc("content_hash", "md5"),
+			output: "5eb63bbbe01eeed093cb22bb8f5acdc3",
+			messages: []easyMsg{
+				{content: "hello world"},
+			},
+		},
 		"check meta function object": {
 			input:  mustFunc("meta", "foo"),
 			output: "foobar",
@@ -113,6 +127,19 @@ func TestFunctions(t *testing.T) {
 				}},
 			},
 		},
+		"check path_join function": {
+			input:  mustFunc("path_join", "foo/", mustFunc("meta", "dir"), nil, "/bar.txt"),
+			output: "foo/a/b/bar.txt",
+			messages: []easyMsg{
+				{content: "", meta: map[string]any{
+					"dir": "/a//b/",
+				}},
+			},
+		},
+		"check path_join function leading slash": {
+			input:  mustFunc("path_join", "/logs/", int64(2026), "", "x.json"),
+			output: "/logs/2026/x.json",
+		},
 		"check range start > end": {
 			input: mustFunc("range", mustFunc("var", "start"), 0, 1),
 			vars: map[string]any{
@@ -72,8 +72,11 @@ func (s *BufferSet) Init(conf buffer.Config, mgr NewManagement) (buffer.Streamed
 		return nil, component.ErrInvalidType("buffer", conf.Type)
 	}
 	c, err := spec.constructor(conf, mgr)
-	err = wrapComponentErr(mgr, "buffer", err)
-	return c, err
+	if err != nil {
+		return nil, wrapComponentErr(mgr, "buffer", err)
+	}
+	warnExperimental(mgr, spec.spec)
+	return c, nil
 }
 
 // Docs returns a slice of buffer specs, which document each method.
@@ -72,8 +72,11 @@ func (s *CacheSet) Init(conf cache.Config, mgr NewManagement) (cache.V1, error)
 		return nil, component.ErrInvalidType("cache", conf.Type)
 	}
 	c, err := spec.constructor(conf, mgr)
-	err = wrapComponentErr(mgr, "cache", err)
-	return c, err
+	if err != nil {
+		return nil, wrapComponentErr(mgr, "cache", err)
+	}
+	warnExperimental(mgr, spec.spec)
+	return c, nil
 }
 
 // Docs returns a slice of cache specs, which document each method.
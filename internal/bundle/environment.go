@@ -92,6 +92,40 @@ func (e *Environment) GetDocs(name string, ctype docs.Type) (docs.ComponentSpec,
 	return spec, ok
 }
 
+// ComponentNamesByCategory returns the names of all components within the
+// environment keyed by their documented categories and then their type. A
+// component with multiple categories is listed under each of them, and
+// components without categories are listed under an empty category. Names are
+// sorted alphabetically.
+func (e *Environment) ComponentNamesByCategory() map[string]map[docs.Type][]string {
+	categories := map[string]map[docs.Type][]string{}
+	add := func(specs []docs.ComponentSpec) {
+		for _, spec := range specs {
+			cats := spec.Categories
+			if len(cats) == 0 {
+				cats = []string{""}
+			}
+			for _, cat := range cats {
+				types, exists := categories[cat]
+				if !exists {
+					types = map[docs.Type][]string{}
+					categories[cat] = types
+				}
+				types[spec.Type] = append(types[spec.Type], spec.Name)
+			}
+		}
+	}
+	add(e.buffers.Docs())
+	add(e.caches.Docs())
+	add(e.inputs.Docs())
+	add(e.outputs.Docs())
+	add(e.processors.Docs())
+	add(e.rateLimits.Docs())
+	add(e.metrics.Docs())
+	add(e.tracers.Docs())
+	return categories
+}
+
 // GlobalEnvironment contains service-wide singleton bundles.
 var GlobalEnvironment = &Environment{
 	buffers:    AllBuffers,
@@ -0,0 +1,104 @@
+package bundle_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestEnvironmentComponentNamesByCategory(t *testing.T) {
+	env := bundle.NewEnvironment()
+
+	require.NoError(t, env.InputAdd(nil, docs.ComponentSpec{
+		Name:       "testcategoriesinputb",
+		Categories: []string{"Services"},
+	}))
+	require.NoError(t, env.InputAdd(nil, docs.ComponentSpec{
+		Name:       "testcategoriesinputa",
+		Categories: []string{"Services", "Network"},
+	}))
+	require.NoError(t, env.ProcessorAdd(nil, docs.ComponentSpec{
+		Name:       "testcategoriesprocessor",
+		Categories: []string{"Mapping"},
+	}))
+	require.NoError(t, env.CacheAdd(nil, docs.ComponentSpec{
+		Name: "testcategoriescache",
+	}))
+
+	assert.Equal(t, map[string]map[docs.Type][]string{
+		"Services": {
+			docs.TypeInput: {"testcategoriesinputa", "testcategoriesinputb"},
+		},
+		"Network": {
+			docs.TypeInput: {"testcategoriesinputa"},
+		},
+		"Mapping": {
+			docs.TypeProcessor: {"testcategoriesprocessor"},
+		},
+		"": {
+			docs.TypeCache: {"testcategoriescache"},
+		},
+	}, env.ComponentNamesByCategory())
+}
+
+func TestEnvironmentExperimentalWarning(t *testing.T) {
+	env := bundle.NewEnvironment()
+
+	ctor := func(conf processor.Config, mgr bundle.NewManagement) (processor.V1, error) {
+		if conf.Plugin == "fail" {
+			return nil, errors.New("nope")
+		}
+		return nil, nil
+	}
+	require.NoError(t, env.ProcessorAdd(ctor, docs.ComponentSpec{
+		Name:                "testexperimentalwarna",
+		Status:              docs.StatusExperimental,
+		ExperimentalWarning: true,
+	}))
+	require.NoError(t, env.ProcessorAdd(ctor, docs.ComponentSpec{
+		Name:   "testexperimentalwarnb",
+		Status: docs.StatusExperimental,
+	}))
+
+	logConf := log.NewConfig()
+	logConf.AddTimeStamp = false
+	logConf.Format = "logfmt"
+	logConf.LogLevel = "WARN"
+
+	var buf bytes.Buffer
+	logger, err := log.NewV2(&buf, logConf)
+	require.NoError(t, err)
+
+	mgr := mock.NewManager()
+	mgr.L = logger
+
+	conf := processor.NewConfig()
+	conf.Type = "testexperimentalwarna"
+	conf.Plugin = "fail"
+	_, err = env.ProcessorInit(conf, mgr)
+	require.Error(t, err)
+	assert.Empty(t, buf.String())
+
+	conf.Plugin = nil
+	for i := 0; i < 3; i++ {
+		_, err = env.ProcessorInit(conf, mgr)
+		require.NoError(t, err)
+	}
+
+	conf.Type = "testexperimentalwarnb"
+	_, err = env.ProcessorInit(conf, mgr)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "is experimental"))
+	assert.Contains(t, buf.String(), "The testexperimentalwarna processor is experimental")
+}
@@ -71,8 +71,11 @@ func (s *InputSet) Init(conf input.Config, mgr NewManagement) (input.Streamed, e
 		return nil, component.ErrInvalidType("input", conf.Type)
 	}
 	c, err := spec.constructor(conf, mgr)
-	err = wrapComponentErr(mgr, "input", err)
-	return c, err
+	if err != nil {
+		return nil, wrapComponentErr(mgr, "input", err)
+	}
+	warnExperimental(mgr, spec.spec)
+	return c, nil
 }
 
 // Docs returns a slice of input specs, which document each method.
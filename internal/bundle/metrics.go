@@ -77,6 +77,7 @@ func (s *MetricsSet) Init(conf metrics.Config, nm NewManagement) (*metrics.Names
 	if err != nil {
 		return nil, err
 	}
+	warnExperimental(nm, spec.spec)
 
 	ns := metrics.NewNamespaced(m)
 	if conf.Mapping != "" {
@@ -81,8 +81,11 @@ func (s *OutputSet) Init(
 		return nil, component.ErrInvalidType("output", conf.Type)
 	}
 	c, err := spec.constructor(conf, mgr, pipelines...)
-	err = wrapComponentErr(mgr, "output", err)
-	return c, err
+	if err != nil {
+		return nil, wrapComponentErr(mgr, "output", err)
+	}
+	warnExperimental(mgr, spec.spec)
+	return c, nil
 }
 
 // Docs returns a slice of output specs, which document each method.
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sync"
 
 	"go.opentelemetry.io/otel/trace"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/filepath/ifs"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -62,22 +64,27 @@ type NewManagement interface {
 	ProbeCache(name string) bool
 	AccessCache(ctx context.Context, name string, fn func(cache.V1)) error
 	StoreCache(ctx context.Context, name string, conf cache.Config) error
+	RemoveCache(ctx context.Context, name string) error
 
 	ProbeInput(name string) bool
 	AccessInput(ctx context.Context, name string, fn func(input.Streamed)) error
 	StoreInput(ctx context.Context, name string, conf input.Config) error
+	RemoveInput(ctx context.Context, name string) error
 
 	ProbeProcessor(name string) bool
 	AccessProcessor(ctx context.Context, name string, fn func(processor.V1)) error
 	StoreProcessor(ctx context.Context, name string, conf processor.Config) error
+	RemoveProcessor(ctx context.Context, name string) error
 
 	ProbeOutput(name string) bool
 	AccessOutput(ctx context.Context, name string, fn func(output.Sync)) error
 	StoreOutput(ctx context.Context, name string, conf output.Config) error
+	RemoveOutput(ctx context.Context, name string) error
 
 	ProbeRateLimit(name string) bool
 	AccessRateLimit(ctx context.Context, name string, fn func(ratelimit.V1)) error
 	StoreRateLimit(ctx context.Context, name string, conf ratelimit.Config) error
+	RemoveRateLimit(ctx context.Context, name string) error
 
 	GetPipe(name string) (<-chan message.Transaction, error)
 	SetPipe(name string, t <-chan message.Transaction)
@@ -98,3 +105,19 @@ func wrapComponentErr(mgr NewManagement, typeStr string, err error) error {
 	}
 	return fmt.Errorf("failed to init %v %v: %w", typeStr, annotation, err)
 }
+
+// experimentalWarned tracks the experimental components that have already
+// logged a warning, keyed by their type and name.
+var experimentalWarned sync.Map
+
+// warnExperimental logs a warning the first time a component that is marked as
+// experimental with ExperimentalWarning is constructed.
+func warnExperimental(mgr NewManagement, spec docs.ComponentSpec) {
+	if !spec.ExperimentalWarning || spec.Status != docs.StatusExperimental {
+		return
+	}
+	if _, warned := experimentalWarned.LoadOrStore(string(spec.Type)+"."+spec.Name, struct{}{}); warned {
+		return
+	}
+	mgr.Logger().Warnf("The %v %v is experimental and may change in backwards incompatible ways", spec.Name, spec.Type)
+}
@@ -74,8 +74,11 @@ func (s *ProcessorSet) Init(conf processor.Config, mgr NewManagement) (processor
 		return nil, component.ErrInvalidType("processor", conf.Type)
 	}
 	c, err := spec.constructor(conf, mgr)
-	err = wrapComponentErr(mgr, "processor", err)
-	return c, err
+	if err != nil {
+		return nil, wrapComponentErr(mgr, "processor", err)
+	}
+	warnExperimental(mgr, spec.spec)
+	return c, nil
 }
 
 // Docs returns a slice of processor specs, which document each method.
@@ -72,8 +72,11 @@ func (s *RateLimitSet) Init(conf ratelimit.Config, mgr NewManagement) (ratelimit
 		return nil, component.ErrInvalidType("rate_limit", conf.Type)
 	}
 	c, err := spec.constructor(conf, mgr)
-	err = wrapComponentErr(mgr, "rate_limit", err)
-	return c, err
+	if err != nil {
+		return nil, wrapComponentErr(mgr, "rate_limit", err)
+	}
+	warnExperimental(mgr, spec.spec)
+	return c, nil
 }
 
 // Docs returns a slice of ratelimit specs, which document each method.
@@ -73,7 +73,12 @@ func (s *TracerSet) Init(conf tracer.Config, nm NewManagement) (trace.TracerProv
 	if !exists {
 		return nil, component.ErrInvalidType("tracer", conf.Type)
 	}
-	return spec.constructor(conf, nm)
+	t, err := spec.constructor(conf, nm)
+	if err != nil {
+		return nil, err
+	}
+	warnExperimental(nm, spec.spec)
+	return t, nil
 }
 
 // Docs returns a slice of tracer specs, which document each method.
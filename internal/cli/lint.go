@@ -150,6 +150,16 @@ files with the .yaml or .yml extension.`[1:],
 				Value: false,
 				Usage: "Print linting errors when components do not have labels.",
 			},
+			&cli.BoolFlag{
+				Name:  "interpolation",
+				Value: false,
+				Usage: "Print linting warnings for interpolations that may resolve to malformed values, such as object keys with stray slashes.",
+			},
+			&cli.BoolFlag{
+				Name:  "secrets",
+				Value: false,
+				Usage: "Print linting warnings for secret fields that are set to plaintext values rather than environment variables.",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			targets, err := ifilepath.GlobsAndSuperPaths(ifs.OS(), c.Args().Slice(), "yaml", "yml")
@@ -162,8 +172,10 @@ files with the .yaml or .yml extension.`[1:],
 			}
 
 			lintOpts := config.LintOptions{
-				RejectDeprecated: c.Bool("deprecated"),
-				RequireLabels:    c.Bool("labels"),
+				RejectDeprecated:    c.Bool("deprecated"),
+				RequireLabels:       c.Bool("labels"),
+				StrictInterpolation: c.Bool("interpolation"),
+				PlaintextSecrets:    c.Bool("secrets"),
 			}
 
 			var pathLintMut sync.Mutex
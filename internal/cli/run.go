@@ -146,7 +146,7 @@ func Run() {
 		&cli.StringSliceFlag{
 			Name:    "resources",
 			Aliases: []string{"r"},
-			Usage:   "pull in extra resources from a file, which can be referenced the same as resources defined in the main config, supports glob patterns (requires quotes)",
+			Usage:   "pull in extra resources from a file or an HTTP(S) URL, which can be referenced the same as resources defined in the main config, supports glob patterns (requires quotes)",
 		},
 		&cli.StringSliceFlag{
 			Name:    "templates",
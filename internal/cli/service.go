@@ -82,6 +82,9 @@ func initStreamsMode(
 		os.Exit(1)
 	}
 
+	labelErrs, labelWarnings := confReader.LintResourceLabels()
+	lints = append(lints, labelErrs...)
+
 	for _, lint := range lints {
 		if strict {
 			logger.With("lint", lint).Errorln("Config lint error")
@@ -89,16 +92,17 @@ func initStreamsMode(
 			logger.With("lint", lint).Warnln("Config lint error")
 		}
 	}
+	for _, lint := range labelWarnings {
+		logger.With("lint", lint).Warnln("Config lint warning")
+	}
 	if strict && len(lints) > 0 {
 		logger.Errorln("Shutting down due to stream linter errors, to prevent shutdown run Benthos with --chilled")
 		os.Exit(1)
 	}
 
-	for id, conf := range streamConfs {
-		if err := streamMgr.Create(id, conf); err != nil {
-			logger.Errorf("Failed to create stream (%v): %v\n", id, err)
-			os.Exit(1)
-		}
+	if err := streamMgr.Reconcile(context.Background(), streamConfs); err != nil {
+		logger.Errorf("Failed to create streams: %v\n", err)
+		os.Exit(1)
 	}
 	logger.Infoln("Launching benthos in streams mode, use CTRL+C to close")
 
@@ -243,6 +247,15 @@ func cmdService(
 		return 1
 	}
 
+	// Resource labels are checked once stream configs have also been read when
+	// in streams mode.
+	var labelWarnings []string
+	if !streamsMode {
+		var labelErrs []string
+		labelErrs, labelWarnings = confReader.LintResourceLabels()
+		lints = append(lints, labelErrs...)
+	}
+
 	if len(overrideLogLevel) > 0 {
 		conf.Logger.LogLevel = strings.ToUpper(overrideLogLevel)
 	}
@@ -301,6 +314,9 @@ func cmdService(
 			logger.With("lint", lint).Warnln("Config lint error")
 		}
 	}
+	for _, lint := range labelWarnings {
+		logger.With("lint", lint).Warnln("Config lint warning")
+	}
 	if strict && len(lints) > 0 {
 		logger.Errorln("Shutting down due to linter errors, to prevent shutdown run Benthos with --chilled")
 		return 1
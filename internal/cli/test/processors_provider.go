@@ -253,6 +253,13 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
 	}
 
+	// Track the origin of each resource so that label collisions can name both
+	// of the files involved.
+	origins := map[manager.ResourceLabel]string{}
+	for _, l := range mgrWrapper.ResourceLabels() {
+		origins[l] = targetPath
+	}
+
 	for _, path := range p.resourcesPaths {
 		resourceBytes, _, err := config.ReadFileEnvSwap(path)
 		if err != nil {
@@ -262,8 +269,8 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		if err = yaml.Unmarshal(resourceBytes, &extraMgrWrapper); err != nil {
 			return confs, fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
 		}
-		if err = mgrWrapper.AddFrom(&extraMgrWrapper); err != nil {
-			return confs, fmt.Errorf("failed to merge resources from '%v': %v", path, err)
+		if err = config.AddResourcesFrom(&mgrWrapper, &extraMgrWrapper, path, origins); err != nil {
+			return confs, err
 		}
 	}
 
@@ -2,6 +2,7 @@ package test_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -406,5 +407,8 @@ pipeline:
 		}),
 	)
 	_, err = provider.Provide("/pipeline/processors", nil, nil)
-	require.EqualError(t, err, "failed to initialise resources: cache resource label 'barcache' collides with a previously defined resource")
+	require.EqualError(t, err, fmt.Sprintf(
+		"cache resource label barcache defined in %v collides with the same label defined in %v",
+		filepath.Join(testDir, "resources2.yaml"), filepath.Join(testDir, "resources1.yaml"),
+	))
 }
@@ -163,7 +163,7 @@ func (m *Stream) outputLoop() {
 	for {
 		msg, ackFunc, err := m.buffer.Read(closeNowCtx)
 		if err != nil {
-			if err != component.ErrTypeClosed && !errors.Is(err, context.Canceled) {
+			if !errors.Is(err, component.ErrTypeClosed) && !errors.Is(err, context.Canceled) {
 				m.log.Errorf("Failed to read buffer: %v\n", err)
 				if !m.errThrottle.Retry() {
 					return
@@ -204,7 +204,7 @@ func (m *Stream) outputLoop() {
 				mLatency.Timing(time.Since(startedAt).Nanoseconds())
 				tracing.FinishSpans(msg)
 				if ackErr := ackFunc(closeNowCtx, res); ackErr != nil {
-					if ackErr != component.ErrTypeClosed {
+					if !errors.Is(ackErr, component.ErrTypeClosed) {
 						m.log.Errorf("Failed to ack buffer message: %v\n", ackErr)
 					}
 				}
@@ -3,7 +3,10 @@ package component
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ErrNotUnwrapped is returned in cases where a component was meant to be
@@ -37,6 +40,12 @@ var (
 	ErrTimeout    = errors.New("action timed out")
 	ErrTypeClosed = errors.New("type was closed")
 
+	// ErrShuttingDown is returned by components that are permanently closing,
+	// as opposed to ErrTimeout which only indicates that no data was available
+	// in time. It matches ErrTypeClosed with errors.Is, and should therefore be
+	// checked for with errors.Is rather than compared directly.
+	ErrShuttingDown = fmt.Errorf("%w: shutting down", ErrTypeClosed)
+
 	ErrNotConnected = errors.New("not connected to target source or sink")
 
 	// ErrAlreadyStarted is returned when an input or output type gets started a
@@ -69,6 +78,24 @@ var (
 	ErrMessageTooLarge = errors.New("message body larger than buffer space")
 )
 
+// ErrMessageExceedsLimit is an error returned when the body of a message is
+// larger than the configured limit of a component. It matches
+// ErrMessageTooLarge with errors.Is.
+type ErrMessageExceedsLimit struct {
+	Size  int
+	Limit int
+}
+
+// Error returns the Error string.
+func (e ErrMessageExceedsLimit) Error() string {
+	return fmt.Sprintf("message body of %v bytes exceeds the limit of %v bytes", e.Size, e.Limit)
+}
+
+// Is returns true when the target is ErrMessageTooLarge.
+func (e ErrMessageExceedsLimit) Is(target error) bool {
+	return target == ErrMessageTooLarge
+}
+
 //------------------------------------------------------------------------------
 
 // ErrUnexpectedHTTPRes is an error returned when an HTTP request returned an
@@ -77,6 +104,10 @@ type ErrUnexpectedHTTPRes struct {
 	Code int
 	S    string
 	Body []byte
+
+	// RetryAfterHeader is the value of the Retry-After header of the response,
+	// or empty when the header was not set.
+	RetryAfterHeader string
 }
 
 // Error returns the Error string.
@@ -84,3 +115,76 @@ func (e ErrUnexpectedHTTPRes) Error() string {
 	body := strings.ReplaceAll(string(e.Body), "\n", "")
 	return fmt.Sprintf("HTTP request returned unexpected response code (%v): %v, Error: %v", e.Code, e.S, body)
 }
+
+// IsRetryable returns whether the request might succeed if attempted again,
+// which is the case when it was throttled or failed due to a server error.
+func (e ErrUnexpectedHTTPRes) IsRetryable() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+// RetryAfter parses the value of the Retry-After header that accompanied the
+// response, which is either a number of seconds or an HTTP date, and returns
+// the period to wait before retrying the request. Returns false if the header
+// is empty or invalid.
+func (e ErrUnexpectedHTTPRes) RetryAfter() (time.Duration, bool) {
+	header := strings.TrimSpace(e.RetryAfterHeader)
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(header, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(t); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+//------------------------------------------------------------------------------
+
+// ErrObjectStorage is an error returned when an object could not be written to
+// an object storage bucket.
+type ErrObjectStorage struct {
+	Bucket string
+	Key    string
+
+	// StatusCode is the HTTP status code of the response to the request, or
+	// zero when no response was received.
+	StatusCode int
+
+	Err error
+}
+
+// Error returns the Error string.
+func (e ErrObjectStorage) Error() string {
+	return fmt.Sprintf("failed to put object (%v) to bucket (%v): %v", e.Key, e.Bucket, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrObjectStorage) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable returns whether the failed write might succeed if attempted
+// again. Requests that received no response, timed out, were throttled or
+// failed due to a server error are considered retryable, whereas any other
+// response indicates a permanent failure such as a missing bucket or
+// insufficient permissions.
+func (e ErrObjectStorage) IsRetryable() bool {
+	switch {
+	case e.StatusCode == 0:
+		return true
+	case e.StatusCode == http.StatusRequestTimeout, e.StatusCode == http.StatusTooManyRequests:
+		return true
+	case e.StatusCode >= 500:
+		return true
+	}
+	return false
+}
@@ -1,6 +1,12 @@
 package component
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
 
 func TestHTTPError(t *testing.T) {
 	err := ErrUnexpectedHTTPRes{
@@ -14,3 +20,84 @@ func TestHTTPError(t *testing.T) {
 		t.Errorf("Wrong Error() from ErrUnexpectedHTTPRes: %v != %v", exp, act)
 	}
 }
+
+func TestHTTPErrorRetryable(t *testing.T) {
+	for code, exp := range map[int]bool{
+		200: false,
+		400: false,
+		404: false,
+		408: false,
+		429: true,
+		500: true,
+		502: true,
+	} {
+		if act := (ErrUnexpectedHTTPRes{Code: code}).IsRetryable(); act != exp {
+			t.Errorf("Wrong IsRetryable() for code %v: %v != %v", code, act, exp)
+		}
+	}
+}
+
+func TestHTTPErrorRetryAfter(t *testing.T) {
+	retryAfter := func(header string) (time.Duration, bool) {
+		return ErrUnexpectedHTTPRes{Code: 429, RetryAfterHeader: header}.RetryAfter()
+	}
+
+	if d, ok := retryAfter("120"); !ok || d != 2*time.Minute {
+		t.Errorf("Wrong RetryAfter() for seconds: %v, %v", d, ok)
+	}
+	if d, ok := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || d <= 59*time.Minute || d > time.Hour {
+		t.Errorf("Wrong RetryAfter() for future date: %v, %v", d, ok)
+	}
+	if d, ok := retryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)); !ok || d != 0 {
+		t.Errorf("Wrong RetryAfter() for past date: %v, %v", d, ok)
+	}
+	for _, header := range []string{"", "-5", "soon"} {
+		if _, ok := retryAfter(header); ok {
+			t.Errorf("Expected RetryAfter() to fail for %q", header)
+		}
+	}
+}
+
+func TestObjectStorageError(t *testing.T) {
+	inner := errors.New("nope")
+	err := ErrObjectStorage{
+		Bucket:     "foo",
+		Key:        "bar/baz.txt",
+		StatusCode: 403,
+		Err:        inner,
+	}
+
+	exp, act := `failed to put object (bar/baz.txt) to bucket (foo): nope`, err.Error()
+	if exp != act {
+		t.Errorf("Wrong Error() from ErrObjectStorage: %v != %v", exp, act)
+	}
+	if !errors.Is(err, inner) {
+		t.Error("Expected ErrObjectStorage to unwrap to the underlying error")
+	}
+
+	var wrapped error = fmt.Errorf("write failed: %w", err)
+	var osErr ErrObjectStorage
+	if !errors.As(wrapped, &osErr) {
+		t.Fatal("Expected errors.As to find ErrObjectStorage")
+	}
+	if osErr.Bucket != "foo" || osErr.Key != "bar/baz.txt" {
+		t.Errorf("Wrong bucket or key: %v, %v", osErr.Bucket, osErr.Key)
+	}
+}
+
+func TestObjectStorageErrorRetryable(t *testing.T) {
+	for code, exp := range map[int]bool{
+		0:   true,
+		400: false,
+		403: false,
+		404: false,
+		408: true,
+		429: true,
+		500: true,
+		503: true,
+	} {
+		if act := (ErrObjectStorage{StatusCode: code}).IsRetryable(); act != exp {
+			t.Errorf("Wrong IsRetryable() for status code %v: %v != %v", code, act, exp)
+		}
+	}
+}
@@ -32,12 +32,21 @@ type AsyncReader struct {
 	shutSig      *shutdown.Signaller
 }
 
+// AsyncReaderWithConnBackOff sets the backoff used for limiting the attempts to
+// connect an AsyncReader, and for pausing between failed reads.
+func AsyncReaderWithConnBackOff(boff backoff.BackOff) func(a *AsyncReader) {
+	return func(a *AsyncReader) {
+		a.connBackoff = boff
+	}
+}
+
 // NewAsyncReader creates a new AsyncReader input type.
 func NewAsyncReader(
 	typeStr string,
 	allowSkipAcks bool,
 	r Async,
 	mgr component.Observability,
+	opts ...func(a *AsyncReader),
 ) (Streamed, error) {
 	boff := backoff.NewExponentialBackOff()
 	boff.InitialInterval = time.Millisecond * 100
@@ -53,6 +62,9 @@ func NewAsyncReader(
 		transactions:  make(chan message.Transaction),
 		shutSig:       shutdown.NewSignaller(),
 	}
+	for _, opt := range opts {
+		opt(rdr)
+	}
 
 	go rdr.loop()
 	return rdr, nil
@@ -141,7 +153,7 @@ func (r *AsyncReader) loop() {
 		}
 
 		if err != nil || msg == nil {
-			if err != nil && err != component.ErrTimeout && err != component.ErrNotConnected {
+			if err != nil && !errors.Is(err, component.ErrTimeout) && !errors.Is(err, component.ErrNotConnected) {
 				r.mgr.Logger().Errorf("Failed to read message: %v\n", err)
 			}
 			select {
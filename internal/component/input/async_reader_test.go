@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -127,6 +128,35 @@ func TestAsyncReaderCantConnect(t *testing.T) {
 	require.NoError(t, r.WaitForClose(context.Background()))
 }
 
+type countingBackOff struct {
+	calls int32
+}
+
+func (b *countingBackOff) NextBackOff() time.Duration {
+	atomic.AddInt32(&b.calls, 1)
+	return time.Hour
+}
+
+func (b *countingBackOff) Reset() {}
+
+func TestAsyncReaderConnBackOff(t *testing.T) {
+	boff := &countingBackOff{}
+	r, err := input.NewAsyncReader("foo", true, asyncReaderCantConnect{}, mock.NewManager(), input.AsyncReaderWithConnBackOff(boff))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&boff.calls) == 1
+	}, time.Second, time.Millisecond)
+
+	// The backoff should be abandoned when the reader is closed.
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	r.TriggerStopConsuming()
+	require.NoError(t, r.WaitForClose(ctx))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&boff.calls))
+}
+
 //------------------------------------------------------------------------------
 
 type asyncReaderCantRead struct {
@@ -1,29 +1,67 @@
 package input
 
 import (
+	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
 	btls "github.com/benthosdev/benthos/v4/internal/tls"
 )
 
 // NSQConfig contains configuration fields for the NSQ input type.
 type NSQConfig struct {
-	Addresses       []string    `json:"nsqd_tcp_addresses" yaml:"nsqd_tcp_addresses"`
-	LookupAddresses []string    `json:"lookupd_http_addresses" yaml:"lookupd_http_addresses"`
-	Topic           string      `json:"topic" yaml:"topic"`
-	Channel         string      `json:"channel" yaml:"channel"`
-	UserAgent       string      `json:"user_agent" yaml:"user_agent"`
-	TLS             btls.Config `json:"tls" yaml:"tls"`
-	MaxInFlight     int         `json:"max_in_flight" yaml:"max_in_flight"`
+	Addresses           []string           `json:"nsqd_tcp_addresses" yaml:"nsqd_tcp_addresses"`
+	LookupAddresses     []string           `json:"lookupd_http_addresses" yaml:"lookupd_http_addresses"`
+	Mode                string             `json:"mode" yaml:"mode"`
+	Topic               string             `json:"topic" yaml:"topic"`
+	Topics              []string           `json:"topics" yaml:"topics"`
+	Channel             string             `json:"channel" yaml:"channel"`
+	UserAgent           string             `json:"user_agent" yaml:"user_agent"`
+	AuthSecret          string             `json:"auth_secret" yaml:"auth_secret"`
+	TLS                 btls.Config        `json:"tls" yaml:"tls"`
+	MaxInFlight         int                `json:"max_in_flight" yaml:"max_in_flight"`
+	MaxAttempts         int                `json:"max_attempts" yaml:"max_attempts"`
+	DialTimeout         string             `json:"dial_timeout" yaml:"dial_timeout"`
+	ReadTimeout         string             `json:"read_timeout" yaml:"read_timeout"`
+	WriteTimeout        string             `json:"write_timeout" yaml:"write_timeout"`
+	LookupdPollInterval string             `json:"lookupd_poll_interval" yaml:"lookupd_poll_interval"`
+	SampleRate          int                `json:"sample_rate" yaml:"sample_rate"`
+	DrainTimeout        string             `json:"drain_timeout" yaml:"drain_timeout"`
+	ConnectionBackoff   NSQBackoffConfig   `json:"connection_backoff" yaml:"connection_backoff"`
+	Batching            batchconfig.Config `json:"batching" yaml:"batching"`
+	DeadLetter          *output.Config     `json:"dead_letter,omitempty" yaml:"dead_letter,omitempty"`
+}
+
+// NSQBackoffConfig contains configuration fields for the backoff between
+// failed connection attempts of the NSQ input type.
+type NSQBackoffConfig struct {
+	InitialInterval string `json:"initial_interval" yaml:"initial_interval"`
+	MaxInterval     string `json:"max_interval" yaml:"max_interval"`
 }
 
 // NewNSQConfig creates a new NSQConfig with default values.
 func NewNSQConfig() NSQConfig {
 	return NSQConfig{
-		Addresses:       []string{},
-		LookupAddresses: []string{},
-		Topic:           "",
-		Channel:         "",
-		UserAgent:       "",
-		TLS:             btls.NewConfig(),
-		MaxInFlight:     100,
+		Addresses:           []string{},
+		LookupAddresses:     []string{},
+		Mode:                "both",
+		Topic:               "",
+		Topics:              []string{},
+		Channel:             "",
+		UserAgent:           "",
+		AuthSecret:          "",
+		TLS:                 btls.NewConfig(),
+		MaxInFlight:         100,
+		MaxAttempts:         5,
+		DialTimeout:         "1s",
+		ReadTimeout:         "60s",
+		WriteTimeout:        "1s",
+		LookupdPollInterval: "60s",
+		SampleRate:          0,
+		DrainTimeout:        "5s",
+		Batching:            batchconfig.NewConfig(),
+		ConnectionBackoff: NSQBackoffConfig{
+			InitialInterval: "1s",
+			MaxInterval:     "30s",
+		},
+		DeadLetter: nil,
 	}
 }
@@ -64,9 +64,16 @@ func (m *Impl) loop() {
 	closeNowCtx, cnDone := m.shutSig.CloseNowCtx(context.Background())
 	defer cnDone()
 
+	var closing, flushedFinal bool
 	defer func() {
 		close(m.messagesOut)
 
+		// When a final batch was flushed as the input closed, and unless we've
+		// been instructed to close immediately, the child is given the
+		// opportunity to finish writing it before it is forced to close.
+		if flushedFinal && !m.shutSig.ShouldCloseNow() {
+			_ = m.child.WaitForClose(closeNowCtx)
+		}
 		m.child.TriggerCloseNow()
 		_ = m.child.WaitForClose(context.Background())
 
@@ -95,6 +102,7 @@ func (m *Impl) loop() {
 				if flushBatch = m.batcher.Count() > 0; !flushBatch {
 					return
 				}
+				closing = true
 
 				// If we're waiting for a timed batch then we will respect it.
 				if nextTimedBatchChan != nil {
@@ -132,6 +140,7 @@ func (m *Impl) loop() {
 		resChan := make(chan error)
 		select {
 		case m.messagesOut <- message.NewTransaction(sendMsg, resChan):
+			flushedFinal = closing
 		case <-m.shutSig.CloseAtLeisureChan():
 			return
 		}
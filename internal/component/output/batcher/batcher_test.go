@@ -15,6 +15,7 @@ import (
 	batchInternal "github.com/benthosdev/benthos/v4/internal/batch"
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/batch/policy/batchconfig"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/output/batcher"
 	"github.com/benthosdev/benthos/v4/internal/manager/mock"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -399,3 +400,183 @@ func TestBatcherTimed(t *testing.T) {
 
 	close(resChan)
 }
+
+type gracefulOutput struct {
+	ignoreClosed bool
+
+	release   chan struct{}
+	closeNow  chan struct{}
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newGracefulOutput() *gracefulOutput {
+	return &gracefulOutput{
+		release:  make(chan struct{}),
+		closeNow: make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (g *gracefulOutput) Consume(msgs <-chan message.Transaction) error {
+	go func() {
+		defer close(g.closed)
+		for {
+			var tran message.Transaction
+			var open bool
+			select {
+			case tran, open = <-msgs:
+				if !open {
+					if g.ignoreClosed {
+						<-g.closeNow
+					}
+					return
+				}
+			case <-g.closeNow:
+				return
+			}
+			select {
+			case <-g.release:
+				_ = tran.Ack(context.Background(), nil)
+			case <-g.closeNow:
+				// The batcher no longer waits on the result once closed.
+				ackCtx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_ = tran.Ack(ackCtx, errors.New("forced to close"))
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (g *gracefulOutput) Connected() bool {
+	return true
+}
+
+func (g *gracefulOutput) TriggerCloseNow() {
+	g.closeOnce.Do(func() {
+		close(g.closeNow)
+	})
+}
+
+func (g *gracefulOutput) WaitForClose(ctx context.Context) error {
+	select {
+	case <-g.closed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestBatcherFlushOnClose(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	tInChan := make(chan message.Transaction)
+	resChan := make(chan error, 1)
+
+	stats := metrics.NewLocal()
+	mgr := mock.NewManager()
+	mgr.M = stats
+
+	policyConf := batchconfig.NewConfig()
+	policyConf.Count = 10
+	batchPol, err := policy.New(policyConf, mgr)
+	require.NoError(t, err)
+
+	out := newGracefulOutput()
+
+	b := batcher.New(batchPol, out, mgr)
+	require.NoError(t, b.Consume(tInChan))
+
+	select {
+	case tInChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("foo")}), resChan):
+	case <-tCtx.Done():
+		t.Fatal("timed out")
+	}
+	assert.Eventually(t, func() bool {
+		return stats.GetCounters()["batch_pending"] == 1
+	}, time.Second*5, time.Millisecond*10)
+	close(tInChan)
+
+	// The partial batch is flushed and the batcher waits for the child to
+	// finish writing it rather than forcing it to close.
+	assert.Eventually(t, func() bool {
+		return stats.GetCounters()["batch_pending"] == 0
+	}, time.Second*5, time.Millisecond*10)
+	waitCtx, waitDone := context.WithTimeout(tCtx, time.Millisecond*100)
+	require.Error(t, b.WaitForClose(waitCtx))
+	waitDone()
+
+	close(out.release)
+
+	select {
+	case err := <-resChan:
+		require.NoError(t, err)
+	case <-tCtx.Done():
+		t.Fatal("timed out")
+	}
+	require.NoError(t, b.WaitForClose(tCtx))
+}
+
+func TestBatcherCloseWithoutFinalBatch(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	tInChan := make(chan message.Transaction)
+
+	policyConf := batchconfig.NewConfig()
+	policyConf.Count = 10
+	batchPol, err := policy.New(policyConf, mock.NewManager())
+	require.NoError(t, err)
+
+	// The child only closes once forced to, like an output that is unable to
+	// connect.
+	out := newGracefulOutput()
+	out.ignoreClosed = true
+
+	b := batcher.New(batchPol, out, mock.NewManager())
+	require.NoError(t, b.Consume(tInChan))
+
+	// Without a final batch to write the child is closed without waiting.
+	close(tInChan)
+
+	waitCtx, waitDone := context.WithTimeout(tCtx, time.Second*5)
+	defer waitDone()
+	require.NoError(t, b.WaitForClose(waitCtx))
+}
+
+func TestBatcherFlushOnCloseNow(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	tInChan := make(chan message.Transaction)
+
+	policyConf := batchconfig.NewConfig()
+	policyConf.Count = 10
+	batchPol, err := policy.New(policyConf, mock.NewManager())
+	require.NoError(t, err)
+
+	// The child never finishes writing the final batch.
+	out := newGracefulOutput()
+
+	b := batcher.New(batchPol, out, mock.NewManager())
+	require.NoError(t, b.Consume(tInChan))
+
+	select {
+	case tInChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("foo")}), make(chan error, 1)):
+	case <-tCtx.Done():
+		t.Fatal("timed out")
+	}
+	close(tInChan)
+
+	waitCtx, waitDone := context.WithTimeout(tCtx, time.Millisecond*100)
+	require.Error(t, b.WaitForClose(waitCtx))
+	waitDone()
+
+	// Writing the final batch is still interrupted when the batcher is
+	// instructed to close immediately.
+	b.TriggerCloseNow()
+	require.NoError(t, b.WaitForClose(tCtx))
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"regexp"
 	"strings"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
 )
 
 var (
@@ -22,11 +25,42 @@ var (
 // the environment variable is empty or does not exist then either the default
 // value is used or the field will be left empty.
 func ReplaceEnvVariables(inBytes []byte) []byte {
+	return replaceEnvVariables(inBytes, nil)
+}
+
+// ReplaceEnvVariablesLinted behaves the same as ReplaceEnvVariables but also
+// returns a linting warning for each environment variable that is referenced
+// without a default value but is not set.
+func ReplaceEnvVariablesLinted(inBytes []byte) ([]byte, []docs.Lint) {
+	var lints []docs.Lint
+	replaced := replaceEnvVariables(inBytes, func(name string, offset int) {
+		line := 1 + bytes.Count(inBytes[:offset], []byte("\n"))
+		lints = append(lints, docs.NewLintWarning(line, docs.LintMissingEnvVar, fmt.Sprintf("environment variable %v referenced but not set", name)))
+	})
+	return replaced, lints
+}
+
+func replaceEnvVariables(inBytes []byte, onMissing func(name string, offset int)) []byte {
+	var offsets [][]int
+	if onMissing != nil {
+		offsets = envRegex.FindAllIndex(inBytes, -1)
+	}
+
+	i := 0
 	replaced := envRegex.ReplaceAllFunc(inBytes, func(content []byte) []byte {
+		matchIndex := i
+		i++
+
 		var value string
 		if len(content) > 3 {
 			if colonIndex := bytes.IndexByte(content, ':'); colonIndex == -1 {
-				value = os.Getenv(string(content[2 : len(content)-1]))
+				targetVar := string(content[2 : len(content)-1])
+
+				var exists bool
+				value, exists = os.LookupEnv(targetVar)
+				if !exists && onMissing != nil && matchIndex < len(offsets) {
+					onMissing(targetVar, offsets[matchIndex][0])
+				}
 			} else {
 				targetVar := content[2:colonIndex]
 				defaultVal := content[colonIndex+1 : len(content)-1]
@@ -3,6 +3,10 @@ package config
 import (
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
 )
 
 func TestEnvSwapping(t *testing.T) {
@@ -42,3 +46,24 @@ func TestEnvSwapping(t *testing.T) {
 		}
 	}
 }
+
+func TestEnvSwappingLinted(t *testing.T) {
+	os.Setenv("BENTHOS_TEST_LINTED_SET", "foo")
+	os.Unsetenv("BENTHOS_TEST_LINTED_UNSET")
+	os.Unsetenv("BENTHOS_TEST_LINTED_DEFAULTED")
+
+	out, lints := ReplaceEnvVariablesLinted([]byte(`a: ${BENTHOS_TEST_LINTED_SET}
+b: ${BENTHOS_TEST_LINTED_DEFAULTED:bar}
+c: ${{BENTHOS_TEST_LINTED_UNSET}}
+d: ${BENTHOS_TEST_LINTED_UNSET}
+`))
+
+	assert.Equal(t, `a: foo
+b: bar
+c: ${BENTHOS_TEST_LINTED_UNSET}
+d: 
+`, string(out))
+	assert.Equal(t, []docs.Lint{
+		docs.NewLintWarning(4, docs.LintMissingEnvVar, "environment variable BENTHOS_TEST_LINTED_UNSET referenced but not set"),
+	}, lints)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/internal/filepath/ifs"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// isGzipConfig returns true if a config is gzip compressed, which is detected
+// by either a .gz suffix of its path or the magic bytes of its contents.
+func isGzipConfig(path string, configBytes []byte) bool {
+	return strings.HasSuffix(path, ".gz") || bytes.HasPrefix(configBytes, gzipMagic)
+}
+
+// decompressConfig returns the decompressed contents of a config when it is
+// gzip compressed, and otherwise returns the contents unchanged.
+func decompressConfig(path string, configBytes []byte) ([]byte, error) {
+	if !isGzipConfig(path, configBytes) {
+		return configBytes, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(configBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress config: %w", err)
+	}
+	defer r.Close()
+
+	if configBytes, err = io.ReadAll(r); err != nil {
+		return nil, fmt.Errorf("failed to decompress config: %w", err)
+	}
+	return configBytes, nil
+}
+
+// readConfigFile reads the contents of a config file, which are transparently
+// decompressed when the file is gzip compressed.
+func readConfigFile(path string) ([]byte, error) {
+	configBytes, err := ifs.ReadFile(ifs.OS(), path)
+	if err != nil {
+		return nil, err
+	}
+	return decompressConfig(path, configBytes)
+}
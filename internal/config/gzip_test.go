@@ -0,0 +1,65 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/manager"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecompressConfig(t *testing.T) {
+	raw := []byte("foo: bar\n")
+	compressed := gzipBytes(t, raw)
+
+	b, err := decompressConfig("foo.yaml", raw)
+	require.NoError(t, err)
+	assert.Equal(t, raw, b)
+
+	b, err = decompressConfig("foo.yaml.gz", compressed)
+	require.NoError(t, err)
+	assert.Equal(t, raw, b)
+
+	b, err = decompressConfig("foo.yaml", compressed)
+	require.NoError(t, err)
+	assert.Equal(t, raw, b)
+
+	_, err = decompressConfig("foo.yaml.gz", raw)
+	require.Error(t, err)
+}
+
+func TestReadResourceGzip(t *testing.T) {
+	t.Setenv("TEST_GZIP_LABEL", "foo")
+
+	path := filepath.Join(t.TempDir(), "res.yaml.gz")
+	require.NoError(t, os.WriteFile(path, gzipBytes(t, []byte(`
+cache_resources:
+  - label: ${TEST_GZIP_LABEL}
+    memory: {}
+`)), 0o644))
+
+	conf := manager.NewResourceConfig()
+	lints, err := readResource(path, nil, &conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	require.Len(t, conf.ResourceCaches, 1)
+	assert.Equal(t, "foo", conf.ResourceCaches[0].Label)
+	assert.Equal(t, "memory", conf.ResourceCaches[0].Type)
+}
@@ -1,38 +1,58 @@
 package config
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
+	"strings"
 	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 
+	tdocs "github.com/benthosdev/benthos/v4/internal/cli/test/docs"
 	"github.com/benthosdev/benthos/v4/internal/docs"
-	"github.com/benthosdev/benthos/v4/internal/filepath/ifs"
+	"github.com/benthosdev/benthos/v4/internal/manager"
 )
 
 // LintOptions specifies the linters that will be enabled.
 type LintOptions struct {
-	RejectDeprecated bool
-	RequireLabels    bool
+	RejectDeprecated    bool
+	RequireLabels       bool
+	StrictInterpolation bool
+	PlaintextSecrets    bool
 }
 
 // ReadFileLinted will attempt to read a configuration file path into a
 // structure. Returns an array of lint messages or an error.
 func ReadFileLinted(path string, opts LintOptions, config *Type) ([]docs.Lint, error) {
-	configBytes, lints, err := ReadFileEnvSwap(path)
+	rawBytes, err := readConfigFile(path)
 	if err != nil {
 		return nil, err
 	}
 
+	configBytes, lints := envSwap(rawBytes, false)
 	if err := yaml.Unmarshal(configBytes, config); err != nil {
 		return nil, err
 	}
 
-	newLints, err := LintBytes(opts, configBytes)
+	// Secrets are linted separately against the config before environment
+	// variables are replaced, as otherwise it's not possible to distinguish
+	// them from plaintext values.
+	swappedOpts := opts
+	swappedOpts.PlaintextSecrets = false
+
+	newLints, err := LintBytes(swappedOpts, configBytes)
 	if err != nil {
 		return nil, err
 	}
 	lints = append(lints, newLints...)
+
+	if opts.PlaintextSecrets {
+		if newLints, err = lintPlaintextSecrets(rawBytes); err != nil {
+			return nil, err
+		}
+		lints = append(lints, newLints...)
+	}
 	return lints, nil
 }
 
@@ -51,20 +71,93 @@ func LintBytes(opts LintOptions, rawBytes []byte) ([]docs.Lint, error) {
 	lintCtx := docs.NewLintContext()
 	lintCtx.RejectDeprecated = opts.RejectDeprecated
 	lintCtx.RequireLabels = opts.RequireLabels
+	lintCtx.StrictInterpolation = opts.StrictInterpolation
+	lintCtx.PlaintextSecrets = opts.PlaintextSecrets
 
 	return Spec().LintYAML(lintCtx, &rawNode), nil
 }
 
+// lintPlaintextSecrets returns lints for secret fields that are set to
+// plaintext values within a config that has not had its environment variables
+// replaced.
+func lintPlaintextSecrets(rawBytes []byte) ([]docs.Lint, error) {
+	if bytes.HasPrefix(rawBytes, []byte("# BENTHOS LINT DISABLE")) {
+		return nil, nil
+	}
+
+	var rawNode yaml.Node
+	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
+		return nil, err
+	}
+
+	lintCtx := docs.NewLintContext()
+	lintCtx.PlaintextSecrets = true
+
+	var lints []docs.Lint
+	for _, l := range Spec().LintYAML(lintCtx, &rawNode) {
+		if l.Type == docs.LintPlaintextSecret {
+			lints = append(lints, l)
+		}
+	}
+	return lints, nil
+}
+
+// LintYAMLBytes lints a YAML config with a given lint context, where the config
+// is either a full stream config or, when resourcesOnly is true, a config
+// containing only resources. The config is linted in the same way as resource
+// files, and therefore linting can be disabled entirely or for specific lint
+// types with comment directives.
+func LintYAMLBytes(lintCtx docs.LintContext, configBytes []byte, resourcesOnly bool) ([]docs.Lint, error) {
+	if bytes.HasPrefix(configBytes, []byte("# BENTHOS LINT DISABLE")) {
+		return nil, nil
+	}
+
+	var rawNode yaml.Node
+	if err := yaml.Unmarshal(configBytes, &rawNode); err != nil {
+		return nil, err
+	}
+	return applyLintDirectives(configBytes, lintYAMLNode(lintCtx, &rawNode, resourcesOnly)), nil
+}
+
+func lintYAMLNode(lintCtx docs.LintContext, node *yaml.Node, resourcesOnly bool) []docs.Lint {
+	if resourcesOnly {
+		return append(docs.FieldSpecs{tdocs.ConfigSpec()}, manager.Spec()...).LintYAML(lintCtx, node)
+	}
+	return Spec().LintYAML(lintCtx, node)
+}
+
 // ReadFileEnvSwap reads a file and replaces any environment variable
-// interpolations before returning the contents. Linting errors are returned if
+// interpolations before returning the contents. Files that are gzip compressed,
+// either with a .gz extension or detected from their contents, are
+// decompressed first. Linting errors are returned if
 // the file has an unexpected higher level format, such as invalid utf-8
 // encoding.
 func ReadFileEnvSwap(path string) (configBytes []byte, lints []docs.Lint, err error) {
-	configBytes, err = ifs.ReadFile(ifs.OS(), path)
+	return readFileEnvSwap(path, false)
+}
+
+// ReadFileEnvSwapLintMissing behaves the same as ReadFileEnvSwap but also
+// returns linting warnings for environment variables that are referenced
+// without a default value but are not set.
+func ReadFileEnvSwapLintMissing(path string) (configBytes []byte, lints []docs.Lint, err error) {
+	return readFileEnvSwap(path, true)
+}
+
+func readFileEnvSwap(path string, lintMissing bool) (configBytes []byte, lints []docs.Lint, err error) {
+	configBytes, err = readConfigFile(path)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	configBytes, lints = envSwap(configBytes, lintMissing)
+	return configBytes, lints, nil
+}
+
+// envSwap replaces any environment variable interpolations within config
+// bytes, returning linting errors if the config has an unexpected higher level
+// format, and optionally warnings for missing environment variables.
+func envSwap(configBytes []byte, lintMissing bool) ([]byte, []docs.Lint) {
+	var lints []docs.Lint
 	if !utf8.Valid(configBytes) {
 		lints = append(lints, docs.NewLintError(
 			1, docs.LintFailedRead,
@@ -72,6 +165,58 @@ func ReadFileEnvSwap(path string) (configBytes []byte, lints []docs.Lint, err er
 		))
 	}
 
-	configBytes = ReplaceEnvVariables(configBytes)
-	return configBytes, lints, nil
+	if !lintMissing {
+		return ReplaceEnvVariables(configBytes), lints
+	}
+
+	configBytes, envLints := ReplaceEnvVariablesLinted(configBytes)
+	return configBytes, append(lints, envLints...)
+}
+
+const lintDisableDirective = "# benthos-lint-disable"
+
+// lintDisabledTypes parses any `# benthos-lint-disable <type>...` comment
+// directives within a config and returns the lint types they disable, which
+// may include lint types registered by plugins. Lints are returned for
+// directives that name unrecognised lint types.
+func lintDisabledTypes(configBytes []byte) (disabled map[docs.LintType]struct{}, lints []docs.Lint) {
+	disabled = map[docs.LintType]struct{}{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(configBytes))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, lintDisableDirective+" ") {
+			continue
+		}
+		for _, name := range strings.Fields(strings.TrimPrefix(text, lintDisableDirective)) {
+			t, err := docs.LintTypeFromString(name)
+			if err != nil {
+				lints = append(lints, docs.NewLintWarning(line, docs.LintCustom, fmt.Sprintf("unrecognised lint type %v in %v directive", name, lintDisableDirective)))
+				continue
+			}
+			disabled[t] = struct{}{}
+		}
+	}
+	return
+}
+
+// applyLintDirectives removes lints of the types disabled by comment directives
+// within a config, and adds lints for any unrecognised directives.
+func applyLintDirectives(configBytes []byte, lints []docs.Lint) []docs.Lint {
+	disabled, dLints := lintDisabledTypes(configBytes)
+	return append(filterLints(lints, disabled), dLints...)
+}
+
+// filterLints removes lints of the disabled types.
+func filterLints(lints []docs.Lint, disabled map[docs.LintType]struct{}) []docs.Lint {
+	if len(disabled) == 0 {
+		return lints
+	}
+	filtered := lints[:0]
+	for _, l := range lints {
+		if _, skip := disabled[l.Type]; !skip {
+			filtered = append(filtered, l)
+		}
+	}
+	return filtered
 }
@@ -49,6 +49,9 @@ type Reader struct {
 	streamsPaths  []string
 	overrides     []string
 
+	// Variables available to template expressions within resource files.
+	resourceVars map[string]any
+
 	// Controls whether the main config should include input, output, etc.
 	streamsMode bool
 
@@ -64,24 +67,33 @@ type Reader struct {
 	resourceFileInfo    map[string]resourceFileInfo
 	resourceFileInfoMut sync.Mutex
 
+	// The config files read at startup, which are checked together for the
+	// resource labels they define and reference.
+	labelSources []labelSource
+
 	mainUpdateFn   MainUpdateFunc
 	streamUpdateFn StreamUpdateFunc
 	watcher        fileWatcher
 
-	changeFlushPeriod time.Duration
-	changeDelayPeriod time.Duration
+	// Closed in order to stop polling resource files obtained over HTTP.
+	resourcePollStop chan struct{}
+
+	changeFlushPeriod  time.Duration
+	changeDelayPeriod  time.Duration
+	resourcePollPeriod time.Duration
 }
 
 // NewReader creates a new config reader.
 func NewReader(mainPath string, resourcePaths []string, opts ...OptFunc) *Reader {
 	r := &Reader{
-		testSuffix:        "_benthos_test",
-		mainPath:          mainPath,
-		resourcePaths:     resourcePaths,
-		streamFileInfo:    map[string]streamFileInfo{},
-		resourceFileInfo:  map[string]resourceFileInfo{},
-		changeFlushPeriod: defaultChangeFlushPeriod,
-		changeDelayPeriod: defaultChangeDelayPeriod,
+		testSuffix:         "_benthos_test",
+		mainPath:           mainPath,
+		resourcePaths:      resourcePaths,
+		streamFileInfo:     map[string]streamFileInfo{},
+		resourceFileInfo:   map[string]resourceFileInfo{},
+		changeFlushPeriod:  defaultChangeFlushPeriod,
+		changeDelayPeriod:  defaultChangeDelayPeriod,
+		resourcePollPeriod: defaultResourcePollPeriod,
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -111,6 +123,17 @@ func OptAddOverrides(overrides ...string) OptFunc {
 	}
 }
 
+// OptSetResourceVariables sets a map of variables that resource files are
+// templated with. When set, each expression of the form `${[ <query> ]}` within
+// a resource file is replaced with the result of executing the Bloblang query
+// against the variables before the file is linted and parsed, allowing a single
+// resource file to be instantiated with different labels and values.
+func OptSetResourceVariables(vars map[string]any) OptFunc {
+	return func(r *Reader) {
+		r.resourceVars = vars
+	}
+}
+
 // OptSetStreamPaths marks this config reader as operating in streams mode, and
 // adds a list of paths to obtain individual stream configs from.
 func OptSetStreamPaths(streamsPaths ...string) OptFunc {
@@ -184,6 +207,10 @@ func (r *Reader) SubscribeStreamChanges(fn StreamUpdateFunc) error {
 
 // Close the reader, when this method exits all reloading will be stopped.
 func (r *Reader) Close(ctx context.Context) error {
+	if r.resourcePollStop != nil {
+		close(r.resourcePollStop)
+		r.resourcePollStop = nil
+	}
 	if r.watcher != nil {
 		return r.watcher.Close()
 	}
@@ -266,6 +293,10 @@ func (r *Reader) readMain(conf *Type) (lints []string, err error) {
 		}
 	}
 
+	if r.mainPath != "" {
+		r.labelSources = append(r.labelSources, newLabelSource(r.mainPath, confBytes, &rawNode))
+	}
+
 	err = rawNode.Decode(conf)
 	return
 }
@@ -298,7 +329,7 @@ func (r *Reader) reactMainUpdate(mgr bundle.NewManagement, strict bool) bool {
 	}
 
 	// Update any resources within the file.
-	if newInfo := resInfoFromConfig(&conf.ResourceConfig); !newInfo.applyChanges(mgr) {
+	if newInfo := resInfoFromConfig(&conf.ResourceConfig); !newInfo.applyChanges(mgr, resourceFileInfo{}) {
 		return false
 	}
 
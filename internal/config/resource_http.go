@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+const (
+	defaultResourcePollPeriod = 10 * time.Second
+	resourceHTTPTimeout       = 30 * time.Second
+)
+
+var resourceHTTPClient = &http.Client{Timeout: resourceHTTPTimeout}
+
+// isHTTPResourcePath returns true if a resource path is an HTTP(S) URL rather
+// than a file path.
+func isHTTPResourcePath(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// httpResourceVersion identifies a version of a resource file obtained over
+// HTTP by the ETag and Last-Modified headers of the response.
+type httpResourceVersion struct {
+	etag         string
+	lastModified string
+}
+
+func httpResourceVersionFromHeader(h http.Header) httpResourceVersion {
+	return httpResourceVersion{
+		etag:         h.Get("ETag"),
+		lastModified: h.Get("Last-Modified"),
+	}
+}
+
+// known returns true if the server provided any means of identifying the
+// version of a resource file.
+func (v httpResourceVersion) known() bool {
+	return v.etag != "" || v.lastModified != ""
+}
+
+// readHTTPResource fetches the contents of a resource file from a URL.
+func readHTTPResource(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resourceHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status code: %v", res.StatusCode)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// checkHTTPResource performs a conditional HEAD request against a resource
+// file URL and returns its current version, along with whether it differs from
+// the previous version.
+func checkHTTPResource(ctx context.Context, url string, prev httpResourceVersion) (httpResourceVersion, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, http.NoBody)
+	if err != nil {
+		return prev, false, err
+	}
+	if prev.etag != "" {
+		req.Header.Set("If-None-Match", prev.etag)
+	}
+	if prev.lastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.lastModified)
+	}
+
+	res, err := resourceHTTPClient.Do(req)
+	if err != nil {
+		return prev, false, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return prev, false, nil
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return prev, false, fmt.Errorf("unexpected status code: %v", res.StatusCode)
+	}
+	v := httpResourceVersionFromHeader(res.Header)
+	return v, v != prev, nil
+}
+
+// readHTTPResourceEnvSwap fetches a resource file from a URL and replaces any
+// environment variable interpolations in the same way as resource files read
+// from the filesystem, including decompressing gzip compressed files.
+func readHTTPResourceEnvSwap(url string) (configBytes []byte, lints []docs.Lint, err error) {
+	if configBytes, err = readHTTPResource(context.Background(), url); err != nil {
+		return nil, nil, err
+	}
+	if configBytes, err = decompressConfig(url, configBytes); err != nil {
+		return nil, nil, err
+	}
+	configBytes, lints = envSwap(configBytes, true)
+	return
+}
+
+// httpResourcePaths returns the resource paths that are HTTP(S) URLs.
+func (r *Reader) httpResourcePaths() (urls []string) {
+	for _, p := range r.resourcePaths {
+		if isHTTPResourcePath(p) {
+			urls = append(urls, p)
+		}
+	}
+	return
+}
+
+// pollHTTPResources periodically checks resource files obtained over HTTP for
+// changes until the stop channel is closed, and updates their resources when a
+// new version is found. Resource files for which the server provides neither
+// an ETag nor a Last-Modified header are not reloaded.
+func (r *Reader) pollHTTPResources(mgr bundle.NewManagement, strict bool, urls []string, stopChan <-chan struct{}) {
+	versions := map[string]httpResourceVersion{}
+	unversioned := map[string]struct{}{}
+
+	check := func(url string) bool {
+		v, changed, err := checkHTTPResource(context.Background(), url, versions[url])
+		if err != nil {
+			mgr.Logger().Errorf("Failed to check resource %v for changes: %v", url, err)
+			return false
+		}
+		if !v.known() {
+			mgr.Logger().Warnf("Resource %v does not provide an ETag or Last-Modified header and will not be reloaded when changed.", url)
+			unversioned[url] = struct{}{}
+			return false
+		}
+		versions[url] = v
+		return changed
+	}
+
+	// The first check only establishes the versions of resource files that
+	// have already been read.
+	for _, url := range urls {
+		_ = check(url)
+	}
+
+	ticker := time.NewTicker(r.resourcePollPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stopChan:
+			return
+		}
+		for _, url := range urls {
+			if _, skip := unversioned[url]; skip {
+				continue
+			}
+			prev := versions[url]
+			if check(url) && !r.reactResourceUpdate(mgr, strict, url) {
+				// Restore the previous version so that the update is attempted
+				// again on the next poll.
+				versions[url] = prev
+			}
+		}
+	}
+}
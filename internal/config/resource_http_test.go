@@ -0,0 +1,165 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/manager"
+)
+
+type testResourceServer struct {
+	mut     sync.Mutex
+	body    string
+	version int
+}
+
+func (s *testResourceServer) set(body string) {
+	s.mut.Lock()
+	s.body = body
+	s.version++
+	s.mut.Unlock()
+}
+
+func (s *testResourceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	etag := `"` + strconv.Itoa(s.version) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if r.Method == http.MethodGet {
+		_, _ = w.Write([]byte(s.body))
+	}
+}
+
+func TestReaderHTTPResources(t *testing.T) {
+	srv := &testResourceServer{}
+	srv.set(`
+cache_resources:
+  - label: foo
+    memory: {}
+`)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{ts.URL + "/res.yaml"}
+
+	conf := manager.NewResourceConfig()
+	lints, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	mgr, err := manager.New(conf)
+	require.NoError(t, err)
+	assert.True(t, mgr.ProbeCache("foo"))
+
+	srv.set(`
+cache_resources:
+  - label: bar
+    memory: {}
+`)
+	require.True(t, rdr.reactResourceUpdate(mgr, true, ts.URL+"/res.yaml"))
+
+	assert.False(t, mgr.ProbeCache("foo"))
+	assert.True(t, mgr.ProbeCache("bar"))
+}
+
+func TestReaderHTTPResourcesBadStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	t.Cleanup(ts.Close)
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{ts.URL + "/res.yaml"}
+
+	conf := manager.NewResourceConfig()
+	_, err := rdr.readResources(&conf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 404")
+}
+
+func TestCheckHTTPResource(t *testing.T) {
+	srv := &testResourceServer{}
+	srv.set("foo")
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	v, changed, err := checkHTTPResource(context.Background(), ts.URL, httpResourceVersion{})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, `"1"`, v.etag)
+
+	v, changed, err = checkHTTPResource(context.Background(), ts.URL, v)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, `"1"`, v.etag)
+
+	srv.set("bar")
+
+	v, changed, err = checkHTTPResource(context.Background(), ts.URL, v)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, `"2"`, v.etag)
+}
+
+func TestReaderPollHTTPResources(t *testing.T) {
+	srv := &testResourceServer{}
+	srv.set(`
+cache_resources:
+  - label: foo
+    memory: {}
+`)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{ts.URL + "/res.yaml"}
+	rdr.resourcePollPeriod = 10 * time.Millisecond
+
+	conf := manager.NewResourceConfig()
+	_, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+
+	mgr, err := manager.New(conf)
+	require.NoError(t, err)
+
+	stopChan := make(chan struct{})
+	pollDone := make(chan struct{})
+	go func() {
+		rdr.pollHTTPResources(mgr, true, rdr.httpResourcePaths(), stopChan)
+		close(pollDone)
+	}()
+	t.Cleanup(func() {
+		close(stopChan)
+		<-pollDone
+	})
+
+	// The version is bumped until the update is observed, as the poller may
+	// establish its initial versions after the first bump.
+	assert.Eventually(t, func() bool {
+		if mgr.ProbeCache("bar") {
+			return true
+		}
+		srv.set(`
+cache_resources:
+  - label: foo
+    memory: {}
+  - label: bar
+    memory: {}
+`)
+		return false
+	}, time.Second*5, time.Millisecond*50)
+}
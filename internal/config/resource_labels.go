@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// resourceKindsByField maps the fields of a config that define resources to the
+// kind of resource that they define.
+var resourceKindsByField = map[string]string{
+	"input_resources":      "input",
+	"processor_resources":  "processor",
+	"output_resources":     "output",
+	"cache_resources":      "cache",
+	"rate_limit_resources": "rate_limit",
+}
+
+// labelSource is a parsed config file that resource labels are defined and
+// referenced within.
+type labelSource struct {
+	path string
+	node *yaml.Node
+
+	// Lints are only reported for sources that have not disabled them.
+	lintDisabled  bool
+	disabledTypes map[docs.LintType]struct{}
+}
+
+func newLabelSource(path string, confBytes []byte, node *yaml.Node) labelSource {
+	src := labelSource{path: path, node: node}
+	if bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
+		src.lintDisabled = true
+	} else {
+		src.disabledTypes, _ = lintDisabledTypes(confBytes)
+	}
+	return src
+}
+
+type resourceLabelDef struct {
+	label  string
+	kind   string
+	line   int
+	column int
+	src    *labelSource
+}
+
+// resourceLabelDefs returns the resources defined at the root of a config in
+// the order that they appear.
+func (s *labelSource) resourceLabelDefs() (defs []resourceLabelDef) {
+	root := s.node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i < len(root.Content)-1; i += 2 {
+		kind, exists := resourceKindsByField[root.Content[i].Value]
+		if !exists || root.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, res := range root.Content[i+1].Content {
+			if res.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j < len(res.Content)-1; j += 2 {
+				if res.Content[j].Value == "label" && res.Content[j+1].Value != "" {
+					defs = append(defs, resourceLabelDef{
+						label:  res.Content[j+1].Value,
+						kind:   kind,
+						line:   res.Content[j+1].Line,
+						column: res.Content[j+1].Column,
+						src:    s,
+					})
+				}
+			}
+		}
+	}
+	return
+}
+
+// addReferences adds each scalar value within a config to a set of potential
+// resource references, other than the values of labels themselves.
+func addReferences(node *yaml.Node, refs map[string]struct{}) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		refs[node.Value] = struct{}{}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			if node.Content[i].Value == "label" {
+				continue
+			}
+			addReferences(node.Content[i+1], refs)
+		}
+	default:
+		for _, c := range node.Content {
+			addReferences(c, refs)
+		}
+	}
+}
+
+// report adds a lint for a resource definition to a set of lint strings unless
+// it has been disabled within the source of the definition.
+func (d resourceLabelDef) report(lint docs.Lint, lints []string) []string {
+	if d.src.lintDisabled {
+		return lints
+	}
+	if _, disabled := d.src.disabledTypes[lint.Type]; disabled {
+		return lints
+	}
+	lint.Column = d.column
+	return append(lints, fmt.Sprintf("%v%v", d.src.path, lint.Error()))
+}
+
+// lintResourceLabels checks the resource labels defined across a collection of
+// config files. Errors are returned for labels that are defined within more
+// than one file, as labels must be unique in order to identify resources and
+// their metrics. Collisions within a single file are reported when that file
+// is linted and are therefore ignored.
+//
+// Warnings are returned for resources that are never referenced by any of the
+// files. A reference is any value matching the label of a resource, and
+// therefore resources that are only referenced dynamically, such as with
+// interpolation functions or through the streams API, are also reported.
+func lintResourceLabels(sources []labelSource) (errs, warnings []string) {
+	defs := map[string][]resourceLabelDef{}
+	var labels []string
+
+	refs := map[string]struct{}{}
+	for i := range sources {
+		for _, d := range sources[i].resourceLabelDefs() {
+			if _, exists := defs[d.label]; !exists {
+				labels = append(labels, d.label)
+			}
+			defs[d.label] = append(defs[d.label], d)
+		}
+		addReferences(sources[i].node, refs)
+	}
+	sort.Strings(labels)
+
+	for _, l := range labels {
+		lDefs := defs[l]
+		for _, d := range lDefs[1:] {
+			if d.src == lDefs[0].src {
+				continue
+			}
+			errs = d.report(docs.NewLintError(
+				d.line, docs.LintDuplicateLabel,
+				fmt.Sprintf("%v resource label '%v' collides with a %v resource defined in %v", d.kind, l, lDefs[0].kind, lDefs[0].src.path),
+			), errs)
+		}
+		if _, exists := refs[l]; !exists {
+			for _, d := range lDefs {
+				warnings = d.report(docs.NewLintWarning(
+					d.line, docs.LintUnusedResource,
+					fmt.Sprintf("%v resource '%v' is not referenced by any config", d.kind, l),
+				), warnings)
+			}
+		}
+	}
+	return
+}
+
+// LintResourceLabels checks the resource labels defined across all of the
+// config files that have been read, returning errors for labels defined within
+// more than one file and warnings for resources that are not referenced by any
+// file. In streams mode this should be called after stream configs are read.
+func (r *Reader) LintResourceLabels() (errs, warnings []string) {
+	return lintResourceLabels(r.labelSources)
+}
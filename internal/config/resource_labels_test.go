@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderLintResourceLabels(t *testing.T) {
+	dir := t.TempDir()
+
+	mainPath := filepath.Join(dir, "main.yaml")
+	require.NoError(t, os.WriteFile(mainPath, []byte(`
+input:
+  generate:
+    mapping: 'root = "hello"'
+pipeline:
+  processors:
+    - resource: foo
+    - cache:
+        resource: bar
+        operator: get
+        key: baz
+output:
+  drop: {}
+`), 0o644))
+
+	resourceOnePath := filepath.Join(dir, "res1.yaml")
+	require.NoError(t, os.WriteFile(resourceOnePath, []byte(`
+processor_resources:
+  - label: foo
+    noop: {}
+cache_resources:
+  - label: bar
+    memory: {}
+  - label: unused
+    memory: {}
+`), 0o644))
+
+	resourceTwoPath := filepath.Join(dir, "res2.yaml")
+	require.NoError(t, os.WriteFile(resourceTwoPath, []byte(`
+rate_limit_resources:
+  - label: foo
+    local: {}
+`), 0o644))
+
+	resourceThreePath := filepath.Join(dir, "res3.yaml")
+	require.NoError(t, os.WriteFile(resourceThreePath, []byte(`# benthos-lint-disable unused_resource
+cache_resources:
+  - label: also_unused
+    memory: {}
+`), 0o644))
+
+	rdr := newDummyReader(mainPath)
+	rdr.resourcePaths = []string{resourceOnePath, resourceTwoPath, resourceThreePath}
+
+	conf := New()
+	_, err := rdr.Read(&conf)
+	require.NoError(t, err)
+
+	errs, warnings := rdr.LintResourceLabels()
+	assert.Equal(t, []string{
+		resourceTwoPath + "(3,12) rate_limit resource label 'foo' collides with a processor resource defined in " + resourceOnePath,
+	}, errs)
+	assert.Equal(t, []string{
+		resourceOnePath + "(8,12) cache resource 'unused' is not referenced by any config",
+	}, warnings)
+}
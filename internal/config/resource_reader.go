@@ -3,14 +3,15 @@ package config
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/benthosdev/benthos/v4/internal/bundle"
-	tdocs "github.com/benthosdev/benthos/v4/internal/cli/test/docs"
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
@@ -47,31 +48,84 @@ func resInfoFromConfig(conf *manager.ResourceConfig) resourceFileInfo {
 	resInfo.updatedAt = time.Now()
 
 	// New style
-	for _, c := range conf.ResourceInputs {
-		resInfo.inputs[c.Label] = &c
+	for i, c := range conf.ResourceInputs {
+		resInfo.inputs[c.Label] = &conf.ResourceInputs[i]
 	}
-	for _, c := range conf.ResourceProcessors {
-		resInfo.processors[c.Label] = &c
+	for i, c := range conf.ResourceProcessors {
+		resInfo.processors[c.Label] = &conf.ResourceProcessors[i]
 	}
-	for _, c := range conf.ResourceOutputs {
-		resInfo.outputs[c.Label] = &c
+	for i, c := range conf.ResourceOutputs {
+		resInfo.outputs[c.Label] = &conf.ResourceOutputs[i]
 	}
-	for _, c := range conf.ResourceCaches {
-		resInfo.caches[c.Label] = &c
+	for i, c := range conf.ResourceCaches {
+		resInfo.caches[c.Label] = &conf.ResourceCaches[i]
 	}
-	for _, c := range conf.ResourceRateLimits {
-		resInfo.rateLimits[c.Label] = &c
+	for i, c := range conf.ResourceRateLimits {
+		resInfo.rateLimits[c.Label] = &conf.ResourceRateLimits[i]
 	}
 
 	return resInfo
 }
 
+// resourcePathsExpanded returns the configured resource paths with any glob
+// patterns expanded. Resource paths that are HTTP(S) URLs are not expanded and
+// are returned after all file paths.
 func (r *Reader) resourcePathsExpanded() ([]string, error) {
-	resourcePaths, err := ifilepath.Globs(ifs.OS(), r.resourcePaths)
+	var filePaths []string
+	for _, p := range r.resourcePaths {
+		if !isHTTPResourcePath(p) {
+			filePaths = append(filePaths, p)
+		}
+	}
+	resourcePaths, err := ifilepath.Globs(ifs.OS(), filePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve resource glob pattern: %w", err)
 	}
-	return resourcePaths, nil
+	return append(resourcePaths, r.httpResourcePaths()...), nil
+}
+
+// resourcePathKey returns the key that a resource path is tracked by, which is
+// the cleaned path of a file or the URL of a resource obtained over HTTP.
+func resourcePathKey(path string) string {
+	if isHTTPResourcePath(path) {
+		return path
+	}
+	return filepath.Clean(path)
+}
+
+// resourceDirs returns the directories that contain resource files according
+// to the configured resource paths. Directories that are themselves a glob
+// pattern, and resource paths that are URLs, are omitted.
+func (r *Reader) resourceDirs() (dirs []string) {
+	seen := map[string]struct{}{}
+	for _, p := range r.resourcePaths {
+		if isHTTPResourcePath(p) {
+			continue
+		}
+		d := filepath.Dir(p)
+		if strings.ContainsAny(d, "*?[") {
+			continue
+		}
+		if _, exists := seen[d]; !exists {
+			seen[d] = struct{}{}
+			dirs = append(dirs, d)
+		}
+	}
+	return
+}
+
+// isResourcePath returns true if a path matches the configured resource paths.
+func (r *Reader) isResourcePath(path string) bool {
+	resourcePaths, err := r.resourcePathsExpanded()
+	if err != nil {
+		return false
+	}
+	for _, p := range resourcePaths {
+		if resourcePathKey(p) == path {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Reader) readResources(conf *manager.ResourceConfig) (lints []string, err error) {
@@ -79,24 +133,72 @@ func (r *Reader) readResources(conf *manager.ResourceConfig) (lints []string, er
 	if err != nil {
 		return nil, err
 	}
+
+	// Track the origin of each resource so that label collisions can name both
+	// of the files involved.
+	mainOrigin := r.mainPath
+	if mainOrigin == "" {
+		mainOrigin = "the main config"
+	}
+	origins := map[manager.ResourceLabel]string{}
+	for _, l := range conf.ResourceLabels() {
+		origins[l] = mainOrigin
+	}
+
 	for _, path := range resourcesPaths {
 		rconf := manager.NewResourceConfig()
-		var rLints []string
-		if rLints, err = readResource(path, &rconf); err != nil {
+		var rLints []docs.Lint
+		var src labelSource
+		if rLints, src, err = readResourceSource(path, r.resourceVars, &rconf); err != nil {
 			return
 		}
-		lints = append(lints, rLints...)
+		r.labelSources = append(r.labelSources, src)
+		for _, l := range rLints {
+			lints = append(lints, fmt.Sprintf("%v%v", path, l.Error()))
+		}
 
-		if err = conf.AddFrom(&rconf); err != nil {
-			err = fmt.Errorf("%v: %w", path, err)
+		if err = AddResourcesFrom(conf, &rconf, path, origins); err != nil {
 			return
 		}
-		r.resourceFileInfo[filepath.Clean(path)] = resInfoFromConfig(&rconf)
+		r.resourceFileInfo[resourcePathKey(path)] = resInfoFromConfig(&rconf)
+	}
+	return
+}
+
+// AddResourcesFrom adds the resources of a config read from a file to another
+// config. Origins maps the labels of the existing resources to the files they
+// were read from, and when a label collides the returned error names both of
+// the files involved. On success origins is updated with the new labels.
+func AddResourcesFrom(conf, extra *manager.ResourceConfig, path string, origins map[manager.ResourceLabel]string) error {
+	if err := conf.AddFrom(extra); err != nil {
+		var cErr *manager.ErrResourceLabelCollision
+		if errors.As(err, &cErr) {
+			return fmt.Errorf("%v resource label %v defined in %v collides with the same label defined in %v", cErr.Kind, cErr.Label, path, origins[cErr.ResourceLabel])
+		}
+		return fmt.Errorf("%v: %w", path, err)
 	}
+	for _, l := range extra.ResourceLabels() {
+		origins[l] = path
+	}
+	return nil
+}
+
+// readResource reads a resource file into a config, returning any linting
+// issues found within it. Lints are not prefixed with the file path. Paths that
+// are HTTP(S) URLs are fetched with a GET request. When variables are provided
+// the file is expanded as a template with them before being linted and parsed.
+//
+// Linting is disabled entirely when the file begins with the comment
+// `# BENTHOS LINT DISABLE`, and specific lint types can be disabled with one or
+// more `# benthos-lint-disable <type>...` comments anywhere in the file.
+func readResource(path string, vars map[string]any, conf *manager.ResourceConfig) (lints []docs.Lint, err error) {
+	lints, _, err = readResourceSource(path, vars, conf)
 	return
 }
 
-func readResource(path string, conf *manager.ResourceConfig) (lints []string, err error) {
+// readResourceSource reads a resource file in the same way as readResource and
+// also returns the parsed file as a source of resource labels.
+func readResourceSource(path string, vars map[string]any, conf *manager.ResourceConfig) (lints []docs.Lint, src labelSource, err error) {
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("%v: %w", path, err)
@@ -104,27 +206,31 @@ func readResource(path string, conf *manager.ResourceConfig) (lints []string, er
 	}()
 
 	var confBytes []byte
-	var dLints []docs.Lint
-	if confBytes, dLints, err = ReadFileEnvSwap(path); err != nil {
+	if isHTTPResourcePath(path) {
+		confBytes, lints, err = readHTTPResourceEnvSwap(path)
+	} else {
+		confBytes, lints, err = ReadFileEnvSwapLintMissing(path)
+	}
+	if err != nil {
 		return
 	}
-	for _, l := range dLints {
-		lints = append(lints, l.Error())
+	if vars != nil {
+		if confBytes, err = expandResourceTemplate(confBytes, vars); err != nil {
+			return
+		}
 	}
 
 	var rawNode yaml.Node
 	if err = yaml.Unmarshal(confBytes, &rawNode); err != nil {
 		return
 	}
-	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		allowTest := append(docs.FieldSpecs{
-			tdocs.ConfigSpec(),
-		}, manager.Spec()...)
-		for _, lint := range allowTest.LintYAML(docs.NewLintContext(), &rawNode) {
-			lints = append(lints, fmt.Sprintf("%v%v", path, lint.Error()))
-		}
+	if bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
+		lints = nil
+	} else {
+		lints = applyLintDirectives(confBytes, append(lints, lintYAMLNode(docs.NewLintContext(), &rawNode, true)...))
 	}
 
+	src = newLabelSource(path, confBytes, &rawNode)
 	err = rawNode.Decode(conf)
 	return
 }
@@ -133,35 +239,40 @@ func (r *Reader) reactResourceUpdate(mgr bundle.NewManagement, strict bool, path
 	r.resourceFileInfoMut.Lock()
 	defer r.resourceFileInfoMut.Unlock()
 
-	if _, exists := r.resourceFileInfo[path]; !exists {
-		mgr.Logger().Warnf("Skipping resource update for unknown path: %v", path)
+	if _, exists := r.resourceFileInfo[path]; exists {
+		mgr.Logger().Infof("Resource %v config updated, attempting to update resources.", path)
+	} else if r.isResourcePath(path) {
+		mgr.Logger().Infof("Resource %v config created, attempting to add resources.", path)
+	} else {
+		mgr.Logger().Debugf("Skipping resource update for unknown path: %v", path)
 		return true
 	}
 
-	mgr.Logger().Infof("Resource %v config updated, attempting to update resources.", path)
-
 	newResConf := manager.NewResourceConfig()
-	lints, err := readResource(path, &newResConf)
+	lints, err := readResource(path, r.resourceVars, &newResConf)
 	if err != nil {
 		mgr.Logger().Errorf("Failed to read updated resources config: %v", err)
 		return true
 	}
 
+	var lintErrors int
 	lintlog := mgr.Logger()
 	for _, lint := range lints {
-		lintlog.Infoln(lint)
+		if lint.Level == docs.LintError {
+			lintErrors++
+		}
+		lintlog.Infof("%v%v", path, lint.Error())
 	}
-	if strict && len(lints) > 0 {
+	if strict && lintErrors > 0 {
 		mgr.Logger().Errorln("Rejecting updated resource config due to linter errors, to allow linting errors run Benthos with --chilled")
 		return true
 	}
 
-	// TODO: Should we error out if the new config is missing some resources?
-	// (as they will continue to exist). Also, we could avoid restarting
-	// resources where the config hasn't changed.
-
 	newInfo := resInfoFromConfig(&newResConf)
-	if !newInfo.applyChanges(mgr) {
+	if !newInfo.applyChanges(mgr, r.resourceFileInfo[path]) {
+		return false
+	}
+	if !r.removeStaleResources(mgr, path, newInfo) {
 		return false
 	}
 
@@ -169,49 +280,187 @@ func (r *Reader) reactResourceUpdate(mgr bundle.NewManagement, strict bool, path
 	return true
 }
 
-func (i *resourceFileInfo) applyChanges(mgr bundle.NewManagement) bool {
+// resourceConfigsEqual returns true if two resource configs serialise to
+// identical YAML, in which case there is no need to restart the resource.
+func resourceConfigsEqual(a, b any) bool {
+	aBytes, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := yaml.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aBytes, bBytes)
+}
+
+// unchangedResource returns true if a resource config is identical to the
+// config of the same label from the previous read of the file.
+func unchangedResource[T any](mgr bundle.NewManagement, label string, conf *T, prev map[string]*T) bool {
+	prevConf, exists := prev[label]
+	if !exists || !resourceConfigsEqual(prevConf, conf) {
+		return false
+	}
+	mgr.Logger().Debugf("Resource %v config unchanged, skipping update.", label)
+	return true
+}
+
+// resourceUndo reverts a single resource change applied during an update.
+type resourceUndo struct {
+	label string
+	fn    func(ctx context.Context) error
+}
+
+// applyResourceChanges stores each resource config that has changed since the
+// previous read, and records how to revert each change that is applied. When a
+// resource existed prior to the update but not within the previous read its
+// config is unknown, and therefore the change cannot be reverted.
+func applyResourceChanges[T any](
+	ctx context.Context,
+	mgr bundle.NewManagement,
+	next, prev map[string]*T,
+	probe func(name string) bool,
+	store func(ctx context.Context, name string, conf T) error,
+	remove func(ctx context.Context, name string) error,
+	undos *[]resourceUndo,
+) bool {
+	for k, v := range next {
+		if unchangedResource(mgr, k, v, prev) {
+			continue
+		}
+
+		label := k
+		var undo func(ctx context.Context) error
+		if prevConf, exists := prev[k]; exists {
+			undo = func(ctx context.Context) error {
+				return store(ctx, label, *prevConf)
+			}
+		} else if !probe(k) {
+			undo = func(ctx context.Context) error {
+				return remove(ctx, label)
+			}
+		}
+
+		if err := store(ctx, k, *v); err != nil {
+			mgr.Logger().Errorf("Failed to update resource %v: %v", k, err)
+			return false
+		}
+		mgr.Logger().Infof("Updated resource %v config from file.", k)
+
+		if undo != nil {
+			*undos = append(*undos, resourceUndo{label: label, fn: undo})
+		}
+	}
+	return true
+}
+
+// applyChanges applies the resources of a read that have changed since the
+// previous read. If any resource fails to be applied then the resources that
+// were already applied are reverted to their previous configs, or removed if
+// they did not previously exist, so that the manager is not left partially
+// updated.
+func (i *resourceFileInfo) applyChanges(mgr bundle.NewManagement, prev resourceFileInfo) bool {
 	// Kind of arbitrary, but I feel better about having some sort of timeout.
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
 	defer cancel()
 
+	var undos []resourceUndo
+
 	// WARNING: The order here is actually kind of important, we want to start
 	// with components that could be dependencies of other components. This is
 	// a "best attempt", so not all edge cases need to be accounted for.
-	for k, v := range i.rateLimits {
-		if err := mgr.StoreRateLimit(ctx, k, *v); err != nil {
-			mgr.Logger().Errorf("Failed to update resource %v: %v", k, err)
-			return false
-		}
-		mgr.Logger().Infof("Updated resource %v config from file.", k)
+	if applyResourceChanges(ctx, mgr, i.rateLimits, prev.rateLimits, mgr.ProbeRateLimit, mgr.StoreRateLimit, mgr.RemoveRateLimit, &undos) &&
+		applyResourceChanges(ctx, mgr, i.caches, prev.caches, mgr.ProbeCache, mgr.StoreCache, mgr.RemoveCache, &undos) &&
+		applyResourceChanges(ctx, mgr, i.processors, prev.processors, mgr.ProbeProcessor, mgr.StoreProcessor, mgr.RemoveProcessor, &undos) &&
+		applyResourceChanges(ctx, mgr, i.inputs, prev.inputs, mgr.ProbeInput, mgr.StoreInput, mgr.RemoveInput, &undos) &&
+		applyResourceChanges(ctx, mgr, i.outputs, prev.outputs, mgr.ProbeOutput, mgr.StoreOutput, mgr.RemoveOutput, &undos) {
+		return true
 	}
-	for k, v := range i.caches {
-		if err := mgr.StoreCache(ctx, k, *v); err != nil {
-			mgr.Logger().Errorf("Failed to update resource %v: %v", k, err)
-			return false
+
+	// Revert in the reverse order so that dependents are reverted before
+	// their dependencies.
+	for j := len(undos) - 1; j >= 0; j-- {
+		if err := undos[j].fn(ctx); err != nil {
+			mgr.Logger().Errorf("Failed to roll back resource %v: %v", undos[j].label, err)
+			continue
 		}
-		mgr.Logger().Infof("Updated resource %v config from file.", k)
+		mgr.Logger().Infof("Rolled back resource %v after a failed update.", undos[j].label)
 	}
-	for k, v := range i.processors {
-		if err := mgr.StoreProcessor(ctx, k, *v); err != nil {
-			mgr.Logger().Errorf("Failed to update resource %v: %v", k, err)
-			return false
+	return false
+}
+
+// removedLabels returns the labels of a previous read of a resource file that
+// are absent from a new read.
+func removedLabels[T any](prev, next map[string]T) (labels []string) {
+	for k := range prev {
+		if _, exists := next[k]; !exists {
+			labels = append(labels, k)
 		}
-		mgr.Logger().Infof("Updated resource %v config from file.", k)
 	}
-	for k, v := range i.inputs {
-		if err := mgr.StoreInput(ctx, k, *v); err != nil {
-			mgr.Logger().Errorf("Failed to update resource %v: %v", k, err)
-			return false
+	return
+}
+
+// removeStaleResources removes resources that were present in the previous read
+// of a resource file but are absent from the new one. Resources that have moved
+// into a different resource file are left intact.
+func (r *Reader) removeStaleResources(mgr bundle.NewManagement, path string, newInfo resourceFileInfo) bool {
+	prevInfo := r.resourceFileInfo[path]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	movedFn := func(label string, labelExists func(info resourceFileInfo) bool) bool {
+		for p, info := range r.resourceFileInfo {
+			if p != path && labelExists(info) {
+				mgr.Logger().Infof("Resource %v has moved to file %v.", label, p)
+				return true
+			}
 		}
-		mgr.Logger().Infof("Updated resource %v config from file.", k)
+		return false
 	}
-	for k, v := range i.outputs {
-		if err := mgr.StoreOutput(ctx, k, *v); err != nil {
-			mgr.Logger().Errorf("Failed to update resource %v: %v", k, err)
-			return false
+
+	removeFn := func(labels []string, labelExists func(info resourceFileInfo, label string) bool, remove func(ctx context.Context, name string) error) bool {
+		for _, k := range labels {
+			if movedFn(k, func(info resourceFileInfo) bool { return labelExists(info, k) }) {
+				continue
+			}
+			if err := remove(ctx, k); err != nil {
+				mgr.Logger().Errorf("Failed to remove resource %v: %v", k, err)
+				return false
+			}
+			mgr.Logger().Infof("Removed resource %v as it is no longer present in file.", k)
 		}
-		mgr.Logger().Infof("Updated resource %v config from file.", k)
+		return true
 	}
 
-	return true
+	// WARNING: The order here is the reverse of applyChanges as we want to
+	// remove components before any that they might depend on.
+	if !removeFn(removedLabels(prevInfo.outputs, newInfo.outputs), func(info resourceFileInfo, label string) bool {
+		_, exists := info.outputs[label]
+		return exists
+	}, mgr.RemoveOutput) {
+		return false
+	}
+	if !removeFn(removedLabels(prevInfo.inputs, newInfo.inputs), func(info resourceFileInfo, label string) bool {
+		_, exists := info.inputs[label]
+		return exists
+	}, mgr.RemoveInput) {
+		return false
+	}
+	if !removeFn(removedLabels(prevInfo.processors, newInfo.processors), func(info resourceFileInfo, label string) bool {
+		_, exists := info.processors[label]
+		return exists
+	}, mgr.RemoveProcessor) {
+		return false
+	}
+	if !removeFn(removedLabels(prevInfo.caches, newInfo.caches), func(info resourceFileInfo, label string) bool {
+		_, exists := info.caches[label]
+		return exists
+	}, mgr.RemoveCache) {
+		return false
+	}
+	return removeFn(removedLabels(prevInfo.rateLimits, newInfo.rateLimits), func(info resourceFileInfo, label string) bool {
+		_, exists := info.rateLimits[label]
+		return exists
+	}, mgr.RemoveRateLimit)
 }
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+)
+
+func TestReaderResourceUpdateRemovesResources(t *testing.T) {
+	dir := t.TempDir()
+
+	resourceOnePath := filepath.Join(dir, "res1.yaml")
+	require.NoError(t, os.WriteFile(resourceOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory: {}
+  - label: bar
+    memory: {}
+`), 0o644))
+
+	resourceTwoPath := filepath.Join(dir, "res2.yaml")
+	require.NoError(t, os.WriteFile(resourceTwoPath, []byte(`
+cache_resources:
+  - label: baz
+    memory: {}
+`), 0o644))
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{resourceOnePath, resourceTwoPath}
+
+	conf := manager.NewResourceConfig()
+	lints, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	mgr, err := manager.New(conf)
+	require.NoError(t, err)
+
+	assert.True(t, mgr.ProbeCache("foo"))
+	assert.True(t, mgr.ProbeCache("bar"))
+	assert.True(t, mgr.ProbeCache("baz"))
+
+	// Move bar into the second file
+	require.NoError(t, os.WriteFile(resourceTwoPath, []byte(`
+cache_resources:
+  - label: baz
+    memory: {}
+  - label: bar
+    memory: {}
+`), 0o644))
+	require.True(t, rdr.reactResourceUpdate(mgr, true, filepath.Clean(resourceTwoPath)))
+
+	// Remove both foo and bar from the first file
+	require.NoError(t, os.WriteFile(resourceOnePath, []byte(`
+cache_resources: []
+`), 0o644))
+	require.True(t, rdr.reactResourceUpdate(mgr, true, filepath.Clean(resourceOnePath)))
+
+	assert.False(t, mgr.ProbeCache("foo"))
+	assert.True(t, mgr.ProbeCache("bar"))
+	assert.True(t, mgr.ProbeCache("baz"))
+}
+
+func TestReaderResourceUpdateSkipsUnchanged(t *testing.T) {
+	dir := t.TempDir()
+
+	resourcePath := filepath.Join(dir, "res.yaml")
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      default_ttl: 10m
+  - label: bar
+    memory:
+      default_ttl: 10m
+`), 0o644))
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{resourcePath}
+
+	conf := manager.NewResourceConfig()
+	_, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+
+	mgr, err := manager.New(conf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, label := range []string{"foo", "bar"} {
+		require.NoError(t, mgr.AccessCache(ctx, label, func(c cache.V1) {
+			require.NoError(t, c.Set(ctx, "key", []byte("value"), nil))
+		}))
+	}
+
+	// Only bar is changed, and therefore only bar should be reinitialised
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      default_ttl: 10m
+  - label: bar
+    memory:
+      default_ttl: 20m
+`), 0o644))
+	require.True(t, rdr.reactResourceUpdate(mgr, true, filepath.Clean(resourcePath)))
+
+	require.NoError(t, mgr.AccessCache(ctx, "foo", func(c cache.V1) {
+		v, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, "value", string(v))
+	}))
+	require.NoError(t, mgr.AccessCache(ctx, "bar", func(c cache.V1) {
+		_, err := c.Get(ctx, "key")
+		assert.Equal(t, component.ErrKeyNotFound, err)
+	}))
+}
+
+func TestReaderResourceUpdateRollsBack(t *testing.T) {
+	dir := t.TempDir()
+
+	resourcePath := filepath.Join(dir, "res.yaml")
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      default_ttl: 10m
+`), 0o644))
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{resourcePath}
+
+	conf := manager.NewResourceConfig()
+	_, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+
+	mgr, err := manager.New(conf)
+	require.NoError(t, err)
+
+	// The processor cannot be created and so the cache changes should be
+	// rolled back.
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      default_ttl: 20m
+  - label: bar
+    memory: {}
+processor_resources:
+  - label: baz
+    bloblang: 'root = '
+`), 0o644))
+	require.False(t, rdr.reactResourceUpdate(mgr, false, filepath.Clean(resourcePath)))
+
+	assert.True(t, mgr.ProbeCache("foo"))
+	assert.False(t, mgr.ProbeCache("bar"))
+	assert.False(t, mgr.ProbeProcessor("baz"))
+}
+
+func TestReaderResourceUpdateNewFile(t *testing.T) {
+	dir := t.TempDir()
+
+	resourceOnePath := filepath.Join(dir, "res1.yaml")
+	require.NoError(t, os.WriteFile(resourceOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory: {}
+`), 0o644))
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{filepath.Join(dir, "*.yaml")}
+
+	conf := manager.NewResourceConfig()
+	_, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+
+	mgr, err := manager.New(conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{dir}, rdr.resourceDirs())
+
+	resourceTwoPath := filepath.Join(dir, "res2.yaml")
+	require.NoError(t, os.WriteFile(resourceTwoPath, []byte(`
+cache_resources:
+  - label: bar
+    memory: {}
+`), 0o644))
+	require.True(t, rdr.reactResourceUpdate(mgr, true, filepath.Clean(resourceTwoPath)))
+
+	assert.True(t, mgr.ProbeCache("foo"))
+	assert.True(t, mgr.ProbeCache("bar"))
+	assert.Contains(t, rdr.resourceFileInfo, filepath.Clean(resourceTwoPath))
+
+	otherPath := filepath.Join(dir, "other.txt")
+	require.NoError(t, os.WriteFile(otherPath, []byte(`
+cache_resources:
+  - label: baz
+    memory: {}
+`), 0o644))
+	require.True(t, rdr.reactResourceUpdate(mgr, true, filepath.Clean(otherPath)))
+
+	assert.False(t, mgr.ProbeCache("baz"))
+	assert.NotContains(t, rdr.resourceFileInfo, filepath.Clean(otherPath))
+}
+
+func TestReadResourceStructuredLints(t *testing.T) {
+	dir := t.TempDir()
+
+	resourcePath := filepath.Join(dir, "res.yaml")
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+cache_resources:
+  - label: foo
+    memory: {}
+    meow: nope
+`), 0o644))
+
+	conf := manager.NewResourceConfig()
+	lints, err := readResource(resourcePath, nil, &conf)
+	require.NoError(t, err)
+	require.Len(t, lints, 1)
+
+	assert.Equal(t, 5, lints[0].Line)
+	assert.Equal(t, docs.LintError, lints[0].Level)
+	assert.Equal(t, docs.LintUnknown, lints[0].Type)
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{resourcePath}
+
+	mgr, err := manager.New(manager.NewResourceConfig())
+	require.NoError(t, err)
+
+	require.True(t, rdr.reactResourceUpdate(mgr, true, filepath.Clean(resourcePath)))
+	assert.False(t, mgr.ProbeCache("foo"))
+
+	require.True(t, rdr.reactResourceUpdate(mgr, false, filepath.Clean(resourcePath)))
+	assert.True(t, mgr.ProbeCache("foo"))
+}
+
+func TestReadResourceLintDisable(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := docs.RegisterLintType("test_directive_type")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name     string
+		conf     string
+		expected []string
+	}{
+		{
+			name: "no directives",
+			conf: `
+cache_resources:
+  - label: foo
+    memory: {}
+    meow: nope
+`,
+			expected: []string{"(5,1) field meow is invalid when the component type is memory (cache)"},
+		},
+		{
+			name: "disable all",
+			conf: `# BENTHOS LINT DISABLE
+cache_resources:
+  - label: foo
+    memory: {}
+    meow: nope
+`,
+		},
+		{
+			name: "disable unknown",
+			conf: `
+# benthos-lint-disable unknown
+cache_resources:
+  - label: foo
+    memory: {}
+    meow: nope
+`,
+		},
+		{
+			name: "disable other type",
+			conf: `
+# benthos-lint-disable deprecated bad_label
+cache_resources:
+  - label: foo
+    memory: {}
+    meow: nope
+`,
+			expected: []string{"(6,1) field meow is invalid when the component type is memory (cache)"},
+		},
+		{
+			name: "disable unrecognised type",
+			conf: `
+# benthos-lint-disable meow
+cache_resources:
+  - label: foo
+    memory: {}
+`,
+			expected: []string{"(2,1) unrecognised lint type meow in # benthos-lint-disable directive"},
+		},
+		{
+			name: "disable registered type",
+			conf: `
+# benthos-lint-disable test_directive_type
+cache_resources:
+  - label: foo
+    memory: {}
+`,
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			resourcePath := filepath.Join(dir, "res.yaml")
+			require.NoError(t, os.WriteFile(resourcePath, []byte(test.conf), 0o644))
+
+			conf := manager.NewResourceConfig()
+			lints, err := readResource(resourcePath, nil, &conf)
+			require.NoError(t, err)
+
+			var lintStrs []string
+			for _, l := range lints {
+				lintStrs = append(lintStrs, l.Error())
+			}
+			assert.Equal(t, test.expected, lintStrs)
+		})
+	}
+}
+
+func TestReaderResourceLabelCollision(t *testing.T) {
+	dir := t.TempDir()
+
+	resourceOnePath := filepath.Join(dir, "res1.yaml")
+	require.NoError(t, os.WriteFile(resourceOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory: {}
+`), 0o644))
+
+	resourceTwoPath := filepath.Join(dir, "res2.yaml")
+	require.NoError(t, os.WriteFile(resourceTwoPath, []byte(`
+rate_limit_resources:
+  - label: foo
+    local: {}
+cache_resources:
+  - label: foo
+    memory: {}
+`), 0o644))
+
+	rdr := newDummyReader("")
+	rdr.resourcePaths = []string{resourceOnePath, resourceTwoPath}
+
+	conf := manager.NewResourceConfig()
+	_, err := rdr.readResources(&conf)
+	require.EqualError(t, err, "cache resource label foo defined in "+resourceTwoPath+" collides with the same label defined in "+resourceOnePath)
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+var resourceTemplateRegex = regexp.MustCompile(`\${\[(.+?)\]}`)
+
+// expandResourceTemplate replaces each expression of the form `${[ <query> ]}`
+// within resource config bytes with the result of executing the Bloblang query
+// against a map of variables, which is referenced as `this`. Expressions may
+// not contain the sequence `]}`. The delimiters differ from those of
+// environment variables and interpolation functions so that their escaped forms
+// `${{FOO}}` and `${{!foo}}` are left untouched.
+//
+// Similar to environment variables, newlines within the results are escaped,
+// and structured results are serialised as JSON.
+func expandResourceTemplate(configBytes []byte, vars map[string]any) ([]byte, error) {
+	var err error
+	expanded := resourceTemplateRegex.ReplaceAllFunc(configBytes, func(content []byte) []byte {
+		if err != nil {
+			return nil
+		}
+		var value string
+		if value, err = execResourceTemplateExpr(string(content[3:len(content)-2]), vars); err != nil {
+			line := 1 + bytes.Count(configBytes[:bytes.Index(configBytes, content)], []byte("\n"))
+			err = fmt.Errorf("line %v: %w", line, err)
+			return nil
+		}
+		return []byte(strings.ReplaceAll(value, "\n", "\\n"))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+func execResourceTemplateExpr(expr string, vars map[string]any) (string, error) {
+	exec, err := bloblang.GlobalEnvironment().NewMapping(strings.TrimSpace(expr))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template expression: %w", err)
+	}
+	res, err := exec.Exec(query.FunctionContext{
+		Vars:     map[string]any{},
+		Maps:     exec.Maps(),
+		MsgBatch: message.QuickBatch(nil),
+	}.WithValue(vars))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute template expression: %w", err)
+	}
+	return query.IToString(res), nil
+}
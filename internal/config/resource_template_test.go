@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+)
+
+func TestExpandResourceTemplate(t *testing.T) {
+	vars := map[string]any{
+		"env":  "prod",
+		"ttl":  60,
+		"tags": []any{"a", "b"},
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		output      string
+		errContains string
+	}{
+		{
+			name:   "no expressions",
+			input:  `foo: ${BAR} ${! meta("baz") }`,
+			output: `foo: ${BAR} ${! meta("baz") }`,
+		},
+		{
+			name:   "variables",
+			input:  `label: ${[ this.env ]}_cache, ttl: ${[this.ttl * 2]}s`,
+			output: `label: prod_cache, ttl: 120s`,
+		},
+		{
+			name:   "no spaces",
+			input:  `label: ${[this.env]}_cache`,
+			output: `label: prod_cache`,
+		},
+		{
+			name:   "escaped interpolations",
+			input:  `foo: ${{BAR}} ${{!meta("baz")}}`,
+			output: `foo: ${{BAR}} ${{!meta("baz")}}`,
+		},
+		{
+			name:   "structured",
+			input:  `tags: ${[ this.tags ]}`,
+			output: `tags: ["a","b"]`,
+		},
+		{
+			name:   "functions",
+			input:  `label: ${[ this.env.uppercase() + "_" + this.missing.or("default") ]}`,
+			output: `label: PROD_default`,
+		},
+		{
+			name:        "parse error",
+			input:       "foo: bar\nlabel: ${[ this.env.nope( ]}",
+			errContains: "line 2: failed to parse template expression",
+		},
+		{
+			name:        "exec error",
+			input:       `label: ${[ this.env.number() ]}`,
+			errContains: "line 1: failed to execute template expression",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			res, err := expandResourceTemplate([]byte(test.input), vars)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.output, string(res))
+		})
+	}
+}
+
+func TestReaderResourceTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	resourcePath := filepath.Join(dir, "res.yaml")
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+cache_resources:
+  - label: ${[this.env]}_cache
+    memory:
+      ${[ this.field ]}: 5m
+  - label: escaped
+    memory:
+      init_values:
+        foo: ${{FOO}}
+`), 0o644))
+
+	rdr := NewReader("", []string{resourcePath}, OptSetResourceVariables(map[string]any{
+		"env":   "prod",
+		"field": "default_ttl",
+	}))
+
+	conf := manager.NewResourceConfig()
+	lints, err := rdr.readResources(&conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	require.Len(t, conf.ResourceCaches, 2)
+	assert.Equal(t, "prod_cache", conf.ResourceCaches[0].Label)
+
+	// Escaped environment variables are left for the environment variable
+	// pass to unescape.
+	pluginNode, ok := conf.ResourceCaches[1].Plugin.(*yaml.Node)
+	require.True(t, ok)
+
+	var plugin struct {
+		InitValues map[string]string `yaml:"init_values"`
+	}
+	require.NoError(t, pluginNode.Decode(&plugin))
+	assert.Equal(t, map[string]string{"foo": "${FOO}"}, plugin.InitValues)
+
+	// The expanded config is linted as normal.
+	rconf := manager.NewResourceConfig()
+	rLints, err := readResource(resourcePath, map[string]any{
+		"env":   "dev",
+		"field": "nope",
+	}, &rconf)
+	require.NoError(t, err)
+	assert.Equal(t, []docs.Lint{
+		docs.NewLintError(5, docs.LintUnknown, "field nope not recognised"),
+	}, rLints)
+}
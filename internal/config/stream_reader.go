@@ -44,6 +44,13 @@ func InferStreamID(dir, path string) (string, error) {
 
 // ReadStreamFile attempts to read a stream config and returns the result.
 func ReadStreamFile(path string) (conf stream.Config, lints []string, err error) {
+	conf, lints, _, err = readStreamFileSource(path)
+	return
+}
+
+// readStreamFileSource reads a stream config in the same way as ReadStreamFile
+// and also returns the parsed file as a source of resource labels.
+func readStreamFileSource(path string) (conf stream.Config, lints []string, src labelSource, err error) {
 	conf = stream.NewConfig()
 
 	var confBytes []byte
@@ -69,6 +76,7 @@ func ReadStreamFile(path string) (conf stream.Config, lints []string, err error)
 		}
 	}
 
+	src = newLabelSource(path, confBytes, &rawNode)
 	err = rawNode.Decode(&conf)
 	return
 }
@@ -88,10 +96,11 @@ func (r *Reader) readStreamFile(dir, path string, confs map[string]stream.Config
 		return nil, fmt.Errorf("stream id (%v) collision from file: %v", id, path)
 	}
 
-	conf, lints, err := ReadStreamFile(path)
+	conf, lints, src, err := readStreamFileSource(path)
 	if err != nil {
 		return nil, err
 	}
+	r.labelSources = append(r.labelSources, src)
 
 	strmInfo := streamFileInfo{id: id}
 	// This is an unlikely race condition, see readMain for more info.
@@ -48,7 +48,8 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 					return
 				}
 				switch {
-				case event.Op&fsnotify.Write == fsnotify.Write:
+				case event.Op&fsnotify.Write == fsnotify.Write ||
+					event.Op&fsnotify.Create == fsnotify.Create:
 					collapsedChanges[filepath.Clean(event.Name)] = time.Now()
 
 				case event.Op&fsnotify.Remove == fsnotify.Remove ||
@@ -115,10 +116,28 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 		return err
 	}
 	for _, p := range resourcePaths {
+		if isHTTPResourcePath(p) {
+			continue
+		}
 		if err := watcher.Add(p); err != nil {
 			_ = watcher.Close()
 			return err
 		}
 	}
+
+	// Also watch the directories of resource paths so that we're notified of
+	// resource files created after startup.
+	for _, d := range r.resourceDirs() {
+		if err := watcher.Add(d); err != nil {
+			mgr.Logger().Warnf("Failed to watch resource directory %v for new files: %v", d, err)
+		}
+	}
+
+	// Resource files obtained over HTTP cannot be watched and are polled for
+	// changes instead.
+	if urls := r.httpResourcePaths(); len(urls) > 0 {
+		r.resourcePollStop = make(chan struct{})
+		go r.pollHTTPResources(mgr, strict, urls, r.resourcePollStop)
+	}
 	return nil
 }
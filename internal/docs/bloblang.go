@@ -2,9 +2,11 @@ package docs
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"text/template"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/parser"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 )
@@ -21,7 +23,7 @@ func LintBloblangMapping(ctx LintContext, line, col int, v any) []Lint {
 	}
 	_, err := ctx.BloblangEnv.NewMapping(str)
 	if err == nil {
-		return nil
+		return lintDeprecatedBloblang(ctx, line, col, str)
 	}
 	if mErr, ok := err.(*parser.Error); ok {
 		bline, bcol := parser.LineAndColOf([]rune(str), mErr.Input)
@@ -32,6 +34,97 @@ func LintBloblangMapping(ctx LintContext, line, col int, v any) []Lint {
 	return []Lint{NewLintError(line, LintBadBloblang, err.Error())}
 }
 
+// deprecatedBloblangNames returns the names of the functions and methods of a
+// Bloblang environment that are deprecated.
+func deprecatedBloblangNames(env *bloblang.Environment) (functions, methods map[string]struct{}) {
+	functions, methods = map[string]struct{}{}, map[string]struct{}{}
+	env.WalkFunctions(func(name string, spec query.FunctionSpec) {
+		if spec.Status == query.StatusDeprecated {
+			functions[name] = struct{}{}
+		}
+	})
+	env.WalkMethods(func(name string, spec query.MethodSpec) {
+		if spec.Status == query.StatusDeprecated {
+			methods[name] = struct{}{}
+		}
+	})
+	return
+}
+
+func isBloblangIdentRune(r rune, first bool) bool {
+	if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+		return true
+	}
+	return !first && r >= '0' && r <= '9'
+}
+
+// lintDeprecatedBloblang returns lints for each invocation of a deprecated
+// function or method within a successfully parsed mapping, positioned at the
+// name of the function or method. Invocations are found by scanning for names
+// followed by an opening parenthesis, where names preceded by a dot are
+// methods, and string literals and comments are skipped.
+func lintDeprecatedBloblang(ctx LintContext, line, col int, str string) []Lint {
+	functions, methods := deprecatedBloblangNames(ctx.BloblangEnv)
+	if len(functions) == 0 && len(methods) == 0 {
+		return nil
+	}
+
+	newLint := NewLintWarning
+	if ctx.RejectDeprecated {
+		newLint = NewLintError
+	}
+
+	var lints []Lint
+	input := []rune(str)
+	for i := 0; i < len(input); i++ {
+		switch r := input[i]; {
+		case r == '#':
+			for i < len(input) && input[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			if i+2 < len(input) && input[i+1] == '"' && input[i+2] == '"' {
+				i += 3
+				for i+2 < len(input) && string(input[i:i+3]) != `"""` {
+					i++
+				}
+				i += 2
+				continue
+			}
+			for i++; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\\' {
+					i++
+				}
+			}
+		case isBloblangIdentRune(r, true) && (i == 0 || !isBloblangIdentRune(input[i-1], false)):
+			start := i
+			for i < len(input) && isBloblangIdentRune(input[i], false) {
+				i++
+			}
+			name := string(input[start:i])
+			i--
+			if i+1 >= len(input) || input[i+1] != '(' {
+				continue
+			}
+
+			what := "function"
+			deprecated := functions
+			if start > 0 && input[start-1] == '.' {
+				what, deprecated = "method", methods
+			}
+			if _, exists := deprecated[name]; !exists {
+				continue
+			}
+
+			bline, bcol := parser.LineAndColOf(input, input[start:])
+			lint := newLint(line+bline-1, LintDeprecated, fmt.Sprintf("%v %v is deprecated", what, name))
+			lint.Column = col + bcol
+			lints = append(lints, lint)
+		}
+	}
+	return lints
+}
+
 // LintBloblangField is function for linting a config field expected to be an
 // interpolation string.
 func LintBloblangField(ctx LintContext, line, col int, v any) []Lint {
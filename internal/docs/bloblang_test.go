@@ -0,0 +1,80 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+)
+
+func TestLintBloblangMappingDeprecated(t *testing.T) {
+	env := bloblang.NewEmptyEnvironment()
+	require.NoError(t, env.RegisterFunction(
+		query.NewDeprecatedFunctionSpec("old_fn", ""),
+		func(args *query.ParsedParams) (query.Function, error) {
+			return query.NewLiteralFunction("", "foo"), nil
+		},
+	))
+	require.NoError(t, env.RegisterFunction(
+		query.NewFunctionSpec(query.FunctionCategoryGeneral, "new_fn", ""),
+		func(args *query.ParsedParams) (query.Function, error) {
+			return query.NewLiteralFunction("", "foo"), nil
+		},
+	))
+	require.NoError(t, env.RegisterMethod(
+		query.NewDeprecatedMethodSpec("old_method", ""),
+		func(target query.Function, args *query.ParsedParams) (query.Function, error) {
+			return target, nil
+		},
+	))
+
+	lintCtx := NewLintContext()
+	lintCtx.BloblangEnv = env
+
+	tests := []struct {
+		name     string
+		mapping  string
+		expected []Lint
+	}{
+		{
+			name:    "no deprecated",
+			mapping: `root = new_fn()`,
+		},
+		{
+			name:    "deprecated function",
+			mapping: `root = old_fn()`,
+			expected: []Lint{
+				{Line: 1, Column: 9, Level: LintWarning, Type: LintDeprecated, What: "function old_fn is deprecated"},
+			},
+		},
+		{
+			name: "deprecated method on second line",
+			mapping: `root.a = new_fn()
+root.b = new_fn().old_method()`,
+			expected: []Lint{
+				{Line: 2, Column: 20, Level: LintWarning, Type: LintDeprecated, What: "method old_method is deprecated"},
+			},
+		},
+		{
+			name: "within strings and comments",
+			mapping: `# old_fn()
+root.a = "old_fn()"
+root.b = """old_fn()"""`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, LintBloblangMapping(lintCtx, 1, 1, test.mapping))
+		})
+	}
+
+	lintCtx.RejectDeprecated = true
+	lints := LintBloblangMapping(lintCtx, 1, 1, `root = old_fn()`)
+	require.Len(t, lints, 1)
+	assert.Equal(t, LintError, lints[0].Level)
+}
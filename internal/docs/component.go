@@ -84,6 +84,10 @@ type ComponentSpec struct {
 	// The status of the component.
 	Status Status `json:"status"`
 
+	// ExperimentalWarning is true for experimental components that should log a
+	// warning when used, in order to surface the risk to operators.
+	ExperimentalWarning bool `json:"experimental_warning,omitempty"`
+
 	// Plugin is true for all plugin components.
 	Plugin bool `json:"plugin"`
 
@@ -2,7 +2,12 @@ package docs
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
@@ -91,6 +96,10 @@ type FieldSpec struct {
 	// for backwards compatibility reasons.
 	IsDeprecated bool `json:"is_deprecated,omitempty"`
 
+	// ReplacedBy is an optional path of a field that replaces this deprecated
+	// field, which is used in order to provide migration hints.
+	ReplacedBy string `json:"replaced_by,omitempty"`
+
 	// IsOptional is a boolean flag indicating that a field is optional, even
 	// if there is no default. This prevents linting errors when the field
 	// is missing.
@@ -129,8 +138,14 @@ type FieldSpec struct {
 	// a field.
 	Linter string `json:"linter,omitempty"`
 
-	omitWhenFn   func(field, parent any) (why string, shouldOmit bool)
-	customLintFn LintFunc
+	omitWhenFn    func(field, parent any) (why string, shouldOmit bool)
+	customLintFn  LintFunc
+	optionsLinted bool
+	scalarOrArray bool
+	fieldGroups   []fieldGroup
+	requiredWhen  []requiredCondition
+	arrayLength   *arrayLengthRange
+	lintWhenEmpty bool
 }
 
 // IsInterpolated indicates that the field supports interpolation functions.
@@ -184,12 +199,29 @@ func (f FieldSpec) Deprecated() FieldSpec {
 	return f
 }
 
+// DeprecatedReplacedBy marks this field as being deprecated and replaced by a
+// field at a given path, which is included in deprecation lint messages.
+func (f FieldSpec) DeprecatedReplacedBy(path string) FieldSpec {
+	f = f.Deprecated()
+	f.ReplacedBy = path
+	return f
+}
+
 // Array determines that this field is an array of the field type.
 func (f FieldSpec) Array() FieldSpec {
 	f.Kind = KindArray
 	return f
 }
 
+// ScalarOrArray determines that this field is an array of the field type,
+// where a single scalar value is also accepted and is treated as an array
+// containing only that value.
+func (f FieldSpec) ScalarOrArray() FieldSpec {
+	f.Kind = KindArray
+	f.scalarOrArray = true
+	return f
+}
+
 // ArrayOfArrays determines that this is an array of arrays of the field type.
 func (f FieldSpec) ArrayOfArrays() FieldSpec {
 	f.Kind = Kind2DArray
@@ -284,6 +316,16 @@ func (f FieldSpec) OmitWhen(fn func(field, parent any) (why string, shouldOmit b
 // schema.
 func (f FieldSpec) LinterFunc(fn LintFunc) FieldSpec {
 	f.customLintFn = fn
+	f.optionsLinted = false
+	return f
+}
+
+// LintWhenEmpty marks an interpolated field as expected to resolve to a
+// non-empty string, where linting with strict interpolation enabled warns when
+// the interpolation of the field always resolves empty. See
+// LintInterpolationWhenEmpty for the conditions under which this is detected.
+func (f FieldSpec) LintWhenEmpty() FieldSpec {
+	f.lintWhenEmpty = true
 	return f
 }
 
@@ -302,18 +344,62 @@ func (f FieldSpec) LinterFunc(fn LintFunc) FieldSpec {
 // binary that defines it as the function cannot be serialized into a portable
 // schema.
 func (f FieldSpec) LinterBlobl(blobl string) FieldSpec {
+	fn, err := newBloblLintFunc(blobl)
+	if err == nil {
+		f.Linter = blobl
+	}
+	f.customLintFn = fn
+	f.optionsLinted = false
+	return f
+}
+
+// LintsOfType sets the type of the lints returned by the linting function of a
+// field that would otherwise be of the type LintCustom, which is useful for
+// categorising lints with a type registered with RegisterLintType.
+func (f FieldSpec) LintsOfType(t LintType) FieldSpec {
+	fn := f.customLintFn
+	if fn == nil {
+		return f
+	}
+	f.customLintFn = func(ctx LintContext, line, col int, value any) []Lint {
+		lints := fn(ctx, line, col, value)
+		for i := range lints {
+			if lints[i].Type == LintCustom {
+				lints[i].Type = t
+			}
+		}
+		return lints
+	}
+	return f
+}
+
+// bloblLintFuncs caches the lint functions of Bloblang linter mappings by
+// their source, so that specs with a Linter but no custom lint function do not
+// reparse the mapping each time they're linted.
+var bloblLintFuncs sync.Map
+
+// cachedBloblLintFunc returns a lint function for a Bloblang linter mapping,
+// parsing the mapping only the first time it is encountered.
+func cachedBloblLintFunc(blobl string) LintFunc {
+	if fn, exists := bloblLintFuncs.Load(blobl); exists {
+		return fn.(LintFunc)
+	}
+	fn, _ := newBloblLintFunc(blobl)
+	actual, _ := bloblLintFuncs.LoadOrStore(blobl, fn)
+	return actual.(LintFunc)
+}
+
+func newBloblLintFunc(blobl string) (LintFunc, error) {
 	env := bloblang.NewEnvironment().OnlyPure()
 
 	m, err := env.NewMapping(blobl)
 	if err != nil {
-		f.customLintFn = func(ctx LintContext, line, col int, value any) (lints []Lint) {
+		return func(ctx LintContext, line, col int, value any) (lints []Lint) {
 			return []Lint{NewLintError(line, LintCustom, fmt.Sprintf("Field lint mapping itself failed to parse: %v", err))}
-		}
-		return f
+		}, err
 	}
 
-	f.Linter = blobl
-	f.customLintFn = func(ctx LintContext, line, col int, value any) (lints []Lint) {
+	return func(ctx LintContext, line, col int, value any) (lints []Lint) {
 		res, err := m.Exec(query.FunctionContext{
 			Vars:     map[string]any{},
 			Maps:     map[string]query.Function{},
@@ -335,6 +421,77 @@ func (f FieldSpec) LinterBlobl(blobl string) FieldSpec {
 			}
 		}
 		return
+	}, nil
+}
+
+// LinterNumericRange adds a linting function to a field that checks numeric
+// values are within an inclusive range. Values that are not numbers, such as
+// the array value of a non-scalar field, are ignored.
+func (f FieldSpec) LinterNumericRange(min, max float64) FieldSpec {
+	f.optionsLinted = false
+	f.customLintFn = func(ctx LintContext, line, col int, value any) []Lint {
+		n, ok := numericLintValue(value)
+		if !ok {
+			return nil
+		}
+		if n < min || n > max {
+			return []Lint{NewLintError(line, LintInvalidOption, fmt.Sprintf("value %v is outside of the range %v to %v", value, min, max))}
+		}
+		return nil
+	}
+	return f
+}
+
+type arrayLengthRange struct {
+	min, max int
+}
+
+// LinterArrayLength adds a lint to an array or 2D array field that checks the
+// number of elements of the array is within an inclusive range, where a
+// negative max indicates no upper bound. Unlike LinterFunc the lint is only
+// executed on the array itself, and not its elements or any nested arrays.
+func (f FieldSpec) LinterArrayLength(min, max int) FieldSpec {
+	f.arrayLength = &arrayLengthRange{min: min, max: max}
+	return f
+}
+
+// numericLintValue attempts to obtain a float from a decoded numeric value.
+func numericLintValue(value any) (float64, bool) {
+	switch t := value.(type) {
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+	return 0, false
+}
+
+// LinterPattern adds a linting function to a field that checks string values
+// match a regular expression. Values that are not strings, such as the array
+// value of a non-scalar field, are ignored.
+func (f FieldSpec) LinterPattern(pattern string) FieldSpec {
+	f.optionsLinted = false
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		f.customLintFn = func(ctx LintContext, line, col int, value any) []Lint {
+			return []Lint{NewLintError(line, LintCustom, fmt.Sprintf("Field lint pattern itself failed to compile: %v", err))}
+		}
+		return f
+	}
+
+	f.customLintFn = func(ctx LintContext, line, col int, value any) []Lint {
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		if !re.MatchString(str) {
+			return []Lint{NewLintError(line, LintInvalidOption, fmt.Sprintf("value %v does not match the pattern %v", str, pattern))}
+		}
+		return nil
 	}
 	return f
 }
@@ -344,22 +501,24 @@ func (f FieldSpec) LinterBlobl(blobl string) FieldSpec {
 // because some fields express options that are only a subset due to deprecated
 // functionality.
 func (f FieldSpec) lintOptions() FieldSpec {
+	options := f.Options
+	if len(options) == 0 {
+		for _, optStr := range f.AnnotatedOptions {
+			options = append(options, optStr[0])
+		}
+	}
+	f.optionsLinted = true
+	if f.Type == FieldTypeInt || f.Type == FieldTypeFloat {
+		return f.lintNumericOptions(options)
+	}
 	f.customLintFn = func(ctx LintContext, line, col int, value any) []Lint {
 		str, ok := value.(string)
 		if !ok {
 			return nil
 		}
-		if len(f.Options) > 0 {
-			for _, optStr := range f.Options {
-				if str == optStr {
-					return nil
-				}
-			}
-		} else {
-			for _, optStr := range f.AnnotatedOptions {
-				if str == optStr[0] {
-					return nil
-				}
+		for _, optStr := range options {
+			if str == optStr {
+				return nil
 			}
 		}
 		return []Lint{NewLintError(line, LintInvalidOption, fmt.Sprintf("value %v is not a valid option for this field", str))}
@@ -367,6 +526,31 @@ func (f FieldSpec) lintOptions() FieldSpec {
 	return f
 }
 
+// lintNumericOptions enforces that a numeric field value matches one of the
+// provided options once parsed as numbers. Options that cannot be parsed are
+// ignored.
+func (f FieldSpec) lintNumericOptions(options []string) FieldSpec {
+	var numOptions []float64
+	for _, optStr := range options {
+		if n, err := strconv.ParseFloat(optStr, 64); err == nil {
+			numOptions = append(numOptions, n)
+		}
+	}
+	f.customLintFn = func(ctx LintContext, line, col int, value any) []Lint {
+		n, ok := numericLintValue(value)
+		if !ok {
+			return nil
+		}
+		for _, opt := range numOptions {
+			if n == opt {
+				return nil
+			}
+		}
+		return []Lint{NewLintError(line, LintInvalidOption, fmt.Sprintf("value %v is not a valid option for this field", value))}
+	}
+	return f
+}
+
 var (
 	envRegex = regexp.MustCompile(`^\${[0-9A-Za-z_.]+(:((\${[^}]+})|[^}])+)?}$`)
 )
@@ -381,23 +565,31 @@ func (f FieldSpec) scrubValue(value string) string {
 func (f FieldSpec) getLintFunc() LintFunc {
 	fn := f.customLintFn
 	if fn == nil && len(f.Linter) > 0 {
-		fn = f.LinterBlobl(f.Linter).customLintFn
+		fn = cachedBloblLintFunc(f.Linter)
 	}
 	if f.Interpolated {
-		if fn != nil {
+		if customFn := fn; customFn != nil {
 			fn = func(ctx LintContext, line, col int, value any) []Lint {
-				lints := f.customLintFn(ctx, line, col, value)
+				lints := customFn(ctx, line, col, value)
 				moreLints := LintBloblangField(ctx, line, col, value)
 				return append(lints, moreLints...)
 			}
 		} else {
 			fn = LintBloblangField
 		}
+		if f.lintWhenEmpty {
+			customFn := fn
+			fn = func(ctx LintContext, line, col int, value any) []Lint {
+				lints := customFn(ctx, line, col, value)
+				moreLints := LintInterpolationWhenEmpty(ctx, line, col, value)
+				return append(lints, moreLints...)
+			}
+		}
 	}
 	if f.Bloblang {
-		if fn != nil {
+		if customFn := fn; customFn != nil {
 			fn = func(ctx LintContext, line, col int, value any) []Lint {
-				lints := f.customLintFn(ctx, line, col, value)
+				lints := customFn(ctx, line, col, value)
 				moreLints := LintBloblangMapping(ctx, line, col, value)
 				return append(lints, moreLints...)
 			}
@@ -429,6 +621,24 @@ func FieldInterpolatedString(name, description string, examples ...any) FieldSpe
 	return newField(name, description, examples...).HasType(FieldTypeString).IsInterpolated()
 }
 
+// FieldDuration returns a field spec for a string typed field containing a
+// duration, which is linted for values that cannot be parsed. Empty values and
+// values containing interpolations are not linted.
+func FieldDuration(name, description string, examples ...any) FieldSpec {
+	return newField(name, description, examples...).HasType(FieldTypeString).LinterFunc(lintDuration)
+}
+
+func lintDuration(ctx LintContext, line, col int, value any) []Lint {
+	str, ok := value.(string)
+	if !ok || str == "" || strings.Contains(str, "${") {
+		return nil
+	}
+	if _, err := time.ParseDuration(str); err != nil {
+		return []Lint{NewLintError(line, LintInvalidOption, fmt.Sprintf("value %v is not a valid duration: %v", str, err))}
+	}
+	return nil
+}
+
 // FieldBloblang returns a field spec for a string typed field containing a
 // Bloblang mapping.
 func FieldBloblang(name, description string, examples ...any) FieldSpec {
@@ -590,16 +800,37 @@ type LintContext struct {
 
 	// Require labels for components.
 	RequireLabels bool
+
+	// Report interpolations that may resolve to malformed values, such as
+	// object keys containing stray slashes, as linting warnings.
+	StrictInterpolation bool
+
+	// Report secret fields that are set to plaintext values rather than
+	// environment variable interpolations as linting warnings. This lint is
+	// only meaningful when environment variables within a config have not yet
+	// been replaced.
+	PlaintextSecrets bool
+
+	// Report scalar values that do not match the type of their field, such as
+	// a string given for an int field, and missing fields of any kind that are
+	// neither optional nor have a default, as linting errors. This is stricter
+	// than config linting requires, as scalar config values are commonly
+	// coerced and objects are populated by the defaults of their children, and
+	// is intended for validating structured documents.
+	StrictStructure bool
 }
 
 // NewLintContext creates a new linting context.
 func NewLintContext() LintContext {
 	return LintContext{
-		LabelsToLine:     map[string]int{},
-		DocsProvider:     DeprecatedProvider,
-		BloblangEnv:      bloblang.GlobalEnvironment().Deactivated(),
-		RejectDeprecated: false,
-		RequireLabels:    false,
+		LabelsToLine:        map[string]int{},
+		DocsProvider:        DeprecatedProvider,
+		BloblangEnv:         bloblang.GlobalEnvironment().Deactivated(),
+		RejectDeprecated:    false,
+		RequireLabels:       false,
+		StrictInterpolation: false,
+		PlaintextSecrets:    false,
+		StrictStructure:     false,
 	}
 }
 
@@ -672,6 +903,25 @@ const (
 
 	// LintDeprecated means a field is deprecated and should not be used.
 	LintDeprecated LintType = iota
+
+	// LintMissingEnvVar means an environment variable was referenced without a
+	// default value but is not set.
+	LintMissingEnvVar LintType = iota
+
+	// LintBadInterpolation means an interpolation may resolve to a malformed
+	// value.
+	LintBadInterpolation LintType = iota
+
+	// LintPlaintextSecret means a secret field was set to a plaintext value
+	// rather than an environment variable interpolation.
+	LintPlaintextSecret LintType = iota
+
+	// LintUnusedResource means a resource is defined but never referenced.
+	LintUnusedResource LintType = iota
+
+	// lintTypeCustomStart is the first value of lint types registered with
+	// RegisterLintType, and must remain after all core lint types.
+	lintTypeCustomStart LintType = iota
 )
 
 // Lint describes a single linting issue found with a Benthos config.
@@ -711,10 +961,44 @@ func (f FieldSpec) needsDefault() bool {
 	return true
 }
 
+// cloneDefault performs a deep copy of a default value, ensuring that configs
+// populated with the default of a map or slice field do not share references.
+func cloneDefault(v any) any {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		newMap := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			newMap.SetMapIndex(iter.Key(), cloneDefaultValue(iter.Value()))
+		}
+		return newMap.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		newSlice := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			newSlice.Index(i).Set(cloneDefaultValue(rv.Index(i)))
+		}
+		return newSlice.Interface()
+	}
+	return v
+}
+
+func cloneDefaultValue(v reflect.Value) reflect.Value {
+	if (v.Kind() == reflect.Interface || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return v
+	}
+	return reflect.ValueOf(cloneDefault(v.Interface()))
+}
+
 func getDefault(pathName string, field FieldSpec) (any, error) {
 	if field.Default != nil {
-		// TODO: Should be deep copy here?
-		return *field.Default, nil
+		return cloneDefault(*field.Default), nil
 	} else if field.Kind == KindArray {
 		return []any{}, nil
 	} else if field.Kind == Kind2DArray {
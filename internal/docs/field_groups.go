@@ -0,0 +1,103 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type fieldGroupRule int
+
+const (
+	fieldGroupExclusive fieldGroupRule = iota
+	fieldGroupExactlyOne
+	fieldGroupTogether
+)
+
+type fieldGroup struct {
+	rule  fieldGroupRule
+	names []string
+}
+
+func (f FieldSpec) withFieldGroup(rule fieldGroupRule, names []string) FieldSpec {
+	groups := make([]fieldGroup, 0, len(f.fieldGroups)+1)
+	groups = append(groups, f.fieldGroups...)
+	f.fieldGroups = append(groups, fieldGroup{rule: rule, names: names})
+	return f
+}
+
+// ExclusiveFields declares that at most one of the named children of an object
+// field may be set within a config.
+func (f FieldSpec) ExclusiveFields(names ...string) FieldSpec {
+	return f.withFieldGroup(fieldGroupExclusive, names)
+}
+
+// ExactlyOneOfFields declares that exactly one of the named children of an
+// object field must be set within a config.
+func (f FieldSpec) ExactlyOneOfFields(names ...string) FieldSpec {
+	return f.withFieldGroup(fieldGroupExactlyOne, names)
+}
+
+// FieldsRequiredTogether declares that the named children of an object field
+// must either all be set within a config or none of them.
+func (f FieldSpec) FieldsRequiredTogether(names ...string) FieldSpec {
+	return f.withFieldGroup(fieldGroupTogether, names)
+}
+
+// isFieldValueSet returns whether a field value node should be considered as
+// explicitly set, where null and empty values are treated as unset.
+func isFieldValueSet(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag != "!!null" && node.Value != ""
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) > 0
+	}
+	return true
+}
+
+func (f FieldSpec) lintFieldGroups(node *yaml.Node) []Lint {
+	if len(f.fieldGroups) == 0 || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	setFields := map[string]struct{}{}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if isFieldValueSet(node.Content[i+1]) {
+			setFields[node.Content[i].Value] = struct{}{}
+		}
+	}
+
+	var lints []Lint
+	for _, g := range f.fieldGroups {
+		var set, unset []string
+		for _, name := range g.names {
+			if _, exists := setFields[name]; exists {
+				set = append(set, name)
+			} else {
+				unset = append(unset, name)
+			}
+		}
+
+		var what string
+		switch g.rule {
+		case fieldGroupExclusive:
+			if len(set) > 1 {
+				what = fmt.Sprintf("fields %v are mutually exclusive, but %v were set", strings.Join(g.names, ", "), strings.Join(set, ", "))
+			}
+		case fieldGroupExactlyOne:
+			if len(set) != 1 {
+				what = fmt.Sprintf("exactly one of fields %v must be set", strings.Join(g.names, ", "))
+			}
+		case fieldGroupTogether:
+			if len(set) > 0 && len(unset) > 0 {
+				what = fmt.Sprintf("fields %v must be set together, but %v were not set", strings.Join(g.names, ", "), strings.Join(unset, ", "))
+			}
+		}
+		if what != "" {
+			lints = append(lints, NewLintError(node.Line, LintCustom, what))
+		}
+	}
+	return lints
+}
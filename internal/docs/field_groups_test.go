@@ -0,0 +1,106 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func TestFieldGroupsLinting(t *testing.T) {
+	spec := docs.FieldObject("", "").WithChildren(
+		docs.FieldString("a", "").Optional(),
+		docs.FieldString("b", "").Array().Optional(),
+		docs.FieldString("c", "").Optional(),
+		docs.FieldString("d", "").Optional(),
+	)
+
+	tests := []struct {
+		name     string
+		spec     docs.FieldSpec
+		input    string
+		expected []docs.Lint
+	}{
+		{
+			name:  "exclusive one set",
+			spec:  spec.ExclusiveFields("a", "b"),
+			input: `a: foo`,
+		},
+		{
+			name: "exclusive empty values ignored",
+			spec: spec.ExclusiveFields("a", "b"),
+			input: `
+a: foo
+b: []`,
+		},
+		{
+			name: "exclusive both set",
+			spec: spec.ExclusiveFields("a", "b"),
+			input: `
+a: foo
+b: [ bar ]`,
+			expected: []docs.Lint{
+				docs.NewLintError(2, docs.LintCustom, "fields a, b are mutually exclusive, but a, b were set"),
+			},
+		},
+		{
+			name:  "exactly one set",
+			spec:  spec.ExactlyOneOfFields("a", "b"),
+			input: `b: [ bar ]`,
+		},
+		{
+			name:  "exactly one none set",
+			spec:  spec.ExactlyOneOfFields("a", "b"),
+			input: `c: baz`,
+			expected: []docs.Lint{
+				docs.NewLintError(1, docs.LintCustom, "exactly one of fields a, b must be set"),
+			},
+		},
+		{
+			name: "together all set",
+			spec: spec.FieldsRequiredTogether("c", "d"),
+			input: `
+c: foo
+d: bar`,
+		},
+		{
+			name:  "together none set",
+			spec:  spec.FieldsRequiredTogether("c", "d"),
+			input: `a: foo`,
+		},
+		{
+			name:  "together some set",
+			spec:  spec.FieldsRequiredTogether("c", "d"),
+			input: `c: foo`,
+			expected: []docs.Lint{
+				docs.NewLintError(1, docs.LintCustom, "fields c, d must be set together, but d were not set"),
+			},
+		},
+		{
+			name: "multiple groups",
+			spec: spec.ExclusiveFields("a", "b").FieldsRequiredTogether("c", "d"),
+			input: `
+a: foo
+b: [ bar ]
+d: baz`,
+			expected: []docs.Lint{
+				docs.NewLintError(2, docs.LintCustom, "fields a, b are mutually exclusive, but a, b were set"),
+				docs.NewLintError(2, docs.LintCustom, "fields c, d must be set together, but c were not set"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(test.input), &node))
+
+			lints := test.spec.LintYAML(docs.NewLintContext(), &node)
+			assert.Equal(t, test.expected, lints)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type requiredCondition struct {
+	path  string
+	value any
+}
+
+// RequiredWhen declares that the field is required when a sibling field, which
+// is identified by a dot separated path relative to the parent object of the
+// field, is set to a given value. When the sibling field is absent its default
+// value is used instead. The field is otherwise optional, and multiple
+// conditions can be declared, in which case the field is required when any of
+// them hold.
+func (f FieldSpec) RequiredWhen(siblingPath string, value any) FieldSpec {
+	conds := make([]requiredCondition, 0, len(f.requiredWhen)+1)
+	conds = append(conds, f.requiredWhen...)
+	f.requiredWhen = append(conds, requiredCondition{path: siblingPath, value: value})
+	f.IsOptional = true
+	return f
+}
+
+// siblingValue returns the value of a field at a dot separated path relative to
+// a parent object node, falling back to the default value of the field when it
+// is absent.
+func siblingValue(specs FieldSpecs, parent *yaml.Node, path string) (any, bool) {
+	var spec *FieldSpec
+	node := parent
+	for _, seg := range strings.Split(path, ".") {
+		spec = nil
+		for i := range specs {
+			if specs[i].Name == seg {
+				spec = &specs[i]
+				break
+			}
+		}
+
+		var child *yaml.Node
+		if node != nil && node.Kind == yaml.MappingNode {
+			for i := 0; i < len(node.Content)-1; i += 2 {
+				if node.Content[i].Value == seg {
+					child = node.Content[i+1]
+					break
+				}
+			}
+		}
+		node = child
+		if spec != nil {
+			specs = spec.Children
+		} else {
+			specs = nil
+		}
+	}
+
+	if node != nil {
+		if node.Kind != yaml.ScalarNode {
+			return nil, false
+		}
+		return node.Value, true
+	}
+	if spec != nil && spec.Default != nil {
+		return *spec.Default, true
+	}
+	return nil, false
+}
+
+// lintRequiredWhen returns a lint for a field that is absent from its parent
+// object whilst a condition under which it is required holds.
+func (f FieldSpec) lintRequiredWhen(siblings FieldSpecs, parent *yaml.Node) []Lint {
+	for _, cond := range f.requiredWhen {
+		v, exists := siblingValue(siblings, parent, cond.path)
+		if !exists || fmt.Sprint(v) != fmt.Sprint(cond.value) {
+			continue
+		}
+		return []Lint{NewLintError(parent.Line, LintMissing, fmt.Sprintf("field %v is required when %v is %v", f.Name, cond.path, cond.value))}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func TestFieldRequiredWhenLinting(t *testing.T) {
+	spec := docs.FieldObject("", "").WithChildren(
+		docs.FieldString("auth", "").HasOptions("none", "basic", "token").HasDefault("none"),
+		docs.FieldString("username", "").RequiredWhen("auth", "basic"),
+		docs.FieldString("token", "").RequiredWhen("auth", "token"),
+		docs.FieldObject("tls", "").WithChildren(
+			docs.FieldBool("enabled", "").HasDefault(false),
+		),
+		docs.FieldString("root_cas", "").RequiredWhen("tls.enabled", true),
+	)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []docs.Lint
+	}{
+		{
+			name:  "condition does not hold",
+			input: `auth: none`,
+		},
+		{
+			name:  "default does not satisfy condition",
+			input: `{}`,
+		},
+		{
+			name: "condition holds and field set",
+			input: `
+auth: basic
+username: foo`,
+		},
+		{
+			name:  "condition holds and field missing",
+			input: `auth: token`,
+			expected: []docs.Lint{
+				docs.NewLintError(1, docs.LintMissing, "field token is required when auth is token"),
+			},
+		},
+		{
+			name: "nested condition holds",
+			input: `
+tls:
+  enabled: true`,
+			expected: []docs.Lint{
+				docs.NewLintError(2, docs.LintMissing, "field root_cas is required when tls.enabled is true"),
+			},
+		},
+		{
+			name: "nested condition does not hold",
+			input: `
+tls:
+  enabled: false`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(test.input), &node))
+
+			assert.Equal(t, test.expected, spec.LintYAML(docs.NewLintContext(), &node))
+		})
+	}
+
+	defaultSpec := docs.FieldObject("", "").WithChildren(
+		docs.FieldString("mode", "").HasDefault("strict"),
+		docs.FieldString("schema", "").RequiredWhen("mode", "strict"),
+	)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`{}`), &node))
+	assert.Equal(t, []docs.Lint{
+		docs.NewLintError(1, docs.LintMissing, "field schema is required when mode is strict"),
+	}, defaultSpec.LintYAML(docs.NewLintContext(), &node))
+}
@@ -1,6 +1,7 @@
 package docs
 
 import (
+	"reflect"
 	"strings"
 
 	"github.com/Jeffail/gabs/v2"
@@ -71,6 +72,30 @@ Type: {{if eq $field.Spec.Kind "array"}}list of {{end}}{{if eq $field.Spec.Kind
 {{end -}}`
 }
 
+// exampleForKind wraps a scalar example of a field within an array or map
+// according to the kind of the field, so that it renders as a valid value of
+// the field. Examples that are already arrays or maps are returned unchanged.
+func (f FieldSpec) exampleForKind(example any) any {
+	if example == nil {
+		return nil
+	}
+	switch f.Kind {
+	case KindArray:
+		if k := reflect.TypeOf(example).Kind(); k != reflect.Slice && k != reflect.Array {
+			return []any{example}
+		}
+	case Kind2DArray:
+		if k := reflect.TypeOf(example).Kind(); k != reflect.Slice && k != reflect.Array {
+			return []any{[]any{example}}
+		}
+	case KindMap:
+		if reflect.TypeOf(example).Kind() != reflect.Map {
+			return map[string]any{"example": example}
+		}
+	}
+	return example
+}
+
 // FlattenChildrenForDocs converts the children of a field into a flat list,
 // where the names contain hints as to their position in a structured hierarchy.
 // This makes it easier to list the fields in documentation.
@@ -93,7 +118,7 @@ func (f FieldSpec) FlattenChildrenForDocs() []FieldSpecCtx {
 				newV.ExamplesMarshalled = make([]string, len(v.Examples))
 				for i, e := range v.Examples {
 					exampleBytes, err := marshalYAML(map[string]any{
-						v.Name: e,
+						v.Name: v.exampleForKind(e),
 					})
 					if err == nil {
 						newV.ExamplesMarshalled[i] = string(exampleBytes)
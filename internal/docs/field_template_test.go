@@ -0,0 +1,35 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenChildrenExamplesForKind(t *testing.T) {
+	spec := FieldComponent().WithChildren(
+		FieldString("a", "", "foo"),
+		FieldString("b", "", "foo", []string{"bar", "baz"}).Array(),
+		FieldString("c", "", "foo").ArrayOfArrays(),
+		FieldString("d", "", "foo", map[string]any{"bar": "baz"}).Map(),
+		FieldObject("e", "", map[string]any{"f": "foo"}).WithChildren(
+			FieldString("f", ""),
+		).Array(),
+	)
+
+	fields := spec.FlattenChildrenForDocs()
+	require.Len(t, fields, 6)
+
+	assert.Equal(t, []string{"a: foo\n"}, fields[0].ExamplesMarshalled)
+	assert.Equal(t, []string{
+		"b:\n  - foo\n",
+		"b:\n  - bar\n  - baz\n",
+	}, fields[1].ExamplesMarshalled)
+	assert.Equal(t, []string{"c:\n  - - foo\n"}, fields[2].ExamplesMarshalled)
+	assert.Equal(t, []string{
+		"d:\n  example: foo\n",
+		"d:\n  bar: baz\n",
+	}, fields[3].ExamplesMarshalled)
+	assert.Equal(t, []string{"e:\n  - f: foo\n"}, fields[4].ExamplesMarshalled)
+}
@@ -67,3 +67,432 @@ func TestBloblLinter(t *testing.T) {
 		})
 	}
 }
+
+func TestBloblLinterCached(t *testing.T) {
+	f := FieldInterpolatedString("foo", "")
+	f.Linter = `root = if this.contains("meow") { "no cats allowed" }`
+
+	lintCtx := NewLintContext()
+
+	for i := 0; i < 3; i++ {
+		var node yaml.Node
+		require.NoError(t, node.Encode("hello meow ${! content() }"))
+
+		assert.Equal(t, []Lint{
+			NewLintError(0, LintCustom, "no cats allowed"),
+		}, f.LintYAML(lintCtx, &node))
+	}
+
+	fn, exists := bloblLintFuncs.Load(f.Linter)
+	require.True(t, exists)
+	assert.NotNil(t, fn)
+}
+
+func TestNumericRangeLinter(t *testing.T) {
+	lintCtx := NewLintContext()
+
+	tests := []struct {
+		name     string
+		field    FieldSpec
+		input    any
+		expected []Lint
+	}{
+		{
+			name:  "int within range",
+			field: FieldInt("foo", "").LinterNumericRange(0, 10),
+			input: 5,
+		},
+		{
+			name:  "int on boundary",
+			field: FieldInt("foo", "").LinterNumericRange(0, 10),
+			input: 10,
+		},
+		{
+			name:  "int above range",
+			field: FieldInt("foo", "").LinterNumericRange(0, 10),
+			input: 11,
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value 11 is outside of the range 0 to 10"),
+			},
+		},
+		{
+			name:  "float below range",
+			field: FieldFloat("foo", "").LinterNumericRange(0.5, 1),
+			input: 0.1,
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value 0.1 is outside of the range 0.5 to 1"),
+			},
+		},
+		{
+			name:  "int array",
+			field: FieldInt("foo", "").Array().LinterNumericRange(0, 10),
+			input: []any{1, 20, 5, -1},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value 20 is outside of the range 0 to 10"),
+				NewLintError(0, LintInvalidOption, "value -1 is outside of the range 0 to 10"),
+			},
+		},
+		{
+			name:  "env var string",
+			field: FieldInt("foo", "").LinterNumericRange(0, 10),
+			input: "${FOO}",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, node.Encode(test.input))
+
+			lints := test.field.LintYAML(lintCtx, &node)
+			assert.Equal(t, test.expected, lints)
+		})
+	}
+}
+
+func TestNumericOptionsLinter(t *testing.T) {
+	lintCtx := NewLintContext()
+
+	tests := []struct {
+		name     string
+		field    FieldSpec
+		input    any
+		expected []Lint
+	}{
+		{
+			name:  "int option",
+			field: FieldInt("foo", "").HasOptions("0", "1", "2"),
+			input: 1,
+		},
+		{
+			name:  "int not an option",
+			field: FieldInt("foo", "").HasOptions("0", "1", "2"),
+			input: 3,
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value 3 is not a valid option for this field"),
+			},
+		},
+		{
+			name:  "float option",
+			field: FieldFloat("foo", "").HasAnnotatedOptions("0.5", "half", "1", "whole"),
+			input: 0.5,
+		},
+		{
+			name:  "float matches int option",
+			field: FieldFloat("foo", "").HasAnnotatedOptions("0.5", "half", "1", "whole"),
+			input: 1,
+		},
+		{
+			name:  "float not an option",
+			field: FieldFloat("foo", "").HasAnnotatedOptions("0.5", "half", "1", "whole"),
+			input: 0.75,
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value 0.75 is not a valid option for this field"),
+			},
+		},
+		{
+			name:  "int array",
+			field: FieldInt("foo", "").Array().HasOptions("0", "1", "2"),
+			input: []any{0, 5, 2},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value 5 is not a valid option for this field"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, node.Encode(test.input))
+
+			lints := test.field.LintYAML(lintCtx, &node)
+			assert.Equal(t, test.expected, lints)
+		})
+	}
+}
+
+func TestPatternLinter(t *testing.T) {
+	lintCtx := NewLintContext()
+
+	tests := []struct {
+		name     string
+		field    FieldSpec
+		input    any
+		expected []Lint
+	}{
+		{
+			name:  "matches",
+			field: FieldString("foo", "").LinterPattern(`^[a-z]+$`),
+			input: "foo",
+		},
+		{
+			name:  "does not match",
+			field: FieldString("foo", "").LinterPattern(`^[a-z]+$`),
+			input: "Foo",
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value Foo does not match the pattern ^[a-z]+$"),
+			},
+		},
+		{
+			name:  "array",
+			field: FieldString("foo", "").Array().LinterPattern(`^[a-z]+$`),
+			input: []any{"foo", "b4r", "baz"},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value b4r does not match the pattern ^[a-z]+$"),
+			},
+		},
+		{
+			name:  "map",
+			field: FieldString("foo", "").Map().LinterPattern(`^[a-z]+$`),
+			input: map[string]any{"a": "foo", "b": "BAR"},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "value BAR does not match the pattern ^[a-z]+$"),
+			},
+		},
+		{
+			name:  "bad pattern",
+			field: FieldString("foo", "").LinterPattern(`^[a-z+$`),
+			input: "foo",
+			expected: []Lint{
+				NewLintError(0, LintCustom, "Field lint pattern itself failed to compile: error parsing regexp: missing closing ]: `[a-z+$`"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, node.Encode(test.input))
+
+			lints := test.field.LintYAML(lintCtx, &node)
+			assert.Equal(t, test.expected, lints)
+		})
+	}
+}
+
+func TestDurationLinter(t *testing.T) {
+	lintCtx := NewLintContext()
+
+	tests := []struct {
+		name     string
+		field    FieldSpec
+		input    any
+		expected []Lint
+	}{
+		{
+			name:  "valid duration",
+			field: FieldDuration("foo", ""),
+			input: "10s",
+		},
+		{
+			name:  "empty duration",
+			field: FieldDuration("foo", ""),
+			input: "",
+		},
+		{
+			name:  "invalid duration",
+			field: FieldDuration("foo", ""),
+			input: "10secs",
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, `value 10secs is not a valid duration: time: unknown unit "secs" in duration "10secs"`),
+			},
+		},
+		{
+			name:  "interpolated duration",
+			field: FieldDuration("foo", "").IsInterpolated(),
+			input: `${! meta("timeout") }`,
+		},
+		{
+			name:  "duration array",
+			field: FieldDuration("foo", "").Array(),
+			input: []any{"1s", "nope"},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, `value nope is not a valid duration: time: invalid duration "nope"`),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, node.Encode(test.input))
+
+			lints := test.field.LintYAML(lintCtx, &node)
+			assert.Equal(t, test.expected, lints)
+		})
+	}
+}
+
+func TestGetDefaultDeepCopy(t *testing.T) {
+	f := FieldObject("foo", "").HasDefault(map[string]any{
+		"a": []any{"b", map[string]any{"c": "d"}},
+		"e": []string{"f"},
+	})
+
+	first, err := getDefault("foo", f)
+	require.NoError(t, err)
+
+	firstMap := first.(map[string]any)
+	firstMap["a"].([]any)[1].(map[string]any)["c"] = "changed"
+	firstMap["e"].([]string)[0] = "changed"
+	firstMap["g"] = "added"
+
+	second, err := getDefault("foo", f)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"a": []any{"b", map[string]any{"c": "d"}},
+		"e": []string{"f"},
+	}, second)
+}
+
+func TestArrayLengthLinter(t *testing.T) {
+	lintCtx := NewLintContext()
+
+	tests := []struct {
+		name     string
+		field    FieldSpec
+		input    any
+		expected []Lint
+	}{
+		{
+			name:  "within range",
+			field: FieldString("foo", "").Array().LinterArrayLength(1, 2),
+			input: []any{"a", "b"},
+		},
+		{
+			name:  "too short",
+			field: FieldString("foo", "").Array().LinterArrayLength(1, 2),
+			input: []any{},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "field foo requires at least 1 elements, got 0"),
+			},
+		},
+		{
+			name:  "too long",
+			field: FieldString("foo", "").Array().LinterArrayLength(1, 2),
+			input: []any{"a", "b", "c"},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "field foo allows at most 2 elements, got 3"),
+			},
+		},
+		{
+			name:  "no upper bound",
+			field: FieldString("foo", "").Array().LinterArrayLength(1, -1),
+			input: []any{"a", "b", "c", "d"},
+		},
+		{
+			name:  "2D array only outer",
+			field: FieldString("foo", "").ArrayOfArrays().LinterArrayLength(2, 2),
+			input: []any{[]any{"a"}, []any{"b", "c", "d"}},
+		},
+		{
+			name:  "2D array too short",
+			field: FieldString("foo", "").ArrayOfArrays().LinterArrayLength(2, 2),
+			input: []any{[]any{"a", "b"}},
+			expected: []Lint{
+				NewLintError(0, LintInvalidOption, "field foo requires at least 2 elements, got 1"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, node.Encode(test.input))
+
+			lints := test.field.LintYAML(lintCtx, &node)
+			assert.Equal(t, test.expected, lints)
+		})
+	}
+}
+
+func TestLintColumns(t *testing.T) {
+	spec := FieldObject("", "").WithChildren(
+		FieldString("mode", "").HasOptions("foo", "bar"),
+		FieldBloblang("mapping", ""),
+		FieldString("tags", "").Array().LinterArrayLength(0, 1),
+	)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+mode: baz
+mapping: 'root = ('
+tags: [ a, b ]
+`), &node))
+
+	lints := spec.LintYAML(NewLintContext(), &node)
+	require.Len(t, lints, 3)
+
+	assert.Equal(t, LintInvalidOption, lints[0].Type)
+	assert.Equal(t, 2, lints[0].Line)
+	assert.Equal(t, 7, lints[0].Column)
+
+	assert.Equal(t, LintBadBloblang, lints[1].Type)
+	assert.Equal(t, 3, lints[1].Line)
+	assert.Greater(t, lints[1].Column, 10)
+
+	assert.Equal(t, LintInvalidOption, lints[2].Type)
+	assert.Equal(t, 4, lints[2].Line)
+	assert.Equal(t, 7, lints[2].Column)
+}
+
+func TestScalarOrArray(t *testing.T) {
+	f := FieldString("topics", "").ScalarOrArray()
+	lintCtx := NewLintContext()
+
+	tests := []struct {
+		name      string
+		input     string
+		lintTypes []LintType
+		value     any
+	}{
+		{
+			name:  "scalar",
+			input: `foo`,
+			value: []any{"foo"},
+		},
+		{
+			name:  "array",
+			input: `[ foo, bar ]`,
+			value: []any{"foo", "bar"},
+		},
+		{
+			name:      "object",
+			input:     `foo: bar`,
+			lintTypes: []LintType{LintExpectedArray},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(test.input), &node))
+
+			var lintTypes []LintType
+			for _, l := range f.LintYAML(lintCtx, &node) {
+				lintTypes = append(lintTypes, l.Type)
+			}
+			assert.Equal(t, test.lintTypes, lintTypes)
+
+			if test.value != nil {
+				v, err := f.YAMLToValue(&node, ToValueConfig{})
+				require.NoError(t, err)
+				assert.Equal(t, test.value, v)
+			}
+		})
+	}
+
+	assert.Equal(t, map[string]any{
+		"anyOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	}, f.JSONSchema())
+}
@@ -0,0 +1,164 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// keySegment is a part of an interpolated object key, which is either static
+// text or a dynamic query along with any metadata keys that it references.
+type keySegment struct {
+	static   string
+	dynamic  bool
+	metaKeys []string
+}
+
+// metaCondition returns a description of the metadata keys a dynamic segment
+// references, or an empty string if it references none.
+func (k keySegment) metaCondition() string {
+	if !k.dynamic || len(k.metaKeys) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("metadata %v is absent", strings.Join(k.metaKeys, ", "))
+}
+
+func objectKeySegments(str string) ([]keySegment, bool) {
+	// The lint context environment is deactivated, and deactivated functions do
+	// not report their query targets. Therefore we parse with the pure subset
+	// of the global environment, where any expression that fails to parse is
+	// left to LintBloblangField.
+	e, err := bloblang.GlobalEnvironment().OnlyPure().NewField(str)
+	if err != nil {
+		return nil, false
+	}
+
+	var segments []keySegment
+	for _, r := range e.Resolvers() {
+		switch t := r.(type) {
+		case field.StaticResolver:
+			if n := len(segments); n > 0 && !segments[n-1].dynamic {
+				segments[n-1].static += string(t)
+				continue
+			}
+			segments = append(segments, keySegment{static: string(t)})
+		case *field.QueryResolver:
+			seg := keySegment{dynamic: true}
+			for _, target := range t.QueryTargets(query.TargetsContext{}) {
+				if target.Type == query.TargetMetadata && len(target.Path) > 0 {
+					seg.metaKeys = append(seg.metaKeys, target.Path[0])
+				}
+			}
+			segments = append(segments, seg)
+		default:
+			segments = append(segments, keySegment{dynamic: true})
+		}
+	}
+	return segments, true
+}
+
+// lintEmptyKeySegments returns lints for dynamic segments that are surrounded
+// by slashes and reference metadata, as these result in an empty segment of
+// the key when the metadata is absent.
+func lintEmptyKeySegments(line int, segments []keySegment) (lints []Lint) {
+	for i := 1; i < len(segments)-1; i++ {
+		cond := segments[i].metaCondition()
+		if cond == "" {
+			continue
+		}
+		if strings.HasSuffix(segments[i-1].static, "/") && strings.HasPrefix(segments[i+1].static, "/") {
+			lints = append(lints, NewLintWarning(line, LintBadInterpolation, fmt.Sprintf("object keys will contain an empty segment when %v", cond)))
+		}
+	}
+	return
+}
+
+// LintObjectKeyDirectory is a function for linting an interpolated field
+// expected to be the directory of object keys. When the lint context enables
+// strict interpolation linting warnings are returned for directories that
+// would result in keys beginning with a slash, or containing empty segments,
+// including when referenced metadata is absent.
+func LintObjectKeyDirectory(ctx LintContext, line, col int, v any) []Lint {
+	str, ok := v.(string)
+	if !ok || str == "" || !ctx.StrictInterpolation {
+		return nil
+	}
+	segments, ok := objectKeySegments(str)
+	if !ok {
+		return nil
+	}
+
+	var lints []Lint
+	if first := segments[0]; !first.dynamic && strings.HasPrefix(first.static, "/") {
+		lints = append(lints, NewLintWarning(line, LintBadInterpolation, "object keys will begin with a slash"))
+	} else if cond := first.metaCondition(); cond != "" && len(segments) > 1 && strings.HasPrefix(segments[1].static, "/") {
+		lints = append(lints, NewLintWarning(line, LintBadInterpolation, fmt.Sprintf("object keys will begin with a slash when %v", cond)))
+	}
+	return append(lints, lintEmptyKeySegments(line, segments)...)
+}
+
+// LintObjectKeyPath is a function for linting an interpolated field expected
+// to be the path of object keys relative to a directory. When the lint context
+// enables strict interpolation linting warnings are returned for paths that
+// would result in keys ending with a slash, or containing empty segments,
+// including when referenced metadata is absent.
+func LintObjectKeyPath(ctx LintContext, line, col int, v any) []Lint {
+	str, ok := v.(string)
+	if !ok || str == "" || !ctx.StrictInterpolation {
+		return nil
+	}
+	segments, ok := objectKeySegments(str)
+	if !ok {
+		return nil
+	}
+
+	var lints []Lint
+	n := len(segments)
+	if last := segments[n-1]; !last.dynamic && strings.HasSuffix(last.static, "/") {
+		lints = append(lints, NewLintWarning(line, LintBadInterpolation, "object keys will end with a slash"))
+	} else if cond := last.metaCondition(); cond != "" && n > 1 && strings.HasSuffix(segments[n-2].static, "/") {
+		lints = append(lints, NewLintWarning(line, LintBadInterpolation, fmt.Sprintf("object keys will end with a slash when %v", cond)))
+	}
+	return append(lints, lintEmptyKeySegments(line, segments)...)
+}
+
+// LintInterpolationWhenEmpty is a function for linting an interpolated field
+// that is expected to resolve to a non-empty string. When the lint context
+// enables strict interpolation linting the field is resolved against an empty
+// message, and a warning is returned when a non-empty template that contains
+// dynamic expressions resolves to an empty string. Expressions that reference
+// the contents or metadata of messages are not linted, as an empty message is
+// not representative of their results.
+func LintInterpolationWhenEmpty(ctx LintContext, line, col int, v any) []Lint {
+	str, ok := v.(string)
+	if !ok || str == "" || !ctx.StrictInterpolation {
+		return nil
+	}
+	e, err := bloblang.GlobalEnvironment().OnlyPure().NewField(str)
+	if err != nil || e.NumDynamicExpressions() == 0 {
+		return nil
+	}
+	for _, r := range e.Resolvers() {
+		q, ok := r.(*field.QueryResolver)
+		if !ok {
+			continue
+		}
+		for _, target := range q.QueryTargets(query.TargetsContext{}) {
+			if target.Type == query.TargetMetadata || target.Type == query.TargetValue {
+				return nil
+			}
+		}
+	}
+	// Functions such as content() do not declare their targets, and therefore
+	// the field is also resolved against a message with contents.
+	for _, content := range [][]byte{nil, []byte("content")} {
+		if e.String(0, message.QuickBatch([][]byte{content})) != "" {
+			return nil
+		}
+	}
+	return []Lint{NewLintWarning(line, LintBadInterpolation, "interpolation always resolves to an empty string")}
+}
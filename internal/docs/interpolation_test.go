@@ -0,0 +1,182 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintObjectKeyDirectory(t *testing.T) {
+	lintCtx := NewLintContext()
+	lintCtx.StrictInterpolation = true
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []Lint
+	}{
+		{
+			name:  "static",
+			value: "foo/bar",
+		},
+		{
+			name:  "leading slash",
+			value: "/foo/bar",
+			expected: []Lint{
+				NewLintWarning(1, LintBadInterpolation, "object keys will begin with a slash"),
+			},
+		},
+		{
+			name:  "leading metadata",
+			value: `${! meta("dir") }/bar`,
+			expected: []Lint{
+				NewLintWarning(1, LintBadInterpolation, "object keys will begin with a slash when metadata dir is absent"),
+			},
+		},
+		{
+			name:  "leading metadata reference",
+			value: `${! @dir }/bar`,
+			expected: []Lint{
+				NewLintWarning(1, LintBadInterpolation, "object keys will begin with a slash when metadata dir is absent"),
+			},
+		},
+		{
+			name:  "leading content",
+			value: `${! content() }/bar`,
+		},
+		{
+			name:  "trailing metadata",
+			value: `foo/${! meta("dir") }`,
+		},
+		{
+			name:  "empty segment",
+			value: `foo/${! meta("dir") }/bar`,
+			expected: []Lint{
+				NewLintWarning(1, LintBadInterpolation, "object keys will contain an empty segment when metadata dir is absent"),
+			},
+		},
+		{
+			name:  "bad bloblang",
+			value: `/${! meta("dir" }`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, LintObjectKeyDirectory(lintCtx, 1, 1, test.value))
+		})
+	}
+
+	assert.Empty(t, LintObjectKeyDirectory(NewLintContext(), 1, 1, "/foo/bar"))
+}
+
+func TestLintObjectKeyPath(t *testing.T) {
+	lintCtx := NewLintContext()
+	lintCtx.StrictInterpolation = true
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []Lint
+	}{
+		{
+			name:  "static",
+			value: "foo.txt",
+		},
+		{
+			name:  "trailing slash",
+			value: "foo/",
+			expected: []Lint{
+				NewLintWarning(1, LintBadInterpolation, "object keys will end with a slash"),
+			},
+		},
+		{
+			name:  "trailing metadata",
+			value: `foo/${! meta("name") }`,
+			expected: []Lint{
+				NewLintWarning(1, LintBadInterpolation, "object keys will end with a slash when metadata name is absent"),
+			},
+		},
+		{
+			name:  "trailing metadata with suffix",
+			value: `foo/${! meta("name") }.txt`,
+		},
+		{
+			name:  "only metadata",
+			value: `${! meta("name") }`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, LintObjectKeyPath(lintCtx, 1, 1, test.value))
+		})
+	}
+
+	assert.Empty(t, LintObjectKeyPath(NewLintContext(), 1, 1, "foo/"))
+}
+
+func TestLintInterpolationWhenEmpty(t *testing.T) {
+	lintCtx := NewLintContext()
+	lintCtx.StrictInterpolation = true
+
+	emptyLint := []Lint{
+		NewLintWarning(1, LintBadInterpolation, "interpolation always resolves to an empty string"),
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []Lint
+	}{
+		{
+			name:  "static",
+			value: "foo",
+		},
+		{
+			name:     "empty string literal",
+			value:    `${! "" }`,
+			expected: emptyLint,
+		},
+		{
+			name:     "empty method result",
+			value:    `${! "foo".number().catch("") }`,
+			expected: emptyLint,
+		},
+		{
+			name:  "empty with static text",
+			value: `foo${! "" }`,
+		},
+		{
+			name:  "non-empty function",
+			value: `${! timestamp_unix() }`,
+		},
+		{
+			name:  "metadata",
+			value: `${! meta("foo") }`,
+		},
+		{
+			name:  "content",
+			value: `${! content() }`,
+		},
+		{
+			name:  "bad bloblang",
+			value: `${! "" `,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, LintInterpolationWhenEmpty(lintCtx, 1, 1, test.value))
+		})
+	}
+
+	assert.Empty(t, LintInterpolationWhenEmpty(NewLintContext(), 1, 1, `${! "" }`))
+
+	f := FieldInterpolatedString("foo", "").LintWhenEmpty()
+	assert.Equal(t, emptyLint, f.getLintFunc()(lintCtx, 1, 1, `${! "" }`))
+	assert.Empty(t, FieldInterpolatedString("foo", "").getLintFunc()(lintCtx, 1, 1, `${! "" }`))
+}
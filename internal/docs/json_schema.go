@@ -7,16 +7,27 @@ func (f FieldSpec) JSONSchema() any {
 	case Kind2DArray:
 		innerField := f
 		innerField.Kind = KindArray
+		innerField.Default = nil
+		innerField.arrayLength = nil
 		spec["type"] = "array"
 		spec["items"] = innerField.JSONSchema()
+		f.arrayLengthJSONSchema(spec)
 	case KindArray:
 		innerField := f
 		innerField.Kind = KindScalar
+		innerField.Default = nil
 		spec["type"] = "array"
 		spec["items"] = innerField.JSONSchema()
+		f.arrayLengthJSONSchema(spec)
+		if f.scalarOrArray {
+			spec = map[string]any{
+				"anyOf": []any{innerField.JSONSchema(), spec},
+			}
+		}
 	case KindMap:
 		innerField := f
 		innerField.Kind = KindScalar
+		innerField.Default = nil
 		spec["type"] = "object"
 		spec["patternProperties"] = map[string]any{
 			".": innerField.JSONSchema(),
@@ -27,6 +38,9 @@ func (f FieldSpec) JSONSchema() any {
 			spec["type"] = "boolean"
 		case FieldTypeString:
 			spec["type"] = "string"
+			if enum := f.jsonSchemaEnum(); len(enum) > 0 {
+				spec["enum"] = enum
+			}
 		case FieldTypeInt:
 			spec["type"] = "number"
 		case FieldTypeFloat:
@@ -36,7 +50,7 @@ func (f FieldSpec) JSONSchema() any {
 			spec["properties"] = f.Children.JSONSchema()
 			var required []string
 			for _, child := range f.Children {
-				if !child.IsOptional && child.Default == nil {
+				if child.CheckRequired() {
 					required = append(required, child.Name)
 				}
 			}
@@ -62,9 +76,41 @@ func (f FieldSpec) JSONSchema() any {
 			spec["$ref"] = "#/$defs/tracer"
 		}
 	}
+	if f.Default != nil {
+		spec["default"] = *f.Default
+	}
 	return spec
 }
 
+// arrayLengthJSONSchema adds the array length constraints of a field, if any,
+// to its JSON schema.
+func (f FieldSpec) arrayLengthJSONSchema(spec map[string]any) {
+	if f.arrayLength == nil {
+		return
+	}
+	spec["minItems"] = f.arrayLength.min
+	if f.arrayLength.max >= 0 {
+		spec["maxItems"] = f.arrayLength.max
+	}
+}
+
+// jsonSchemaEnum returns the enumerated options of a field, if any. Options
+// are only enumerated when they're enforced by the linter of the field, as
+// fields that replace that linter accept values other than their options.
+func (f FieldSpec) jsonSchemaEnum() []any {
+	if !f.optionsLinted {
+		return nil
+	}
+	var enum []any
+	for _, o := range f.Options {
+		enum = append(enum, o)
+	}
+	for _, o := range f.AnnotatedOptions {
+		enum = append(enum, o[0])
+	}
+	return enum
+}
+
 // JSONSchema serializes a field spec into a JSON schema structure.
 func (f FieldSpecs) JSONSchema() map[string]any {
 	spec := map[string]any{}
@@ -73,3 +119,55 @@ func (f FieldSpecs) JSONSchema() map[string]any {
 	}
 	return spec
 }
+
+// JSONSchema serializes a component spec into a JSON schema structure, which
+// describes a config object containing the component config under the name of
+// the component along with any reserved fields of the component type.
+func (c *ComponentSpec) JSONSchema() map[string]any {
+	properties := map[string]any{}
+	for name, field := range ReservedFieldsByType(c.Type) {
+		if name == "type" || name == "plugin" {
+			continue
+		}
+		properties[name] = field.JSONSchema()
+	}
+	properties[c.Name] = c.Config.JSONSchema()
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             []string{c.Name},
+		"additionalProperties": false,
+	}
+}
+
+// JSONSchemaDocument returns a JSON schema document describing a config with
+// the provided fields, where component fields may be any of the provided
+// component specs of the same type.
+func JSONSchemaDocument(fields FieldSpecs, components []ComponentSpec) map[string]any {
+	defs := map[string]any{}
+	for _, t := range Types() {
+		var anyOf []any
+		for _, c := range components {
+			if c.Type == t {
+				anyOf = append(anyOf, c.JSONSchema())
+			}
+		}
+		if len(anyOf) == 0 {
+			continue
+		}
+		defs[string(t)] = map[string]any{
+			"anyOf": anyOf,
+		}
+	}
+
+	doc := map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": fields.JSONSchema(),
+	}
+	if len(defs) > 0 {
+		doc["$defs"] = defs
+	}
+	return doc
+}
@@ -0,0 +1,138 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func TestFieldsJSONSchema(t *testing.T) {
+	spec := docs.FieldSpecs{
+		docs.FieldString("a", ""),
+		docs.FieldInt("b", "").HasDefault(11),
+		docs.FieldString("c", "").HasOptions("foo", "bar").HasDefault("foo"),
+		docs.FieldObject("d", "").WithChildren(
+			docs.FieldBool("e", "").HasDefault(true),
+			docs.FieldInterpolatedString("f", ""),
+			docs.FieldString("g", "").Array().Optional(),
+		),
+		docs.FieldProcessor("h", "").Array().HasDefault([]any{}),
+		docs.FieldString("i", "").HasOptions("foo", "bar").LinterFunc(nil).HasDefault("foo"),
+	}
+
+	assert.Equal(t, map[string]any{
+		"a": map[string]any{"type": "string"},
+		"b": map[string]any{"type": "number", "default": 11},
+		"c": map[string]any{
+			"type":    "string",
+			"enum":    []any{"foo", "bar"},
+			"default": "foo",
+		},
+		"d": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"e": map[string]any{"type": "boolean", "default": true},
+				"f": map[string]any{"type": "string"},
+				"g": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"type": "string"},
+				},
+			},
+			"required":             []string{"f"},
+			"additionalProperties": false,
+		},
+		"h": map[string]any{
+			"type":    "array",
+			"items":   map[string]any{"$ref": "#/$defs/processor"},
+			"default": []any{},
+		},
+		"i": map[string]any{"type": "string", "default": "foo"},
+	}, spec.JSONSchema())
+}
+
+func TestJSONSchemaDocument(t *testing.T) {
+	doc := docs.JSONSchemaDocument(docs.FieldSpecs{
+		docs.FieldProcessor("processors", "").Array(),
+	}, []docs.ComponentSpec{
+		{
+			Name: "foo",
+			Type: docs.TypeProcessor,
+			Config: docs.FieldObject("", "").WithChildren(
+				docs.FieldString("bar", ""),
+			),
+		},
+		{
+			Name:   "baz",
+			Type:   docs.TypeCache,
+			Config: docs.FieldString("", ""),
+		},
+	})
+
+	labelSchema := doc["$defs"].(map[string]any)["processor"].(map[string]any)["anyOf"].([]any)[0].(map[string]any)["properties"].(map[string]any)["label"]
+
+	assert.Equal(t, map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"processors": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"$ref": "#/$defs/processor"},
+			},
+		},
+		"$defs": map[string]any{
+			"cache": map[string]any{
+				"anyOf": []any{
+					map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"label": labelSchema,
+							"baz":   map[string]any{"type": "string"},
+						},
+						"required":             []string{"baz"},
+						"additionalProperties": false,
+					},
+				},
+			},
+			"processor": map[string]any{
+				"anyOf": []any{
+					map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"label": labelSchema,
+							"foo": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"bar": map[string]any{"type": "string"},
+								},
+								"required":             []string{"bar"},
+								"additionalProperties": false,
+							},
+						},
+						"required":             []string{"foo"},
+						"additionalProperties": false,
+					},
+				},
+			},
+		},
+	}, doc)
+}
+
+func TestFieldJSONSchemaArrayLength(t *testing.T) {
+	assert.Equal(t, map[string]any{
+		"type":     "array",
+		"items":    map[string]any{"type": "string"},
+		"minItems": 1,
+		"maxItems": 3,
+	}, docs.FieldString("a", "").Array().LinterArrayLength(1, 3).JSONSchema())
+
+	assert.Equal(t, map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type":  "array",
+			"items": map[string]any{"type": "string"},
+		},
+		"minItems": 1,
+	}, docs.FieldString("a", "").ArrayOfArrays().LinterArrayLength(1, -1).JSONSchema())
+}
@@ -0,0 +1,102 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+var coreLintTypeNames = map[LintType]string{
+	LintCustom:            "custom",
+	LintFailedRead:        "failed_read",
+	LintInvalidOption:     "invalid_option",
+	LintBadLabel:          "bad_label",
+	LintMissingLabel:      "missing_label",
+	LintDuplicateLabel:    "duplicate_label",
+	LintBadBloblang:       "bad_bloblang",
+	LintShouldOmit:        "should_omit",
+	LintComponentMissing:  "component_missing",
+	LintComponentNotFound: "component_not_found",
+	LintUnknown:           "unknown",
+	LintMissing:           "missing",
+	LintExpectedArray:     "expected_array",
+	LintExpectedObject:    "expected_object",
+	LintExpectedScalar:    "expected_scalar",
+	LintDeprecated:        "deprecated",
+	LintMissingEnvVar:     "missing_env_var",
+	LintBadInterpolation:  "bad_interpolation",
+	LintPlaintextSecret:   "plaintext_secret",
+	LintUnusedResource:    "unused_resource",
+}
+
+var lintTypeNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Lint types registered by plugins are stored separately from the core types
+// as they're only known at runtime.
+var (
+	customLintTypesMut    sync.RWMutex
+	customLintTypeNames   []string
+	customLintTypesByName = map[string]LintType{}
+)
+
+// RegisterLintType registers a named lint type, allowing plugins to categorise
+// their lints beyond LintCustom. Registering a name that is already registered
+// returns the existing type, and therefore plugins that share a category may
+// register it independently. An error is returned when the name is invalid or
+// is that of a core lint type.
+func RegisterLintType(name string) (LintType, error) {
+	if !lintTypeNameRegexp.MatchString(name) {
+		return LintCustom, fmt.Errorf("lint type name %q must only contain lowercase alphanumeric characters and underscores", name)
+	}
+	for _, n := range coreLintTypeNames {
+		if n == name {
+			return LintCustom, fmt.Errorf("lint type name %q is reserved", name)
+		}
+	}
+
+	customLintTypesMut.Lock()
+	defer customLintTypesMut.Unlock()
+
+	if t, exists := customLintTypesByName[name]; exists {
+		return t, nil
+	}
+	t := lintTypeCustomStart + LintType(len(customLintTypeNames))
+	customLintTypeNames = append(customLintTypeNames, name)
+	customLintTypesByName[name] = t
+	return t, nil
+}
+
+// LintTypeFromString returns the lint type of a name, which is either that of
+// a core lint type or one registered with RegisterLintType.
+func LintTypeFromString(name string) (LintType, error) {
+	for t, n := range coreLintTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+
+	customLintTypesMut.RLock()
+	defer customLintTypesMut.RUnlock()
+
+	if t, exists := customLintTypesByName[name]; exists {
+		return t, nil
+	}
+	return LintCustom, errors.New("unrecognised lint type")
+}
+
+// String returns the name of a lint type, which can be converted back with
+// LintTypeFromString.
+func (t LintType) String() string {
+	if n, exists := coreLintTypeNames[t]; exists {
+		return n
+	}
+
+	customLintTypesMut.RLock()
+	defer customLintTypesMut.RUnlock()
+
+	if i := int(t - lintTypeCustomStart); i >= 0 && i < len(customLintTypeNames) {
+		return customLintTypeNames[i]
+	}
+	return fmt.Sprintf("lint_type_%d", int(t))
+}
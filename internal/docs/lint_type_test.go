@@ -0,0 +1,64 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLintTypeNames(t *testing.T) {
+	for lt := LintCustom; lt < lintTypeCustomStart; lt++ {
+		name := lt.String()
+		require.NotContains(t, name, "lint_type_", "core lint type %d has no name", int(lt))
+
+		parsed, err := LintTypeFromString(name)
+		require.NoError(t, err)
+		assert.Equal(t, lt, parsed)
+	}
+
+	_, err := LintTypeFromString("meow")
+	require.Error(t, err)
+}
+
+func TestRegisterLintType(t *testing.T) {
+	a, err := RegisterLintType("test_register_a")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, a, lintTypeCustomStart)
+	assert.Equal(t, "test_register_a", a.String())
+
+	b, err := RegisterLintType("test_register_b")
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+
+	again, err := RegisterLintType("test_register_a")
+	require.NoError(t, err)
+	assert.Equal(t, a, again)
+
+	parsed, err := LintTypeFromString("test_register_b")
+	require.NoError(t, err)
+	assert.Equal(t, b, parsed)
+
+	_, err = RegisterLintType("unknown")
+	require.EqualError(t, err, `lint type name "unknown" is reserved`)
+
+	_, err = RegisterLintType("Not Valid")
+	require.EqualError(t, err, `lint type name "Not Valid" must only contain lowercase alphanumeric characters and underscores`)
+}
+
+func TestLintsOfType(t *testing.T) {
+	lt, err := RegisterLintType("test_lints_of_type")
+	require.NoError(t, err)
+
+	f := FieldString("foo", "").
+		LinterBlobl(`root = if this.contains("meow") { [ "no cats allowed" ] }`).
+		LintsOfType(lt)
+
+	var node yaml.Node
+	require.NoError(t, node.Encode("hello meow"))
+
+	assert.Equal(t, []Lint{
+		NewLintError(0, lt, "no cats allowed"),
+	}, f.LintYAML(NewLintContext(), &node))
+}
@@ -271,6 +271,21 @@ func SanitiseYAML(cType Type, node *yaml.Node, conf SanitiseConfig) error {
 	return nil
 }
 
+// expandScalarOrArray wraps a scalar node within a sequence node when the
+// field is an array that also accepts a single scalar value.
+func (f FieldSpec) expandScalarOrArray(node *yaml.Node) *yaml.Node {
+	if !f.scalarOrArray || f.Kind != KindArray || node.Kind != yaml.ScalarNode {
+		return node
+	}
+	return &yaml.Node{
+		Kind:    yaml.SequenceNode,
+		Tag:     "!!seq",
+		Line:    node.Line,
+		Column:  node.Column,
+		Content: []*yaml.Node{node},
+	}
+}
+
 // SanitiseYAML attempts to reduce a parsed config (as a *yaml.Node) down into a
 // minimal representation without changing the behaviour of the config. The
 // fields of the result will also be sorted according to the field spec.
@@ -331,12 +346,31 @@ func (f FieldSpec) SanitiseYAML(node *yaml.Node, conf SanitiseConfig) error {
 				return err
 			}
 		}
-	} else if f.IsSecret && conf.ScrubSecrets && node.Value != "" {
-		node.Value = f.scrubValue(node.Value)
+	} else if f.IsSecret && conf.ScrubSecrets {
+		f.scrubYAML(node)
 	}
 	return nil
 }
 
+// scrubYAML replaces the values of a secret field with a placeholder, including
+// the elements of secret arrays and maps.
+func (f FieldSpec) scrubYAML(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			f.scrubYAML(n)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			f.scrubYAML(node.Content[i])
+		}
+	case yaml.ScalarNode:
+		if node.Value != "" {
+			node.Value = f.scrubValue(node.Value)
+		}
+	}
+}
+
 // SanitiseYAML attempts to reduce a parsed config (as a *yaml.Node) down into a
 // minimal representation without changing the behaviour of the config. The
 // fields of the result will also be sorted according to the field spec.
@@ -407,6 +441,21 @@ func customLintFromYAML(ctx LintContext, spec FieldSpec, node *yaml.Node) []Lint
 	}
 
 	lints := lintFn(ctx, line, node.Column, fieldValue)
+	return lintsAtNodeColumn(node, lints)
+}
+
+// lintsAtNodeColumn positions lints that were not given a specific column,
+// and are therefore at the default column of 1, at the column of the node that
+// they were reported for. Nodes without a known column are ignored.
+func lintsAtNodeColumn(node *yaml.Node, lints []Lint) []Lint {
+	if node.Column <= 1 {
+		return lints
+	}
+	for i := range lints {
+		if lints[i].Column == 1 {
+			lints[i].Column = node.Column
+		}
+	}
 	return lints
 }
 
@@ -493,15 +542,45 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 	return lints
 }
 
+func (f FieldSpec) lintArrayLength(node *yaml.Node) []Lint {
+	if f.arrayLength == nil {
+		return nil
+	}
+	l := len(node.Content)
+	if l < f.arrayLength.min {
+		return lintsAtNodeColumn(node, []Lint{NewLintError(node.Line, LintInvalidOption, fmt.Sprintf("field %v requires at least %v elements, got %v", f.Name, f.arrayLength.min, l))})
+	}
+	if f.arrayLength.max >= 0 && l > f.arrayLength.max {
+		return lintsAtNodeColumn(node, []Lint{NewLintError(node.Line, LintInvalidOption, fmt.Sprintf("field %v allows at most %v elements, got %v", f.Name, f.arrayLength.max, l))})
+	}
+	return nil
+}
+
+// lintPlaintextSecret returns a warning when a secret field is set to a
+// plaintext value rather than an environment variable interpolation.
+func (f FieldSpec) lintPlaintextSecret(ctx LintContext, node *yaml.Node) []Lint {
+	if !ctx.PlaintextSecrets || !f.IsSecret || node.Kind != yaml.ScalarNode {
+		return nil
+	}
+	if node.Value == "" || envRegex.MatchString(node.Value) {
+		return nil
+	}
+	return []Lint{NewLintWarning(node.Line, LintPlaintextSecret, fmt.Sprintf("field %v is a secret and should be set with an environment variable such as ${FOO} rather than a plaintext value", f.Name))}
+}
+
 // LintYAML returns a list of linting errors found by checking a field
 // definition against a yaml node.
 func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
-	node = unwrapDocumentNode(node)
+	node = f.expandScalarOrArray(unwrapDocumentNode(node))
 
 	var lints []Lint
 
 	if ctx.RejectDeprecated && f.IsDeprecated {
-		lints = append(lints, NewLintError(node.Line, LintDeprecated, fmt.Sprintf("field %v is deprecated", f.Name)))
+		what := fmt.Sprintf("field %v is deprecated", f.Name)
+		if f.ReplacedBy != "" {
+			what += fmt.Sprintf(", use `%v` instead", f.ReplacedBy)
+		}
+		lints = append(lints, NewLintError(node.Line, LintDeprecated, what))
 	}
 
 	// Execute custom linters, if the kind is non-scalar this means we execute
@@ -516,8 +595,11 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			lints = append(lints, NewLintError(node.Line, LintExpectedArray, "expected array value"))
 			return lints
 		}
+		lints = append(lints, f.lintArrayLength(node)...)
+		elementSpec := f.Array()
+		elementSpec.arrayLength = nil
 		for i := 0; i < len(node.Content); i++ {
-			lints = append(lints, f.Array().LintYAML(ctx, node.Content[i])...)
+			lints = append(lints, elementSpec.LintYAML(ctx, node.Content[i])...)
 		}
 		return lints
 	case KindArray:
@@ -525,6 +607,7 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			lints = append(lints, NewLintError(node.Line, LintExpectedArray, "expected array value"))
 			return lints
 		}
+		lints = append(lints, f.lintArrayLength(node)...)
 		for i := 0; i < len(node.Content); i++ {
 			lints = append(lints, f.Scalar().LintYAML(ctx, node.Content[i])...)
 		}
@@ -547,15 +630,21 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 
 	// If the field has children then lint the child fields
 	if len(f.Children) > 0 {
+		lints = append(lints, f.lintFieldGroups(node)...)
 		return append(lints, f.Children.LintYAML(ctx, node)...)
 	}
 
 	// Otherwise we're a leaf node, so do basic type checking
+	lints = append(lints, f.lintPlaintextSecret(ctx, node)...)
 	switch f.Type {
 	// TODO: Do proper checking for bool and number types.
 	case FieldTypeBool, FieldTypeString, FieldTypeInt, FieldTypeFloat:
 		if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
 			lints = append(lints, NewLintError(node.Line, LintExpectedScalar, fmt.Sprintf("expected %v value", f.Type)))
+		} else if ctx.StrictStructure && node.Kind == yaml.ScalarNode && !scalarMatchesType(node, f.Type) {
+			lints = append(lints, lintsAtNodeColumn(node, []Lint{
+				NewLintError(node.Line, LintExpectedScalar, fmt.Sprintf("expected %v value, got %v", f.Type, node.Value)),
+			})...)
 		}
 	case FieldTypeObject:
 		if node.Kind != yaml.MappingNode && node.Kind != yaml.AliasNode {
@@ -565,6 +654,23 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 	return lints
 }
 
+// scalarMatchesType returns true if the resolved tag of a scalar node is
+// compatible with a field type, where ints are also valid floats.
+func scalarMatchesType(node *yaml.Node, t FieldType) bool {
+	tag := node.ShortTag()
+	switch t {
+	case FieldTypeString:
+		return tag == "!!str"
+	case FieldTypeInt:
+		return tag == "!!int"
+	case FieldTypeFloat:
+		return tag == "!!float" || tag == "!!int"
+	case FieldTypeBool:
+		return tag == "!!bool"
+	}
+	return true
+}
+
 // LintYAML walks a yaml node and returns a list of linting errors found.
 func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 	node = unwrapDocumentNode(node)
@@ -597,15 +703,19 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 		delete(specNames, node.Content[i].Value)
 	}
 
-	for name, remaining := range specNames {
+	for _, field := range f {
+		remaining, exists := specNames[field.Name]
+		if !exists {
+			continue
+		}
 		_, isCore := remaining.Type.IsCoreComponent()
 		if remaining.needsDefault() &&
 			remaining.Default == nil &&
 			!isCore &&
-			remaining.Kind == KindScalar &&
-			len(remaining.Children) == 0 {
-			lints = append(lints, NewLintError(node.Line, LintMissing, fmt.Sprintf("field %v is required", name)))
+			((remaining.Kind == KindScalar && len(remaining.Children) == 0) || ctx.StrictStructure) {
+			lints = append(lints, NewLintError(node.Line, LintMissing, fmt.Sprintf("field %v is required", field.Name)))
 		}
+		lints = append(lints, remaining.lintRequiredWhen(f, node)...)
 	}
 	return lints
 }
@@ -702,7 +812,7 @@ type ToValueConfig struct {
 // YAMLToValue converts a yaml node into a generic value by referencing the
 // expected type.
 func (f FieldSpec) YAMLToValue(node *yaml.Node, conf ToValueConfig) (any, error) {
-	node = unwrapDocumentNode(node)
+	node = f.expandScalarOrArray(unwrapDocumentNode(node))
 
 	switch f.Kind {
 	case Kind2DArray:
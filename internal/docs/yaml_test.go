@@ -620,6 +620,7 @@ func TestYAMLComponentLinting(t *testing.T) {
 				docs.FieldObject("foo8", "").Map().WithChildren(
 					docs.FieldInt("foochild1", "").Optional(),
 				).Optional().Advanced(),
+				docs.FieldString("foo9", "").Optional().DeprecatedReplacedBy("foo1"),
 			),
 		})
 		prov.RegisterDocs(docs.ComponentSpec{
@@ -689,6 +690,17 @@ testlintfooinput:
 				docs.NewLintError(4, docs.LintDeprecated, "field foo6 is deprecated"),
 			},
 		},
+		{
+			name:      "reject deprecated fields with replacement",
+			inputType: docs.TypeInput,
+			inputConf: `
+testlintfooinput:
+  foo9: hello world`,
+			rejectDeprecated: true,
+			res: []docs.Lint{
+				docs.NewLintError(3, docs.LintDeprecated, "field foo9 is deprecated, use `foo1` instead"),
+			},
+		},
 		{
 			name:      "require label",
 			inputType: docs.TypeInput,
@@ -773,7 +785,7 @@ processors:
   - label: foo
     testlintfooprocessor: {}`,
 			res: []docs.Lint{
-				docs.NewLintError(8, docs.LintDuplicateLabel, "Label 'foo' collides with a previously defined label at line 2"),
+				{Line: 8, Column: 12, Level: docs.LintError, Type: docs.LintDuplicateLabel, What: "Label 'foo' collides with a previously defined label at line 2"},
 			},
 		},
 		{
@@ -935,7 +947,7 @@ testlintfooinput:
 testlintfooinput:
   foo1: lint me please`,
 			res: []docs.Lint{
-				docs.NewLintError(3, docs.LintCustom, "this is a custom lint"),
+				{Line: 3, Column: 9, Level: docs.LintError, Type: docs.LintCustom, What: "this is a custom lint"},
 			},
 		},
 	}
@@ -1270,3 +1282,120 @@ processors:
 		})
 	}
 }
+
+func TestYAMLSanitationScrubSecrets(t *testing.T) {
+	prov := docs.NewMappedDocsProvider()
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "testscrubinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("user", ""),
+			docs.FieldString("password", "").Secret(),
+			docs.FieldObject("a", "").WithChildren(
+				docs.FieldString("b", "").Secret(),
+				docs.FieldString("c", ""),
+			),
+			docs.FieldObject("d", "").WithChildren(
+				docs.FieldString("e", "").Secret(),
+			).Array(),
+			docs.FieldString("f", "").Secret().Map(),
+			docs.FieldString("g", "").Secret().Array(),
+		),
+	})
+	prov.RegisterDocs(docs.ComponentSpec{
+		Name: "testscrubprocessor",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("auth_secret", "").Secret(),
+		),
+	})
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+testscrubinput:
+  user: foo
+  password: bar
+  a:
+    b: baz
+    c: buz
+  d:
+    - e: first
+    - e: ${SECOND}
+  f:
+    x: one
+    y: ""
+  g: [ two, "${THREE:default}" ]
+processors:
+  - testscrubprocessor:
+      auth_secret: five
+`), &node))
+
+	sanitConf := docs.NewSanitiseConfig()
+	sanitConf.DocsProvider = prov
+	sanitConf.RemoveTypeField = true
+	sanitConf.ScrubSecrets = true
+
+	require.NoError(t, docs.SanitiseYAML(docs.TypeInput, &node, sanitConf))
+
+	var res any
+	require.NoError(t, node.Decode(&res))
+	assert.Equal(t, map[string]any{
+		"testscrubinput": map[string]any{
+			"user":     "foo",
+			"password": "!!!SECRET_SCRUBBED!!!",
+			"a": map[string]any{
+				"b": "!!!SECRET_SCRUBBED!!!",
+				"c": "buz",
+			},
+			"d": []any{
+				map[string]any{"e": "!!!SECRET_SCRUBBED!!!"},
+				map[string]any{"e": "${SECOND}"},
+			},
+			"f": map[string]any{
+				"x": "!!!SECRET_SCRUBBED!!!",
+				"y": "",
+			},
+			"g": []any{"!!!SECRET_SCRUBBED!!!", "${THREE:default}"},
+		},
+		"processors": []any{
+			map[string]any{
+				"testscrubprocessor": map[string]any{
+					"auth_secret": "!!!SECRET_SCRUBBED!!!",
+				},
+			},
+		},
+	}, res)
+}
+
+func TestFieldsLintPlaintextSecrets(t *testing.T) {
+	spec := docs.FieldSpecs{
+		docs.FieldString("a", "").Secret(),
+		docs.FieldString("b", ""),
+		docs.FieldObject("c", "").WithChildren(
+			docs.FieldString("d", "").Secret(),
+		),
+		docs.FieldString("e", "").Secret().Map(),
+		docs.FieldString("f", "").Secret(),
+	}
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+a: foo
+b: bar
+c:
+  d: ${BAZ}
+e:
+  x: ${ONE:default}
+  y: two
+f: ""
+`), &node))
+
+	lintCtx := docs.NewLintContext()
+	assert.Empty(t, spec.LintYAML(lintCtx, &node))
+
+	lintCtx.PlaintextSecrets = true
+	assert.Equal(t, []docs.Lint{
+		docs.NewLintWarning(2, docs.LintPlaintextSecret, "field a is a secret and should be set with an environment variable such as ${FOO} rather than a plaintext value"),
+		docs.NewLintWarning(8, docs.LintPlaintextSecret, "field e is a secret and should be set with an environment variable such as ${FOO} rather than a plaintext value"),
+	}, spec.LintYAML(lintCtx, &node))
+}
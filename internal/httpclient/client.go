@@ -433,7 +433,12 @@ func unexpectedErr(res *http.Response) error {
 	if err != nil {
 		return err
 	}
-	return component.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status, Body: body}
+	return component.ErrUnexpectedHTTPRes{
+		Code:             res.StatusCode,
+		S:                res.Status,
+		Body:             body,
+		RetryAfterHeader: res.Header.Get("Retry-After"),
+	}
 }
 
 // Send creates an HTTP request from the client config, a provided message to be
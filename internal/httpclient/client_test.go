@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/httpclient/oldconfig"
 	"github.com/benthosdev/benthos/v4/internal/manager/mock"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -47,6 +48,33 @@ func TestHTTPClientRetries(t *testing.T) {
 	assert.Equal(t, uint32(4), atomic.LoadUint32(&reqCount))
 }
 
+func TestHTTPClientUnexpectedResRetryAfter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	conf := oldconfig.NewOldConfig()
+	conf.URL = ts.URL + "/testpost"
+	conf.NumRetries = 0
+
+	h, err := NewClientFromOldConfig(conf, mock.NewManager())
+	require.NoError(t, err)
+	defer h.Close(context.Background())
+
+	out := message.QuickBatch([][]byte{[]byte("test")})
+	_, err = h.Send(context.Background(), out)
+
+	var hErr component.ErrUnexpectedHTTPRes
+	require.ErrorAs(t, err, &hErr)
+	assert.True(t, hErr.IsRetryable())
+
+	d, ok := hErr.RetryAfter()
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+}
+
 func TestHTTPClientBadRequest(t *testing.T) {
 	conf := oldconfig.NewOldConfig()
 	conf.URL = "htp://notvalid:1111"
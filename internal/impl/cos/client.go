@@ -0,0 +1,88 @@
+package cos
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// The defaults of the connection pooling fields, which match those of the
+// default transport of the standard library.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = http.DefaultMaxIdleConnsPerHost
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// transportFromConfig creates an HTTP transport from a parsed config containing
+// the connection pooling fields max_idle_conns, max_idle_conns_per_host and
+// idle_conn_timeout.
+func transportFromConfig(conf *service.ParsedConfig) (*http.Transport, error) {
+	maxIdleConns, err := conf.FieldInt("max_idle_conns")
+	if err != nil {
+		return nil, err
+	}
+	maxIdleConnsPerHost, err := conf.FieldInt("max_idle_conns_per_host")
+	if err != nil {
+		return nil, err
+	}
+	idleConnTimeout, err := conf.FieldDuration("idle_conn_timeout")
+	if err != nil {
+		return nil, err
+	}
+	if maxIdleConns < 0 || maxIdleConnsPerHost < 0 || idleConnTimeout < 0 {
+		return nil, errors.New("connection pooling fields must not be negative")
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = maxIdleConns
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	t.IdleConnTimeout = idleConnTimeout
+	return t, nil
+}
+
+// newCOSClient parses the bucket URL and returns a client authorised with the
+// provided credentials, or an anonymous client when both are empty. A nil
+// transport results in the default transport being used.
+func newCOSClient(bucketURL, secretID, secretKey string, timeout time.Duration, transport http.RoundTripper) (*cos.Client, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bucket url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("bucket url scheme must be http or https, got: %q", u.Scheme)
+	}
+	return cos.NewClient(&cos.BaseURL{BucketURL: u}, newCOSHTTPClient(secretID, secretKey, timeout, transport)), nil
+}
+
+// newCOSHTTPClient returns an HTTP client that signs requests with the provided
+// credentials, or makes requests anonymously when both are empty. Requests are
+// made with the provided transport, or the default transport when nil.
+func newCOSHTTPClient(secretID, secretKey string, timeout time.Duration, transport http.RoundTripper) *http.Client {
+	httpClient := &http.Client{Timeout: timeout, Transport: transport}
+	if secretID != "" || secretKey != "" {
+		httpClient.Transport = &cos.AuthorizationTransport{
+			SecretID:  secretID,
+			SecretKey: secretKey,
+			Transport: transport,
+		}
+	}
+	return httpClient
+}
+
+// bucketNameFromURL returns the name of a bucket from its URL, which is the
+// first label of the host.
+func bucketNameFromURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	name, _, _ := strings.Cut(u.Hostname(), ".")
+	return name
+}
@@ -0,0 +1,68 @@
+package cos
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestNewCOSHTTPClientCredentials(t *testing.T) {
+	client := newCOSHTTPClient("id", "key", time.Second, nil)
+	assert.Equal(t, time.Second, client.Timeout)
+
+	transport, ok := client.Transport.(*cos.AuthorizationTransport)
+	require.True(t, ok)
+	assert.Equal(t, "id", transport.SecretID)
+	assert.Equal(t, "key", transport.SecretKey)
+	assert.Nil(t, transport.Transport)
+}
+
+func TestNewCOSHTTPClientAnonymous(t *testing.T) {
+	client := newCOSHTTPClient("", "", time.Second, nil)
+	assert.Nil(t, client.Transport)
+}
+
+func TestNewCOSHTTPClientTransport(t *testing.T) {
+	pConf, err := cosOutputConfig().ParseYAML(`
+url: https://foo-1250000000.cos.ap-beijing.myqcloud.com
+secret_id: id
+secret_key: key
+directory: foo
+path: bar.txt
+max_idle_conns_per_host: 64
+`, nil)
+	require.NoError(t, err)
+
+	httpTransport, err := transportFromConfig(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxIdleConns, httpTransport.MaxIdleConns)
+	assert.Equal(t, 64, httpTransport.MaxIdleConnsPerHost)
+	assert.Equal(t, defaultIdleConnTimeout, httpTransport.IdleConnTimeout)
+
+	client := newCOSHTTPClient("id", "key", time.Second, httpTransport)
+	transport, ok := client.Transport.(*cos.AuthorizationTransport)
+	require.True(t, ok)
+	assert.Same(t, httpTransport, transport.Transport)
+
+	client = newCOSHTTPClient("", "", time.Second, httpTransport)
+	assert.Same(t, httpTransport, client.Transport)
+}
+
+func TestNewCOSClientBadURL(t *testing.T) {
+	_, err := newCOSClient("ftp://foo", "", "", 0, nil)
+	require.Error(t, err)
+
+	_, err = newCOSClient("https://foo-1250000000.cos.ap-beijing.myqcloud.com", "", "", 0, nil)
+	require.NoError(t, err)
+}
+
+func TestBucketNameFromURL(t *testing.T) {
+	u, err := url.Parse("https://foo-1250000000.cos.ap-beijing.myqcloud.com")
+	require.NoError(t, err)
+	assert.Equal(t, "foo-1250000000", bucketNameFromURL(u))
+	assert.Equal(t, "", bucketNameFromURL(nil))
+}
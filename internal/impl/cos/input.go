@@ -0,0 +1,347 @@
+package cos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	maxCOSListObjectsResults = 100
+)
+
+func cosInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Downloads objects within a Tencent Cloud COS bucket, optionally filtered by a prefix.").
+		Description(`
+Each object is consumed as a single message containing the full contents of the object. Objects with a ` + "`Content-Encoding`" + ` header of ` + "`gzip`, `zstd` or `snappy`" + ` are decompressed automatically, otherwise the ` + "`compression`" + ` field determines how objects are decompressed.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "```" + `
+- cos_key
+- cos_last_modified
+` + "```" + `
+
+You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).`).
+		Field(service.NewStringField("url").Description("Access the domain name of the cos bucket.")).
+		Field(service.NewStringField("secret_id").
+			Description("User's Secret ID. When both `secret_id` and `secret_key` are empty requests are made anonymously, which is only permitted for buckets with public access.").
+			Default("")).
+		Field(service.NewStringField("secret_key").
+			Description("User's Secret key.").
+			Secret().
+			Default("")).
+		Field(service.NewStringField("prefix").
+			Description("An optional path prefix, if set only objects with the prefix are consumed.").
+			Default("")).
+		Field(objstore.KeySuffixField()).
+		Field(objstore.KeyRegexField()).
+		Field(service.NewBoolField("delete_objects").
+			Description("Whether to delete downloaded objects from the bucket once they are processed.").
+			Advanced().
+			Default(false)).
+		Field(objstore.CompressionField()).
+		Field(objstore.FilenameMetadataField()).
+		Field(objstore.ReassemblePartsField()).
+		Field(objstore.CheckpointCacheField()).
+		Field(objstore.DownloadThreadsField())
+}
+
+func init() {
+	err := service.RegisterInput("cos", cosInputConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+		i, err := newCosInputFromConfig(conf, mgr)
+		if err != nil {
+			return nil, err
+		}
+		return service.AutoRetryNacks(i), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newCosInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (c *cosInput, err error) {
+	c = &cosInput{}
+	c.logger = mgr.Logger()
+	if c.url, err = conf.FieldString("url"); err != nil {
+		return nil, err
+	}
+	if c.secretId, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if c.secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if c.prefix, err = conf.FieldString("prefix"); err != nil {
+		return nil, err
+	}
+	if c.filter, err = objstore.KeyFilterFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if c.deleteObjects, err = conf.FieldBool("delete_objects"); err != nil {
+		return nil, err
+	}
+	if c.compression, err = objstore.CompressionFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if c.filename, err = objstore.FilenameFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if c.reassemble, err = conf.FieldBool("reassemble_parts"); err != nil {
+		return nil, err
+	}
+	if !c.deleteObjects {
+		if c.checkpoint, err = objstore.CheckpointFromConfig(conf, c.url, mgr); err != nil {
+			return nil, err
+		}
+	}
+	if c.downloads, err = objstore.DownloadsFromConfig(conf); err != nil {
+		return nil, err
+	}
+	return
+}
+
+type cosInput struct {
+	url           string
+	secretId      string
+	secretKey     string
+	prefix        string
+	filter        objstore.KeyFilter
+	deleteObjects bool
+	compression   objstore.Compression
+	filename      objstore.Filename
+	reassemble    bool
+	checkpoint    *objstore.Checkpoint
+
+	clientMut sync.Mutex
+	client    *cos.Client
+
+	pending   []cos.Object
+	marker    string
+	exhausted bool
+	downloads *objstore.Downloads
+
+	logger *service.Logger
+}
+
+func (c *cosInput) Connect(ctx context.Context) error {
+	c.clientMut.Lock()
+	defer c.clientMut.Unlock()
+
+	client, err := newCOSClient(c.url, c.secretId, c.secretKey, 0, nil)
+	if err != nil {
+		return err
+	}
+	c.client = client
+
+	c.pending = nil
+	c.marker = ""
+	c.exhausted = false
+	c.downloads.Reset()
+	c.logger.Infof("Downloading COS objects from bucket: %s\n", c.url)
+	return nil
+}
+
+// nextObject pops the next object from the pending listing, requesting a new
+// page of results from the bucket when the current one has been drained.
+func (c *cosInput) nextObject(ctx context.Context) (cos.Object, error) {
+	for len(c.pending) == 0 {
+		if c.exhausted {
+			return cos.Object{}, service.ErrEndOfInput
+		}
+		res, _, err := c.client.Bucket.Get(ctx, &cos.BucketGetOptions{
+			Prefix:  c.prefix,
+			Marker:  c.marker,
+			MaxKeys: maxCOSListObjectsResults,
+		})
+		if err != nil {
+			return cos.Object{}, err
+		}
+		c.pending = res.Contents
+		c.marker = res.NextMarker
+		c.exhausted = !res.IsTruncated
+	}
+	obj := c.pending[0]
+	c.pending = c.pending[1:]
+	return obj, nil
+}
+
+// objectCompression returns the compression algorithm of a downloaded object,
+// which is described by its Content-Encoding header when recognised, and
+// otherwise the configured compression algorithm.
+func (c *cosInput) objectCompression(header http.Header) objstore.Compression {
+	if compression, ok := objstore.CompressionFromContentEncoding(header.Get("Content-Encoding")); ok {
+		return compression
+	}
+	return c.compression
+}
+
+// nextUnprocessedObject returns the next listed object to consume, skipping
+// parts that are consumed along with their manifest, objects with keys that do
+// not match the filter, and objects that have already been checkpointed.
+func (c *cosInput) nextUnprocessedObject(ctx context.Context) (cos.Object, error) {
+	for {
+		obj, err := c.nextObject(ctx)
+		if err != nil {
+			return obj, err
+		}
+		if c.reassemble && objstore.IsPartKey(obj.Key) {
+			continue
+		}
+		if !c.filter.Matches(c.logicalKey(obj.Key)) {
+			continue
+		}
+		processed, err := c.checkpoint.Processed(ctx, obj.Key, obj.ETag)
+		if err != nil {
+			c.logger.Warnf("Failed to read checkpoint of object %v, consuming it regardless: %v", obj.Key, err)
+		}
+		if !processed {
+			return obj, nil
+		}
+	}
+}
+
+// logicalKey returns the key of the object that a listed key represents,
+// which for manifests is the key of the object that was split into parts.
+func (c *cosInput) logicalKey(key string) string {
+	if c.reassemble && objstore.IsManifestKey(key) {
+		return strings.TrimSuffix(key, objstore.ManifestSuffix)
+	}
+	return key
+}
+
+// getObject downloads the contents of an object along with its headers.
+func getObject(ctx context.Context, client *cos.Client, key string) ([]byte, http.Header, error) {
+	res, err := client.Object.Get(ctx, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, res.Header, nil
+}
+
+// deleteOnAck returns an AckFunc that deletes the provided keys once a message
+// is successfully processed, when deleting objects is enabled.
+func (c *cosInput) deleteOnAck(client *cos.Client, keys ...string) service.AckFunc {
+	return func(ctx context.Context, res error) error {
+		if res != nil || !c.deleteObjects {
+			return nil
+		}
+		for _, key := range keys {
+			if _, err := client.Object.Delete(ctx, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (c *cosInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	c.clientMut.Lock()
+	defer c.clientMut.Unlock()
+
+	if c.client == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	for !c.downloads.Full() {
+		if c.downloads.Retry() {
+			continue
+		}
+		obj, err := c.nextUnprocessedObject(ctx)
+		if err != nil {
+			// Listing errors are deferred until the downloads in flight are
+			// drained, the listing is retried by the next call.
+			if c.downloads.Len() > 0 {
+				break
+			}
+			return nil, nil, err
+		}
+		client := c.client
+		c.downloads.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+			return c.download(ctx, client, obj)
+		})
+	}
+	return c.downloads.Next(ctx)
+}
+
+// download consumes a listed object as a message. Downloads run concurrently
+// without holding the client mutex, and so the client is provided explicitly.
+func (c *cosInput) download(ctx context.Context, client *cos.Client, obj cos.Object) (*service.Message, service.AckFunc, error) {
+	if c.reassemble && objstore.IsManifestKey(obj.Key) {
+		return c.readManifest(ctx, client, obj)
+	}
+
+	data, header, err := getObject(ctx, client, obj.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data, err = c.objectCompression(header).Decompress(data); err != nil {
+		return nil, nil, objstore.PermanentError(fmt.Errorf("failed to decompress object %v: %w", obj.Key, err))
+	}
+
+	msg := service.NewMessage(data)
+	msg.MetaSetMut("cos_key", obj.Key)
+	msg.MetaSetMut("cos_last_modified", obj.LastModified)
+	c.filename.SetMessageMetadata(msg, header.Get("x-cos-meta-"+objstore.FilenameUserMetadataKey))
+	return msg, c.checkpoint.Ack(obj.Key, obj.ETag, c.deleteOnAck(client, obj.Key)), nil
+}
+
+// readManifest consumes the parts listed by a manifest object as a single
+// message, keyed by the object that was split into the parts.
+func (c *cosInput) readManifest(ctx context.Context, client *cos.Client, obj cos.Object) (*service.Message, service.AckFunc, error) {
+	manifestBytes, header, err := getObject(ctx, client, obj.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := objstore.ParseManifest(manifestBytes)
+	if err != nil {
+		return nil, nil, objstore.PermanentError(fmt.Errorf("failed to read manifest %v: %w", obj.Key, err))
+	}
+
+	data, err := m.Reassemble(ctx, func(ctx context.Context, key string) ([]byte, error) {
+		data, _, err := getObject(ctx, client, key)
+		return data, err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reassemble manifest %v: %w", obj.Key, err)
+	}
+
+	key := c.logicalKey(obj.Key)
+	if data, err = m.ObjectCompression(c.compression).Decompress(data); err != nil {
+		return nil, nil, objstore.PermanentError(fmt.Errorf("failed to decompress object %v: %w", key, err))
+	}
+
+	msg := service.NewMessage(data)
+	msg.MetaSetMut("cos_key", key)
+	msg.MetaSetMut("cos_last_modified", obj.LastModified)
+	c.filename.SetMessageMetadata(msg, header.Get("x-cos-meta-"+objstore.FilenameUserMetadataKey))
+	return msg, c.checkpoint.Ack(obj.Key, obj.ETag, c.deleteOnAck(client, append(m.Parts, obj.Key)...)), nil
+}
+
+func (c *cosInput) Close(ctx context.Context) error {
+	c.clientMut.Lock()
+	c.downloads.Reset()
+	c.client = nil
+	c.clientMut.Unlock()
+	return nil
+}
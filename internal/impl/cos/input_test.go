@@ -0,0 +1,192 @@
+package cos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testCOSInput(t *testing.T, handler http.HandlerFunc, conf string) *cosInput {
+	t.Helper()
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	pConf, err := cosInputConfig().ParseYAML(`
+url: `+ts.URL+`
+`+conf, nil)
+	require.NoError(t, err)
+
+	c, err := newCosInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, c.Connect(context.Background()))
+	return c
+}
+
+const testCOSListing = `<ListBucketResult>
+  <Name>foo</Name>
+  <IsTruncated>false</IsTruncated>
+  <Contents><Key>a.txt</Key><ETag>"1"</ETag></Contents>
+  <Contents><Key>b.txt</Key><ETag>"2"</ETag></Contents>
+  <Contents><Key>c.txt</Key><ETag>"3"</ETag></Contents>
+</ListBucketResult>`
+
+func TestCOSInputDownloadThreads(t *testing.T) {
+	release := make(chan struct{})
+	c := testCOSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(testCOSListing))
+		case "/a.txt":
+			// The first object is only downloaded once the others have been
+			// requested, which requires them to be downloaded concurrently.
+			<-release
+			_, _ = w.Write([]byte("foo"))
+		case "/b.txt":
+			_, _ = w.Write([]byte("bar"))
+		case "/c.txt":
+			close(release)
+			_, _ = w.Write([]byte("baz"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, `
+download_threads: 3
+`)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	for _, exp := range []string{"foo", "bar", "baz"} {
+		msg, ack, err := c.Read(ctx)
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(body))
+		require.NoError(t, ack(ctx, nil))
+	}
+
+	_, _, err := c.Read(ctx)
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+}
+
+func TestCOSInputRetriesFailedDownloads(t *testing.T) {
+	var attempts int
+	c := testCOSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(testCOSListing))
+		case "/a.txt":
+			if attempts++; attempts == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("foo"))
+		case "/b.txt":
+			_, _ = w.Write([]byte("bar"))
+		case "/c.txt":
+			_, _ = w.Write([]byte("baz"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, "")
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	_, _, err := c.Read(ctx)
+	require.Error(t, err)
+
+	for _, exp := range []string{"foo", "bar", "baz"} {
+		msg, ack, err := c.Read(ctx)
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(body))
+		require.NoError(t, ack(ctx, nil))
+	}
+	assert.Equal(t, 2, attempts)
+
+	_, _, err = c.Read(ctx)
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+}
+
+func TestCOSInputCloseCancelsDownloads(t *testing.T) {
+	cancelled := make(chan struct{}, 2)
+	c := testCOSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(testCOSListing))
+			return
+		}
+		<-r.Context().Done()
+		select {
+		case cancelled <- struct{}{}:
+		default:
+		}
+	}, `
+download_threads: 2
+`)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer done()
+
+	_, _, err := c.Read(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, c.Close(context.Background()))
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second * 5):
+		t.Fatal("downloads were not cancelled by close")
+	}
+}
+
+func TestCOSInputSkipsCorruptObjects(t *testing.T) {
+	var attempts int
+	c := testCOSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(testCOSListing))
+		case "/a.txt":
+			attempts++
+			w.Header().Set("Content-Encoding", "zstd")
+			_, _ = w.Write([]byte("not zstd"))
+		case "/b.txt":
+			_, _ = w.Write([]byte("bar"))
+		case "/c.txt":
+			_, _ = w.Write([]byte("baz"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, "")
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	_, _, err := c.Read(ctx)
+	require.ErrorContains(t, err, "failed to decompress object a.txt")
+
+	// Objects that cannot be decompressed are skipped rather than retried.
+	for _, exp := range []string{"bar", "baz"} {
+		msg, ack, err := c.Read(ctx)
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(body))
+		require.NoError(t, ack(ctx, nil))
+	}
+	assert.Equal(t, 1, attempts)
+
+	_, _, err = c.Read(ctx)
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+}
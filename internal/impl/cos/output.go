@@ -3,11 +3,16 @@ package cos
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/service"
-	"github.com/tencentyun/cos-go-sdk-v5"
-	"net/http"
-	"net/url"
 )
 
 func cosOutputConfig() *service.ConfigSpec {
@@ -17,14 +22,57 @@ func cosOutputConfig() *service.ConfigSpec {
 		Summary("Sends message parts as files to a cos.").
 		Description(``).
 		Field(service.NewStringField("url").Description("Access the domain name of the cos bucket.")).
-		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
-		Field(service.NewStringField("secret_key").Description("User's Secret key.")).
-		Field(service.NewInterpolatedStringField("directory").Description("A directory to store message files within. If the directory does not exist it will be created.")).
-		Field(service.NewInterpolatedStringField("path").Description("The path of each message to upload.")).
+		Field(service.NewStringField("secret_id").
+			Description("User's Secret ID. When both `secret_id` and `secret_key` are empty requests are made anonymously, which is only permitted for buckets with public access.").
+			Default("")).
+		Field(service.NewStringField("secret_key").
+			Description("User's Secret key.").
+			Secret().
+			Default("")).
+		Field(objstore.DirectoryField()).
+		Field(objstore.PathField()).
+		Field(objstore.CompressionField()).
+		Field(objstore.SkipPrecompressedField()).
+		Field(objstore.BundleField()).
+		Field(objstore.FailFastField()).
+		Field(objstore.SerializeKeyWritesField()).
+		Field(objstore.MaxObjectSizeField()).
+		Field(objstore.MaxMessageBytesField()).
+		Field(objstore.VerifyOnConnectField()).
+		Field(objstore.CacheControlField()).
+		Field(objstore.ExpiresField()).
+		Field(objstore.FilenameMetadataField()).
+		Field(service.NewInterpolatedStringField("content_type").
+			Description("The content type to set for each object.").
+			Advanced().
+			Default("")).
+		Field(service.NewStringField("storage_class").
+			Description("The storage class to set for each object, when empty the bucket default is used.").
+			Example("STANDARD").
+			Example("STANDARD_IA").
+			Example("ARCHIVE").
+			Advanced().
+			Default("")).
+		Field(service.NewDurationField("timeout").
+			Description("The maximum period to wait for an upload request to complete.").
+			Advanced().
+			Default("30s")).
+		Field(service.NewIntField("max_idle_conns").
+			Description("The maximum number of idle (keep-alive) connections kept open across all hosts. Zero means no limit.").
+			Advanced().
+			Default(defaultMaxIdleConns)).
+		Field(service.NewIntField("max_idle_conns_per_host").
+			Description("The maximum number of idle (keep-alive) connections kept open to a single host. Increasing this allows connections to be reused when uploading with a large number of writes in flight, rather than being closed and reopened.").
+			Advanced().
+			Default(defaultMaxIdleConnsPerHost)).
+		Field(service.NewDurationField("idle_conn_timeout").
+			Description("The maximum period an idle (keep-alive) connection remains open before closing itself. Zero means no limit.").
+			Advanced().
+			Default(defaultIdleConnTimeout.String())).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of inserts to run in parallel.").
 			Default(64))
-	spec = spec.Field(service.NewBatchPolicyField("batching")).
+	spec = spec.Field(objstore.BatchPolicyField()).
 		Version("3.65.0").
 		Example("file to cos",
 			`Here we send data to COS in batches`,
@@ -55,14 +103,16 @@ func init() {
 		if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
 			return
 		}
-		out, err = newCosOutputFromConfig(conf, mgr.Logger())
+		out, err = newCosOutputFromConfig(conf, mgr)
 		return
 	})
 }
 
-func newCosOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (c *cosOutput, err error) {
+func newCosOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (c *cosOutput, err error) {
 	c = &cosOutput{}
-	c.logger = logger
+	c.logger = mgr.Logger()
+	c.shutSig = shutdown.NewSignaller()
+	c.metrics = objstore.NewMetrics(mgr.Metrics())
 	if c.url, err = conf.FieldString("url"); err != nil {
 		return nil, err
 	}
@@ -72,10 +122,28 @@ func newCosOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger)
 	if c.secretKey, err = conf.FieldString("secret_key"); err != nil {
 		return nil, err
 	}
-	if c.directory, err = conf.FieldInterpolatedString("directory"); err != nil {
+	if c.verifyOnConnect, err = conf.FieldBool("verify_on_connect"); err != nil {
+		return nil, err
+	}
+	if c.writer, err = objstore.NewWriterFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if c.headers, err = objstore.HeadersFromConfig(conf); err != nil {
 		return nil, err
 	}
-	if c.path, err = conf.FieldInterpolatedString("path"); err != nil {
+	if c.filename, err = objstore.FilenameFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if c.contentType, err = conf.FieldInterpolatedString("content_type"); err != nil {
+		return nil, err
+	}
+	if c.storageClass, err = conf.FieldString("storage_class"); err != nil {
+		return nil, err
+	}
+	if c.timeout, err = conf.FieldDuration("timeout"); err != nil {
+		return nil, err
+	}
+	if c.transport, err = transportFromConfig(conf); err != nil {
 		return nil, err
 	}
 	return
@@ -85,9 +153,17 @@ type cosOutput struct {
 	url       string
 	secretId  string
 	secretKey string
+	timeout   time.Duration
+	transport *http.Transport
+
+	verifyOnConnect bool
 
-	directory *service.InterpolatedString
-	path      *service.InterpolatedString
+	writer       *objstore.Writer
+	headers      objstore.Headers
+	filename     objstore.Filename
+	metrics      *objstore.Metrics
+	contentType  *service.InterpolatedString
+	storageClass string
 
 	client *cos.Client
 
@@ -96,33 +172,77 @@ type cosOutput struct {
 }
 
 func (c *cosOutput) Connect(ctx context.Context) error {
-	u, _ := url.Parse(c.url)
-	b := &cos.BaseURL{BucketURL: u}
-	c.client = cos.NewClient(b, &http.Client{
-		Transport: &cos.AuthorizationTransport{
-			SecretID:  c.secretId,
-			SecretKey: c.secretKey,
-		},
-	})
+	client, err := newCOSClient(c.url, c.secretId, c.secretKey, c.timeout, c.transport)
+	if err != nil {
+		return err
+	}
+	if c.verifyOnConnect {
+		if _, err = client.Bucket.Head(ctx); err != nil {
+			return fmt.Errorf("failed to access bucket %v: %w", bucketNameFromURL(client.BaseURL.BucketURL), err)
+		}
+	}
+	c.client = client
 	return nil
 }
 
-func (c *cosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
-	for _, msg := range batch {
-		data, err := msg.AsBytes()
-		if err != nil {
-			return err
+func (c *cosOutput) putObject(ctx context.Context, msg *service.Message, key string, body []byte, compression objstore.Compression) error {
+	opts, err := c.putOptions(msg, compression)
+	if err != nil {
+		return err
+	}
+	c.logger.Infof("Writing to COS: %s", key)
+	res, err := c.client.Object.Put(ctx, key, bytes.NewReader(body), opts)
+	if err != nil {
+		osErr := component.ErrObjectStorage{
+			Bucket: bucketNameFromURL(c.client.BaseURL.BucketURL),
+			Key:    key,
+			Err:    err,
 		}
-		key := c.directory.String(msg) + c.path.String(msg)
-		c.logger.Infof("Writing to COS: %s", key)
-		_, err = c.client.Object.Put(ctx, key, bytes.NewReader(data), nil)
-		if err != nil {
-			return err
+		if res != nil && res.Response != nil {
+			osErr.StatusCode = res.StatusCode
 		}
+		return osErr
 	}
 	return nil
 }
 
+func (c *cosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	ctx, done := c.shutSig.CloseNowCtx(ctx)
+	defer done()
+
+	return c.writer.WriteBatch(ctx, batch, c.metrics.Wrap(c.putObject, func(*service.Message) string {
+		return bucketNameFromURL(c.client.BaseURL.BucketURL)
+	}))
+}
+
+// putOptions returns the options to upload a message with, or nil when there
+// are no options to set. The content encoding of objects is set according to
+// the compression applied to their bodies.
+func (c *cosOutput) putOptions(msg *service.Message, compression objstore.Compression) (*cos.ObjectPutOptions, error) {
+	opts := cos.ObjectPutHeaderOptions{
+		ContentType:      c.contentType.String(msg),
+		ContentEncoding:  compression.ContentEncoding(),
+		CacheControl:     c.headers.CacheControl(msg),
+		XCosStorageClass: c.storageClass,
+	}
+	expires, err := c.headers.Expires(msg)
+	if err != nil {
+		return nil, err
+	}
+	if !expires.IsZero() {
+		opts.Expires = expires.UTC().Format(http.TimeFormat)
+	}
+	if filename, ok := c.filename.UserMetadata(msg); ok {
+		opts.XCosMetaXXX = &http.Header{}
+		opts.XCosMetaXXX.Set("x-cos-meta-"+objstore.FilenameUserMetadataKey, filename)
+	}
+	if opts == (cos.ObjectPutHeaderOptions{}) {
+		return nil, nil
+	}
+	return &cos.ObjectPutOptions{ObjectPutHeaderOptions: &opts}, nil
+}
+
 func (c *cosOutput) Close(ctx context.Context) error {
+	c.shutSig.CloseNow()
 	return nil
 }
@@ -0,0 +1,183 @@
+package cos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestCOSOutputPutOptions(t *testing.T) {
+	pConf, err := cosOutputConfig().ParseYAML(`
+url: https://foo-123.cos.ap-beijing.myqcloud.com
+directory: foo
+path: bar.txt
+compression: zstd
+`, nil)
+	require.NoError(t, err)
+
+	c, err := newCosOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	msg := service.NewMessage([]byte("hello world"))
+	opts, err := c.putOptions(msg, objstore.CompressionNone)
+	require.NoError(t, err)
+	assert.Nil(t, opts)
+
+	opts, err = c.putOptions(msg, objstore.CompressionZstd)
+	require.NoError(t, err)
+	require.NotNil(t, opts)
+	assert.Equal(t, "zstd", opts.ContentEncoding)
+}
+
+func TestCOSOutputPutOptionsHeaders(t *testing.T) {
+	pConf, err := cosOutputConfig().ParseYAML(`
+url: https://foo-123.cos.ap-beijing.myqcloud.com
+directory: foo
+path: bar.txt
+cache_control: ${! meta("cache") }
+expires: 2026-10-21T09:28:00+02:00
+`, nil)
+	require.NoError(t, err)
+
+	c, err := newCosOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	msg := service.NewMessage([]byte("hello world"))
+	msg.MetaSet("cache", "max-age=60")
+
+	opts, err := c.putOptions(msg, objstore.CompressionNone)
+	require.NoError(t, err)
+	require.NotNil(t, opts)
+	assert.Equal(t, "max-age=60", opts.CacheControl)
+	assert.Equal(t, "Wed, 21 Oct 2026 07:28:00 GMT", opts.Expires)
+}
+
+func TestCOSOutputPutOptionsFilename(t *testing.T) {
+	pConf, err := cosOutputConfig().ParseYAML(`
+url: https://foo-123.cos.ap-beijing.myqcloud.com
+directory: foo
+path: bar.txt
+filename_metadata: original_filename
+`, nil)
+	require.NoError(t, err)
+
+	c, err := newCosOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	opts, err := c.putOptions(service.NewMessage([]byte("hello world")), objstore.CompressionNone)
+	require.NoError(t, err)
+	assert.Nil(t, opts)
+
+	msg := service.NewMessage([]byte("hello world"))
+	msg.MetaSet("original_filename", "foo.txt")
+
+	opts, err = c.putOptions(msg, objstore.CompressionNone)
+	require.NoError(t, err)
+	require.NotNil(t, opts)
+	require.NotNil(t, opts.XCosMetaXXX)
+	assert.Equal(t, "foo.txt", opts.XCosMetaXXX.Get("x-cos-meta-original-filename"))
+}
+
+func TestCOSInputObjectCompression(t *testing.T) {
+	c := &cosInput{compression: objstore.CompressionGzip}
+
+	header := http.Header{}
+	assert.Equal(t, objstore.CompressionGzip, c.objectCompression(header))
+
+	header.Set("Content-Encoding", "snappy")
+	assert.Equal(t, objstore.CompressionSnappy, c.objectCompression(header))
+
+	header.Set("Content-Encoding", "identity")
+	assert.Equal(t, objstore.CompressionGzip, c.objectCompression(header))
+}
+
+func TestCOSOutputVerifyOnConnect(t *testing.T) {
+	var heads int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads++
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(ts.Close)
+
+	pConf, err := cosOutputConfig().ParseYAML(`
+url: `+ts.URL+`
+directory: foo
+path: bar.txt
+`, nil)
+	require.NoError(t, err)
+
+	c, err := newCosOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	err = c.Connect(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to access bucket")
+	assert.Equal(t, 1, heads)
+	assert.Nil(t, c.client)
+
+	pConf, err = cosOutputConfig().ParseYAML(`
+url: `+ts.URL+`
+directory: foo
+path: bar.txt
+verify_on_connect: false
+`, nil)
+	require.NoError(t, err)
+
+	c, err = newCosOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	require.NoError(t, c.Connect(context.Background()))
+	assert.Equal(t, 1, heads)
+	assert.NotNil(t, c.client)
+}
+
+func TestCOSOutputCloseCancelsWrites(t *testing.T) {
+	started := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body is drained so that the server notices the client hanging up.
+		_, _ = io.Copy(io.Discard, r.Body)
+		close(started)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(ts.Close)
+
+	pConf, err := cosOutputConfig().ParseYAML(`
+url: `+ts.URL+`
+directory: foo
+path: bar.txt
+verify_on_connect: false
+`, nil)
+	require.NoError(t, err)
+
+	c, err := newCosOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, c.Connect(context.Background()))
+
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- c.WriteBatch(context.Background(), service.MessageBatch{
+			service.NewMessage([]byte("hello")),
+		})
+	}()
+
+	<-started
+	require.NoError(t, c.Close(context.Background()))
+
+	select {
+	case err := <-writeErr:
+		require.Error(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("write was not cancelled by close")
+	}
+}
@@ -54,6 +54,37 @@ func init() {
 		panic(err)
 	}
 
+	if err := bloblang.RegisterFunctionV2("lookup_env",
+		bloblang.NewPluginSpec().
+			Impure().
+			Static().
+			Category(query.FunctionCategoryEnvironment).
+			Description("Returns the value of an environment variable, or a default value if the environment variable does not exist. When a default is not provided `null` is returned instead. Environment variables that exist but are empty are returned as empty strings.").
+			Param(bloblang.NewStringParam("name").Description("The name of an environment variable.")).
+			Param(bloblang.NewAnyParam("default").Description("A value to return when the environment variable does not exist.").Optional()).
+			Example("", `root.thing.key = lookup_env("key", "default value")`).
+			Example("", `root.thing.port = lookup_env(name: "port", default: 8080)`),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			value, err := args.Get("default")
+			if err != nil {
+				return nil, err
+			}
+			if valueStr, exists := os.LookupEnv(name); exists {
+				value = valueStr
+			}
+
+			return func() (any, error) {
+				return value, nil
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
 	if err := bloblang.RegisterFunctionV2("file",
 		bloblang.NewPluginSpec().
 			Impure().
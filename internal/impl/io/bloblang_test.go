@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/public/bloblang"
 )
 
 func TestEnvFunction(t *testing.T) {
@@ -35,3 +36,40 @@ func TestHostname(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, hostname, res)
 }
+
+func TestLookupEnvFunction(t *testing.T) {
+	key := "BENTHOS_TEST_BLOBLANG_LOOKUP_ENV"
+	os.Setenv(key, "foobar")
+	t.Cleanup(func() {
+		os.Unsetenv(key)
+	})
+
+	e, err := query.InitFunctionHelper("lookup_env", key, "default")
+	require.NoError(t, err)
+
+	res, err := e.Exec(query.FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "foobar", res)
+
+	e, err = query.InitFunctionHelper("lookup_env", key+"_MISSING", "default")
+	require.NoError(t, err)
+
+	res, err = e.Exec(query.FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "default", res)
+
+	e, err = query.InitFunctionHelper("lookup_env", key+"_MISSING")
+	require.NoError(t, err)
+
+	res, err = e.Exec(query.FunctionContext{})
+	require.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func TestLookupEnvFunctionNotPure(t *testing.T) {
+	_, err := bloblang.NewEnvironment().Parse(`root = lookup_env("FOO", "bar")`)
+	require.NoError(t, err)
+
+	_, err = bloblang.NewEnvironment().OnlyPure().Parse(`root = lookup_env("FOO", "bar")`)
+	require.Error(t, err)
+}
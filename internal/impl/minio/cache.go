@@ -0,0 +1,179 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// minioCacheExpiresMeta is the user metadata key used to store the time at
+// which a cached object expires.
+const minioCacheExpiresMeta = "Benthos-Expires"
+
+func minioCacheConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Use a minio bucket as a cache.").
+		Description(`Each item is stored as an object within the bucket, with a key consisting of the item key appended to the configured prefix.
+
+### TTL
+
+Minio does not support expiring individual objects, and therefore TTLs are best-effort only. The expiry time of an item is stored as metadata of its object, and items that are found to be expired when read are deleted and treated as missing. Expired items that are never read again remain within the bucket, and so it is recommended to also configure a lifecycle rule for the bucket.
+
+It is not possible to atomically upload objects exclusively when the target does not already exist, therefore this cache is not suitable for deduplication.`).
+		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to bucket.")).
+		Field(service.NewStringField("bucket").Description("The name of the bucket to store items in.")).
+		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
+		Field(service.NewStringField("secret_key").Description("User's Secret key.").Secret()).
+		Field(service.NewStringField("prefix").
+			Description("A prefix to add to the key of each item.").
+			Example("benthos/cache/").
+			Default("")).
+		Field(service.NewDurationField("default_ttl").
+			Description("An optional default TTL to set for items, calculated from the moment the item is set.").
+			Example("60s").
+			Optional())
+}
+
+func init() {
+	err := service.RegisterCache("minio", minioCacheConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Cache, error) {
+		return newMinioCacheFromConfig(conf)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type minioCache struct {
+	bucketName string
+	prefix     string
+	defaultTTL *time.Duration
+
+	client *minio.Client
+}
+
+func newMinioCacheFromConfig(conf *service.ParsedConfig) (c *minioCache, err error) {
+	c = &minioCache{}
+
+	var endpoint, secretID, secretKey string
+	if endpoint, err = conf.FieldString("endpoint"); err != nil {
+		return nil, err
+	}
+	if c.bucketName, err = conf.FieldString("bucket"); err != nil {
+		return nil, err
+	}
+	if secretID, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if c.prefix, err = conf.FieldString("prefix"); err != nil {
+		return nil, err
+	}
+	if conf.Contains("default_ttl") {
+		var ttl time.Duration
+		if ttl, err = conf.FieldDuration("default_ttl"); err != nil {
+			return nil, err
+		}
+		c.defaultTTL = &ttl
+	}
+
+	if c.client, err = newMinioClient(endpoint, secretID, secretKey, "", minio.BucketLookupAuto); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *minioCache) objectKey(key string) string {
+	return c.prefix + key
+}
+
+// putOptions returns the options for uploading an item, which include the
+// expiry time of the item when a TTL applies.
+func (c *minioCache) putOptions(ttl *time.Duration, now time.Time) minio.PutObjectOptions {
+	var opts minio.PutObjectOptions
+	if ttl == nil {
+		ttl = c.defaultTTL
+	}
+	if ttl != nil {
+		expires := now.Add(*ttl)
+		opts.UserMetadata = map[string]string{
+			minioCacheExpiresMeta: expires.UTC().Format(time.RFC3339Nano),
+		}
+	}
+	return opts
+}
+
+// isExpired returns whether the metadata of an object indicates that the item
+// it contains has expired.
+func isExpired(metadata http.Header, now time.Time) bool {
+	expiresStr := metadata.Get("X-Amz-Meta-" + minioCacheExpiresMeta)
+	if expiresStr == "" {
+		return false
+	}
+	expires, err := time.Parse(time.RFC3339Nano, expiresStr)
+	if err != nil {
+		return false
+	}
+	return !now.Before(expires)
+}
+
+func (c *minioCache) Get(ctx context.Context, key string) ([]byte, error) {
+	objKey := c.objectKey(key)
+
+	obj, err := c.client.GetObject(ctx, c.bucketName, objKey, minio.GetObjectOptions{})
+	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, service.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	defer obj.Close()
+
+	info, err := obj.Stat()
+	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, service.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	if isExpired(info.Metadata, time.Now()) {
+		_ = c.client.RemoveObject(ctx, c.bucketName, objKey, minio.RemoveObjectOptions{})
+		return nil, service.ErrKeyNotFound
+	}
+	return io.ReadAll(obj)
+}
+
+func (c *minioCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	_, err := c.client.PutObject(ctx, c.bucketName, c.objectKey(key), bytes.NewReader(value), int64(len(value)), c.putOptions(ttl, time.Now()))
+	return err
+}
+
+func (c *minioCache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	info, err := c.client.StatObject(ctx, c.bucketName, c.objectKey(key), minio.StatObjectOptions{})
+	if err == nil {
+		if !isExpired(info.Metadata, time.Now()) {
+			return service.ErrKeyAlreadyExists
+		}
+	} else if !isNoSuchKey(err) {
+		return err
+	}
+	return c.Set(ctx, key, value, ttl)
+}
+
+func (c *minioCache) Delete(ctx context.Context, key string) error {
+	return c.client.RemoveObject(ctx, c.bucketName, c.objectKey(key), minio.RemoveObjectOptions{})
+}
+
+func (c *minioCache) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package minio
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMinioCache(t *testing.T, conf string) *minioCache {
+	t.Helper()
+
+	pConf, err := minioCacheConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	c, err := newMinioCacheFromConfig(pConf)
+	require.NoError(t, err)
+	return c
+}
+
+func TestMinioCacheObjectKey(t *testing.T) {
+	c := testMinioCache(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+prefix: cache/
+`)
+	assert.Equal(t, "cache/bar", c.objectKey("bar"))
+}
+
+func TestMinioCacheTTL(t *testing.T) {
+	c := testMinioCache(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+default_ttl: 1m
+`)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	opts := c.putOptions(nil, now)
+	assert.Equal(t, "2022-01-01T00:01:00Z", opts.UserMetadata[minioCacheExpiresMeta])
+
+	ttl := time.Hour
+	opts = c.putOptions(&ttl, now)
+	assert.Equal(t, "2022-01-01T01:00:00Z", opts.UserMetadata[minioCacheExpiresMeta])
+
+	metadata := http.Header{}
+	assert.False(t, isExpired(metadata, now))
+
+	metadata.Set("X-Amz-Meta-Benthos-Expires", opts.UserMetadata[minioCacheExpiresMeta])
+	assert.False(t, isExpired(metadata, now.Add(time.Minute)))
+	assert.True(t, isExpired(metadata, now.Add(time.Hour)))
+}
+
+func TestMinioCacheNoTTL(t *testing.T) {
+	c := testMinioCache(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+`)
+
+	opts := c.putOptions(nil, time.Now())
+	assert.Empty(t, opts.UserMetadata)
+}
@@ -0,0 +1,90 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// newMinioClient returns a client for the endpoint authorised with the provided
+// credentials. When the region is empty it is resolved from the bucket location
+// when required.
+func newMinioClient(endpoint, secretID, secretKey, region string, lookup minio.BucketLookupType) (*minio.Client, error) {
+	transport, err := minio.DefaultTransport(false)
+	if err != nil {
+		return nil, err
+	}
+	return minio.New(endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(secretID, secretKey, ""),
+		Secure:       false,
+		Region:       region,
+		BucketLookup: lookup,
+		Transport:    conditionalTransport{RoundTripper: transport},
+	})
+}
+
+type ifNoneMatchKey struct{}
+
+// withIfNoneMatch returns a context that, when provided to an upload, causes it
+// to be made with the header `If-None-Match: *` so that the server rejects it
+// when the object already exists. The minio client does not expose conditional
+// headers as put options, and therefore they're added by the transport.
+func withIfNoneMatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ifNoneMatchKey{}, struct{}{})
+}
+
+// conditionalTransport adds the conditional headers requested with
+// withIfNoneMatch to the requests that commit an object, which are either a
+// single put or the completion of a multipart upload.
+type conditionalTransport struct {
+	http.RoundTripper
+}
+
+func (t conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Value(ifNoneMatchKey{}) != nil && isObjectCommit(req) {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", "*")
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+func isObjectCommit(req *http.Request) bool {
+	query := req.URL.Query()
+	switch req.Method {
+	case http.MethodPut:
+		return !query.Has("partNumber")
+	case http.MethodPost:
+		return query.Has("uploadId")
+	}
+	return false
+}
+
+// bucketLookup returns the addressing style of requests to a bucket. Path-style
+// addressing targets endpoint/bucket/key, otherwise the style is detected from
+// the shape of the endpoint.
+func bucketLookup(forcePathStyle bool) minio.BucketLookupType {
+	if forcePathStyle {
+		return minio.BucketLookupPath
+	}
+	return minio.BucketLookupAuto
+}
+
+// isNoSuchKey returns whether an error returned by minio indicates that an
+// object does not exist.
+func isNoSuchKey(err error) bool {
+	return minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
+
+// isPreconditionFailed returns whether an error returned by minio indicates
+// that the conditional headers of a request were not satisfied.
+func isPreconditionFailed(err error) bool {
+	return minio.ToErrorResponse(err).StatusCode == http.StatusPreconditionFailed
+}
+
+// isBadDigest returns whether an error returned by minio indicates that the
+// checksum sent with an upload did not match the object received.
+func isBadDigest(err error) bool {
+	return minio.ToErrorResponse(err).Code == "BadDigest"
+}
@@ -0,0 +1,51 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headerRecorder struct {
+	header http.Header
+}
+
+func (h *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.header = req.Header
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestConditionalTransport(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		cond   bool
+		exp    string
+	}{
+		{method: http.MethodPut, url: "http://localhost/foo/bar", cond: true, exp: "*"},
+		{method: http.MethodPut, url: "http://localhost/foo/bar", cond: false, exp: ""},
+		{method: http.MethodPut, url: "http://localhost/foo/bar?partNumber=1&uploadId=a", cond: true, exp: ""},
+		{method: http.MethodPost, url: "http://localhost/foo/bar?uploads", cond: true, exp: ""},
+		{method: http.MethodPost, url: "http://localhost/foo/bar?uploadId=a", cond: true, exp: "*"},
+		{method: http.MethodGet, url: "http://localhost/foo?location", cond: true, exp: ""},
+	}
+
+	for _, test := range tests {
+		ctx := context.Background()
+		if test.cond {
+			ctx = withIfNoneMatch(ctx)
+		}
+		req, err := http.NewRequestWithContext(ctx, test.method, test.url, http.NoBody)
+		require.NoError(t, err)
+
+		rec := &headerRecorder{}
+		_, err = conditionalTransport{RoundTripper: rec}.RoundTrip(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, test.exp, rec.header.Get("If-None-Match"), "%v %v", test.method, test.url)
+		assert.Empty(t, req.Header.Get("If-None-Match"), "the original request should not be modified")
+	}
+}
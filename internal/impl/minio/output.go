@@ -2,40 +2,87 @@ package minio
 
 import (
 	"bytes"
-	"github.com/benthosdev/benthos/v4/internal/shutdown"
-	"github.com/benthosdev/benthos/v4/public/service"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
 	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
-)
 
-import (
-	"context"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+	"github.com/benthosdev/benthos/v4/public/service"
 )
 
 func cosOutputConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
 		Stable().
 		Categories("Services").
-		Summary("Sends message parts as files to a Oss.").
+		Summary("Sends message parts as files to a minio bucket.").
 		Description(``).
-		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to bucket.")).
+		Field(service.NewInterpolatedStringField("endpoint").
+			Description("Endpoint corresponding to bucket. This field supports interpolation, allowing messages to be routed to different endpoints, e.g. by region, in which case a client is created for each endpoint as it is first resolved.").
+			Example("minio.example.com:9000").
+			Example(`minio-${! meta("region") }.example.com:9000`)).
 		Field(service.NewStringField("bucket_name").Description("Bucket name")).
 		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
 		Field(service.NewStringField("secret_key").Description("User's Secret key.")).
-		Field(service.NewInterpolatedStringField("directory").Description("A directory to store message files within. If the directory does not exist it will be created.")).
-		Field(service.NewInterpolatedStringField("path").Description("The path of each message to upload.")).
+		Field(service.NewBoolField("force_path_style").
+			Description("Whether to force path-style addressing of the bucket, where objects are uploaded to `endpoint/bucket/key`, instead of detecting whether to use virtual-hosted addressing from the endpoint. This is often required for MinIO deployments behind gateways or custom domains.").
+			Advanced().
+			Default(false)).
+		Field(service.NewBoolField("create_bucket").
+			Description("Whether to create the bucket when it does not exist. The bucket is checked when connecting to a static endpoint, and before the first write to each endpoint when the endpoint is interpolated. This is convenient for ephemeral and test environments, and is safe when several instances attempt to create the same bucket at once.").
+			Advanced().
+			Default(false)).
+		Field(service.NewStringField("create_bucket_region").
+			Description("The region to create the bucket within when `create_bucket` is enabled. When empty the default region of the server is used.").
+			Advanced().
+			Default("")).
+		Field(objstore.DirectoryField()).
+		Field(objstore.PathField()).
+		Field(objstore.CompressionField()).
+		Field(objstore.BundleField()).
+		Field(objstore.FailFastField()).
+		Field(objstore.SerializeKeyWritesField()).
+		Field(objstore.MaxObjectSizeField()).
+		Field(objstore.MaxMessageBytesField()).
+		Field(objstore.VerifyOnConnectField()).
+		Field(objstore.CacheControlField()).
+		Field(objstore.FilenameMetadataField()).
+		Field(objstore.LocalMirrorField()).
+		Field(service.NewInterpolatedStringMapField("tags").
+			Description("Key/value pairs to store with each object as tags, which support interpolation functions.").
+			Example(map[string]any{
+				"retention": `${! meta("retention") }`,
+			}).
+			Advanced().
+			Default(map[string]any{})).
+		Field(service.NewBoolField("send_content_md5").
+			Description("Whether to send the MD5 checksum of each object with its upload, in which case the server verifies the object it receives and rejects the upload when the checksum does not match.").
+			Advanced().
+			Default(false)).
+		Field(service.NewBoolField("if_not_exists").
+			Description("Whether to only upload objects with keys that do not already exist within the bucket, which allows data to be reprocessed without overwriting existing objects. The existence of each key is checked before upload, and uploads are also made with the conditional header `If-None-Match: *`, and therefore an object created by another writer between the check and the upload is only overwritten when the server does not support conditional writes.").
+			Advanced().
+			Default(false)).
+		Field(service.NewBoolField("error_if_exists").
+			Description("When `if_not_exists` is enabled, whether an existing object should result in an error rather than the message being silently skipped.").
+			Advanced().
+			Default(false)).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of inserts to run in parallel.").
 			Default(64))
-	spec = spec.Field(service.NewBatchPolicyField("batching")).
+	spec = spec.Field(objstore.BatchPolicyField()).
 		Version("3.65.0").
-		Example("file to cos",
-			`Here we send data to COS in batches`,
+		Example("file to minio",
+			`Here we send data to minio in batches`,
 			`
 output:
   minio:
     endpoint: xxxxx
-    bucket: xxxx
+    bucket_name: xxxx
     secret_id: xxxxxxxxxxxxxx
     secret_key: xxxxxxxxxxxxxx
     directory: /usr/hive/warehouse/test.db/test_topic_02/ds=${!now().format_timestamp("2006-01-02")}/hr=${!now().format_timestamp("15")}/
@@ -59,15 +106,17 @@ func init() {
 		if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
 			return
 		}
-		out, err = newMinioOutputFromConfig(conf, mgr.Logger())
+		out, err = newMinioOutputFromConfig(conf, mgr)
 		return
 	})
 }
 
-func newMinioOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (m *minioOutput, err error) {
+func newMinioOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (m *minioOutput, err error) {
 	m = &minioOutput{}
-	m.logger = logger
-	if m.endpoint, err = conf.FieldString("endpoint"); err != nil {
+	m.logger = mgr.Logger()
+	m.shutSig = shutdown.NewSignaller()
+	m.metrics = objstore.NewMetrics(mgr.Metrics())
+	if m.endpoint, err = conf.FieldInterpolatedString("endpoint"); err != nil {
 		return nil, err
 	}
 	if m.bucketName, err = conf.FieldString("bucket_name"); err != nil {
@@ -79,56 +128,261 @@ func newMinioOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger
 	if m.secretKey, err = conf.FieldString("secret_key"); err != nil {
 		return nil, err
 	}
-	if m.directory, err = conf.FieldInterpolatedString("directory"); err != nil {
+	if m.forcePathStyle, err = conf.FieldBool("force_path_style"); err != nil {
+		return nil, err
+	}
+	if m.createBucket, err = conf.FieldBool("create_bucket"); err != nil {
+		return nil, err
+	}
+	if m.createBucketRegion, err = conf.FieldString("create_bucket_region"); err != nil {
+		return nil, err
+	}
+	if m.verifyOnConnect, err = conf.FieldBool("verify_on_connect"); err != nil {
+		return nil, err
+	}
+	if m.writer, err = objstore.NewWriterFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if m.headers, err = objstore.HeadersFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if m.filename, err = objstore.FilenameFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if m.mirror, err = objstore.LocalMirrorFromConfig(conf, mgr); err != nil {
 		return nil, err
 	}
-	if m.path, err = conf.FieldInterpolatedString("path"); err != nil {
+	if m.tags, err = conf.FieldInterpolatedStringMap("tags"); err != nil {
+		return nil, err
+	}
+	if m.sendContentMD5, err = conf.FieldBool("send_content_md5"); err != nil {
+		return nil, err
+	}
+	if m.ifNotExists, err = conf.FieldBool("if_not_exists"); err != nil {
+		return nil, err
+	}
+	if m.errorIfExists, err = conf.FieldBool("error_if_exists"); err != nil {
 		return nil, err
 	}
 	return
 }
 
 type minioOutput struct {
-	endpoint   string
-	bucketName string
-	secretId   string
-	secretKey  string
+	endpoint       *service.InterpolatedString
+	bucketName     string
+	secretId       string
+	secretKey      string
+	forcePathStyle bool
 
-	directory *service.InterpolatedString
-	path      *service.InterpolatedString
+	verifyOnConnect    bool
+	createBucket       bool
+	createBucketRegion string
+
+	writer         *objstore.Writer
+	headers        objstore.Headers
+	filename       objstore.Filename
+	mirror         *objstore.LocalMirror
+	metrics        *objstore.Metrics
+	tags           map[string]*service.InterpolatedString
+	sendContentMD5 bool
+	ifNotExists    bool
+	errorIfExists  bool
+
+	clientsMut   sync.Mutex
+	clients      map[string]*minio.Client
+	bucketsReady map[string]struct{}
 
-	client  *minio.Client
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
 }
 
 func (m *minioOutput) Connect(ctx context.Context) error {
-	var err error
-	m.client, err = minio.New(m.endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(m.secretId, m.secretKey, ""),
-		Secure: false,
-	})
+	m.clientsMut.Lock()
+	m.clients = map[string]*minio.Client{}
+	m.bucketsReady = map[string]struct{}{}
+	m.clientsMut.Unlock()
+
+	// Clients of interpolated endpoints are created as they are resolved, and
+	// therefore only a static endpoint can be validated up front.
+	endpoint, isStatic := m.endpoint.Static()
+	if !isStatic || (!m.verifyOnConnect && !m.createBucket) {
+		return nil
+	}
+	client, err := m.getClient(endpoint)
 	if err != nil {
 		return err
 	}
+	if m.createBucket {
+		return m.ensureBucket(ctx, endpoint, client)
+	}
+	exists, err := client.BucketExists(ctx, m.bucketName)
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials for endpoint %v: %w", endpoint, err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %v does not exist at endpoint %v", m.bucketName, endpoint)
+	}
 	return nil
 }
 
-func (m *minioOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
-	for _, msg := range batch {
-		data, err := msg.AsBytes()
-		if err != nil {
+// getClient returns a cached client for the given endpoint, creating one when
+// the endpoint hasn't been seen since the last connect.
+func (m *minioOutput) getClient(endpoint string) (*minio.Client, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint interpolation resolved to an empty string")
+	}
+
+	m.clientsMut.Lock()
+	defer m.clientsMut.Unlock()
+
+	if m.clients == nil {
+		return nil, service.ErrNotConnected
+	}
+	if c, exists := m.clients[endpoint]; exists {
+		return c, nil
+	}
+	c, err := newMinioClient(endpoint, m.secretId, m.secretKey, "", bucketLookup(m.forcePathStyle))
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %v: %w", endpoint, err)
+	}
+	m.clients[endpoint] = c
+	return c, nil
+}
+
+// ensureBucket creates the bucket at an endpoint when it does not already
+// exist, which is only checked once per endpoint until the next connect.
+func (m *minioOutput) ensureBucket(ctx context.Context, endpoint string, client *minio.Client) error {
+	m.clientsMut.Lock()
+	_, ready := m.bucketsReady[endpoint]
+	m.clientsMut.Unlock()
+	if ready {
+		return nil
+	}
+
+	exists, err := client.BucketExists(ctx, m.bucketName)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket %v exists at endpoint %v: %w", m.bucketName, endpoint, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, m.bucketName, minio.MakeBucketOptions{Region: m.createBucketRegion}); err != nil {
+			// Another instance may have created the bucket since we checked, in
+			// which case the failure is benign.
+			if exists, _ = client.BucketExists(ctx, m.bucketName); !exists {
+				return fmt.Errorf("failed to create bucket %v at endpoint %v: %w", m.bucketName, endpoint, err)
+			}
+		} else {
+			m.logger.Infof("Created bucket %v at endpoint %v", m.bucketName, endpoint)
+		}
+	}
+
+	m.clientsMut.Lock()
+	if m.bucketsReady != nil {
+		m.bucketsReady[endpoint] = struct{}{}
+	}
+	m.clientsMut.Unlock()
+	return nil
+}
+
+// objectExists returns whether an object with the given key already exists
+// within the bucket.
+func (m *minioOutput) objectExists(ctx context.Context, client *minio.Client, key string) (bool, error) {
+	_, err := client.StatObject(ctx, m.bucketName, key, minio.StatObjectOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if isNoSuchKey(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// skipExisting returns the outcome of uploading an object with a key that
+// already exists.
+func (m *minioOutput) skipExisting(key string) error {
+	if m.errorIfExists {
+		return fmt.Errorf("object %v already exists", key)
+	}
+	m.logger.Debugf("Skipping upload of existing object: %v", key)
+	return nil
+}
+
+func (m *minioOutput) putObject(ctx context.Context, msg *service.Message, key string, body []byte, _ objstore.Compression) error {
+	endpoint := m.endpoint.String(msg)
+	client, err := m.getClient(endpoint)
+	if err != nil {
+		return err
+	}
+	if m.createBucket {
+		if err := m.ensureBucket(ctx, endpoint, client); err != nil {
 			return err
 		}
-		key := m.directory.String(msg) + m.path.String(msg)
-		_, err = m.client.PutObject(ctx, m.bucketName, key, bytes.NewReader(data), -1, minio.PutObjectOptions{})
+	}
+	if m.ifNotExists {
+		// The existence check covers servers that ignore the conditional
+		// header, which otherwise covers objects created after the check.
+		exists, err := m.objectExists(ctx, client, key)
 		if err != nil {
 			return err
 		}
+		if exists {
+			return m.skipExisting(key)
+		}
+		ctx = withIfNoneMatch(ctx)
+	}
+	if _, err := client.PutObject(ctx, m.bucketName, key, bytes.NewReader(body), int64(len(body)), m.putOptions(msg)); err != nil {
+		if m.ifNotExists && isPreconditionFailed(err) {
+			return m.skipExisting(key)
+		}
+		osErr := component.ErrObjectStorage{
+			Bucket:     m.bucketName,
+			Key:        key,
+			StatusCode: minio.ToErrorResponse(err).StatusCode,
+			Err:        err,
+		}
+		if isBadDigest(err) {
+			osErr.Err = fmt.Errorf("checksum of object rejected by server: %w", err)
+		}
+		return osErr
 	}
 	return nil
 }
 
+// putOptions returns the options to upload a message with.
+func (m *minioOutput) putOptions(msg *service.Message) minio.PutObjectOptions {
+	opts := minio.PutObjectOptions{
+		CacheControl:   m.headers.CacheControl(msg),
+		SendContentMd5: m.sendContentMD5,
+	}
+	if len(m.tags) > 0 {
+		opts.UserTags = make(map[string]string, len(m.tags))
+		for k, v := range m.tags {
+			opts.UserTags[k] = v.String(msg)
+		}
+	}
+	if filename, ok := m.filename.UserMetadata(msg); ok {
+		opts.UserMetadata = map[string]string{
+			objstore.FilenameUserMetadataKey: filename,
+		}
+	}
+	return opts
+}
+
+func (m *minioOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	// Writes are aborted promptly when the output is closed, rather than
+	// relying solely on the cancellation of the provided context.
+	ctx, done := m.shutSig.CloseNowCtx(ctx)
+	defer done()
+
+	put := m.metrics.Wrap(m.putObject, func(*service.Message) string {
+		return m.bucketName
+	})
+	if m.mirror != nil {
+		put = m.mirror.Wrap(put)
+	}
+	return m.writer.WriteBatch(ctx, batch, put)
+}
+
 func (m *minioOutput) Close(ctx context.Context) error {
+	m.shutSig.CloseNow()
 	return nil
 }
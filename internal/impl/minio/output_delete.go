@@ -0,0 +1,167 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func minioDeleteOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Deletes objects from a minio bucket, where each message resolves the key of an object to delete.").
+		Description(`
+The objects of a batch are removed with a single multi-object delete request, and therefore it is recommended to configure batching in order to prune large numbers of objects efficiently. Deleting an object that does not exist is not considered an error.
+
+By default objects that fail to be deleted are logged and the batch is acknowledged regardless. When the field `+"`fail_on_error`"+` is enabled the messages of objects that failed to be deleted are instead rejected, which results in only those messages being retried.`).
+		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to bucket.")).
+		Field(service.NewStringField("bucket").Description("The name of the bucket to delete objects from.")).
+		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
+		Field(service.NewStringField("secret_key").Description("User's Secret key.").Secret()).
+		Field(service.NewInterpolatedStringField("key").
+			Description("The key of the object to delete.").
+			Example(`${! json("path") }`)).
+		Field(service.NewBoolField("fail_on_error").
+			Description("Whether messages of objects that could not be deleted should be rejected rather than only logged.").
+			Default(false)).
+		Field(service.NewIntField("max_in_flight").
+			Description("The maximum number of delete requests to run in parallel.").
+			Default(64)).
+		Field(service.NewBatchPolicyField("batching")).
+		Example("Prune Expired Objects",
+			"Here we consume a stream of documents describing objects that have expired, and delete them from a bucket in batches of up to 1000.",
+			`
+output:
+  minio_delete:
+    endpoint: localhost:9000
+    bucket: foo
+    secret_id: xxxxxxxxxxxxxx
+    secret_key: xxxxxxxxxxxxxx
+    key: ${! json("object_key") }
+    batching:
+      count: 1000
+      period: 1s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("minio_delete", minioDeleteOutputConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+		if batchPolicy, err = conf.FieldBatchPolicy("batching"); err != nil {
+			return
+		}
+		if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
+			return
+		}
+		out, err = newMinioDeleteOutputFromConfig(conf, mgr.Logger())
+		return
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type minioDeleteOutput struct {
+	endpoint    string
+	bucketName  string
+	secretID    string
+	secretKey   string
+	key         *service.InterpolatedString
+	failOnError bool
+
+	client *minio.Client
+	logger *service.Logger
+}
+
+func newMinioDeleteOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (m *minioDeleteOutput, err error) {
+	m = &minioDeleteOutput{logger: logger}
+	if m.endpoint, err = conf.FieldString("endpoint"); err != nil {
+		return nil, err
+	}
+	if m.bucketName, err = conf.FieldString("bucket"); err != nil {
+		return nil, err
+	}
+	if m.secretID, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if m.secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if m.key, err = conf.FieldInterpolatedString("key"); err != nil {
+		return nil, err
+	}
+	if m.failOnError, err = conf.FieldBool("fail_on_error"); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (m *minioDeleteOutput) Connect(ctx context.Context) error {
+	var err error
+	m.client, err = newMinioClient(m.endpoint, m.secretID, m.secretKey, "", minio.BucketLookupAuto)
+	return err
+}
+
+// batchKeys returns the distinct object keys of a batch, along with the
+// indexes of the messages that resolved to each key.
+func (m *minioDeleteOutput) batchKeys(batch service.MessageBatch) ([]string, map[string][]int) {
+	var keys []string
+	indexes := map[string][]int{}
+	for i, msg := range batch {
+		key := m.key.String(msg)
+		if _, exists := indexes[key]; !exists {
+			keys = append(keys, key)
+		}
+		indexes[key] = append(indexes[key], i)
+	}
+	return keys, indexes
+}
+
+// deleteErrors returns an error for a batch given the failed deletions of its
+// objects, or nil when the errors should only be logged.
+func (m *minioDeleteOutput) deleteErrors(batch service.MessageBatch, indexes map[string][]int, failed map[string]error) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	for key, err := range failed {
+		m.logger.Errorf("Failed to delete object %v from bucket %v: %v", key, m.bucketName, err)
+	}
+	if !m.failOnError {
+		return nil
+	}
+
+	bErr := service.NewBatchError(batch, fmt.Errorf("failed to delete %v objects from bucket %v", len(failed), m.bucketName))
+	for key, err := range failed {
+		for _, i := range indexes[key] {
+			bErr = bErr.Failed(i, err)
+		}
+	}
+	return bErr
+}
+
+func (m *minioDeleteOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	keys, indexes := m.batchKeys(batch)
+
+	objectsCh := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		objectsCh <- minio.ObjectInfo{Key: key}
+	}
+	close(objectsCh)
+
+	failed := map[string]error{}
+	for rErr := range m.client.RemoveObjects(ctx, m.bucketName, objectsCh, minio.RemoveObjectsOptions{}) {
+		failed[rErr.ObjectName] = rErr.Err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.deleteErrors(batch, indexes, failed)
+}
+
+func (m *minioDeleteOutput) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package minio
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testMinioDeleteOutput(t *testing.T, conf string) *minioDeleteOutput {
+	t.Helper()
+
+	pConf, err := minioDeleteOutputConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	m, err := newMinioDeleteOutputFromConfig(pConf, service.MockResources().Logger())
+	require.NoError(t, err)
+	return m
+}
+
+func TestMinioDeleteBatchKeys(t *testing.T) {
+	m := testMinioDeleteOutput(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+key: a/${! content() }
+`)
+
+	keys, indexes := m.batchKeys(service.MessageBatch{
+		service.NewMessage([]byte("x")),
+		service.NewMessage([]byte("y")),
+		service.NewMessage([]byte("x")),
+	})
+	assert.Equal(t, []string{"a/x", "a/y"}, keys)
+	assert.Equal(t, map[string][]int{
+		"a/x": {0, 2},
+		"a/y": {1},
+	}, indexes)
+}
+
+func TestMinioDeleteErrors(t *testing.T) {
+	conf := `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+key: ${! content() }
+`
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("x")),
+		service.NewMessage([]byte("y")),
+		service.NewMessage([]byte("x")),
+	}
+	failed := map[string]error{"x": errors.New("access denied")}
+
+	m := testMinioDeleteOutput(t, conf)
+	_, indexes := m.batchKeys(batch)
+	assert.NoError(t, m.deleteErrors(batch, indexes, nil))
+	assert.NoError(t, m.deleteErrors(batch, indexes, failed))
+
+	m = testMinioDeleteOutput(t, conf+"fail_on_error: true\n")
+	err := m.deleteErrors(batch, indexes, failed)
+	require.Error(t, err)
+
+	var bErr *service.BatchError
+	require.True(t, errors.As(err, &bErr))
+	assert.Equal(t, 2, bErr.IndexedErrors())
+
+	var failedIndexes []int
+	bErr.WalkMessages(func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			failedIndexes = append(failedIndexes, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{0, 2}, failedIndexes)
+}
@@ -0,0 +1,431 @@
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testMinioOutput(t *testing.T, conf string) *minioOutput {
+	t.Helper()
+
+	pConf, err := cosOutputConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	m, err := newMinioOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	return m
+}
+
+func TestMinioOutputPutOptionsTags(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+tags:
+  retention: ${! meta("retention") }
+  source: benthos
+`)
+
+	msg := service.NewMessage([]byte("hello"))
+	msg.MetaSet("retention", "short")
+
+	assert.Equal(t, map[string]string{
+		"retention": "short",
+		"source":    "benthos",
+	}, m.putOptions(msg).UserTags)
+}
+
+func TestMinioOutputPutOptionsNoTags(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+`)
+
+	assert.Nil(t, m.putOptions(service.NewMessage([]byte("hello"))).UserTags)
+}
+
+func TestMinioOutputPutOptionsCacheControl(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+cache_control: ${! meta("cache") }
+`)
+
+	msg := service.NewMessage([]byte("hello"))
+	msg.MetaSet("cache", "no-cache")
+	assert.Equal(t, "no-cache", m.putOptions(msg).CacheControl)
+}
+
+func TestMinioOutputPutOptionsFilename(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+filename_metadata: original_filename
+`)
+
+	assert.Nil(t, m.putOptions(service.NewMessage([]byte("hello"))).UserMetadata)
+
+	msg := service.NewMessage([]byte("hello"))
+	msg.MetaSet("original_filename", "my report.csv")
+	assert.Equal(t, map[string]string{
+		"original-filename": "my%20report.csv",
+	}, m.putOptions(msg).UserMetadata)
+}
+
+func TestMinioOutputPutOptionsSendContentMD5(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+`)
+	assert.False(t, m.putOptions(service.NewMessage([]byte("hello"))).SendContentMd5)
+
+	m = testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+send_content_md5: true
+`)
+	assert.True(t, m.putOptions(service.NewMessage([]byte("hello"))).SendContentMd5)
+}
+
+func TestMinioOutputInterpolatedEndpoint(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: ${! meta("endpoint").or("") }
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+`)
+
+	// Interpolated endpoints are not validated on connect.
+	require.NoError(t, m.Connect(context.Background()))
+
+	a, err := m.getClient("a.example.com:9000")
+	require.NoError(t, err)
+
+	b, err := m.getClient("b.example.com:9000")
+	require.NoError(t, err)
+	assert.NotSame(t, a, b)
+
+	a2, err := m.getClient("a.example.com:9000")
+	require.NoError(t, err)
+	assert.Same(t, a, a2)
+
+	_, err = m.getClient("")
+	require.EqualError(t, err, "endpoint interpolation resolved to an empty string")
+
+	_, err = m.getClient("a.example.com:9000/nope")
+	require.Error(t, err)
+
+	msg := service.NewMessage([]byte("hello"))
+	err = m.WriteBatch(context.Background(), service.MessageBatch{msg})
+	require.EqualError(t, err, "endpoint interpolation resolved to an empty string")
+}
+
+func TestMinioOutputForcePathStyle(t *testing.T) {
+	m := testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+`)
+	assert.False(t, m.forcePathStyle)
+	assert.Equal(t, minio.BucketLookupAuto, bucketLookup(m.forcePathStyle))
+
+	m = testMinioOutput(t, `
+endpoint: localhost:9000
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+force_path_style: true
+`)
+	assert.True(t, m.forcePathStyle)
+	assert.Equal(t, minio.BucketLookupPath, bucketLookup(m.forcePathStyle))
+}
+
+func TestMinioOutputCloseAbortsWrites(t *testing.T) {
+	started := make(chan struct{})
+	var startedOnce sync.Once
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedOnce.Do(func() { close(started) })
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		ts.Close()
+	})
+
+	m := testMinioOutput(t, `
+endpoint: `+strings.TrimPrefix(ts.URL, "http://")+`
+bucket_name: foo
+secret_id: id
+secret_key: key
+force_path_style: true
+directory: bar
+path: baz.txt
+`)
+	m.clients = map[string]*minio.Client{}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- m.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte("hello"))})
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for upload")
+	}
+
+	require.NoError(t, m.Close(context.Background()))
+
+	select {
+	case err := <-errChan:
+		require.Error(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("write was not aborted by close")
+	}
+}
+
+func TestMinioOutputVerifyOnConnect(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	endpoint := strings.TrimPrefix(ts.URL, "http://")
+	ts.Close()
+
+	m := testMinioOutput(t, `
+endpoint: `+endpoint+`
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+`)
+	assert.True(t, m.verifyOnConnect)
+	require.Error(t, m.Connect(context.Background()))
+
+	m = testMinioOutput(t, `
+endpoint: `+endpoint+`
+bucket_name: foo
+secret_id: id
+secret_key: key
+directory: bar
+path: baz.txt
+verify_on_connect: false
+`)
+	require.NoError(t, m.Connect(context.Background()))
+}
+
+func TestMinioOutputCreateBucket(t *testing.T) {
+	var mut sync.Mutex
+	var created bool
+	var creates, missingHeads int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		// Bucket requests are made with a trailing slash.
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Has("location"):
+			_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+		case r.Method == http.MethodHead && path == "/foo":
+			if !created || missingHeads > 0 {
+				missingHeads--
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.Method == http.MethodPut && path == "/foo":
+			creates++
+			if created {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`<Error><Code>BucketAlreadyOwnedByYou</Code></Error>`))
+				return
+			}
+			created = true
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	conf := `
+endpoint: ` + strings.TrimPrefix(ts.URL, "http://") + `
+bucket_name: foo
+secret_id: id
+secret_key: key
+force_path_style: true
+directory: bar
+path: baz.txt
+create_bucket: true
+`
+
+	m := testMinioOutput(t, conf)
+	require.NoError(t, m.Connect(context.Background()))
+	require.NoError(t, m.Connect(context.Background()))
+
+	mut.Lock()
+	assert.True(t, created)
+	assert.Equal(t, 1, creates)
+
+	// Mimic another instance creating the bucket between our check and our
+	// attempt to create it.
+	missingHeads = 1
+	mut.Unlock()
+
+	m = testMinioOutput(t, conf)
+	require.NoError(t, m.Connect(context.Background()))
+
+	mut.Lock()
+	assert.Equal(t, 2, creates)
+	mut.Unlock()
+}
+
+// testConditionalServer returns a server storing uploaded objects, which can
+// be made to report every object as missing when checked for existence, and to
+// ignore the conditional header If-None-Match on uploads.
+func testConditionalServer(t *testing.T, statMissing, ignoreCondition bool) (url string, objects map[string]string, requests func() []string) {
+	t.Helper()
+
+	var mut sync.Mutex
+	objects = map[string]string{}
+	var reqs []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Has("location"):
+			_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+		case r.Method == http.MethodHead:
+			reqs = append(reqs, "HEAD")
+			if _, exists := objects[r.URL.Path]; !exists || statMissing {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("ETag", `"foo"`)
+		case r.Method == http.MethodPut:
+			cond := r.Header.Get("If-None-Match")
+			reqs = append(reqs, strings.TrimSpace("PUT "+cond))
+
+			body, _ := io.ReadAll(r.Body)
+			if _, exists := objects[r.URL.Path]; exists && cond == "*" && !ignoreCondition {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`<Error><Code>PreconditionFailed</Code></Error>`))
+				return
+			}
+			objects[r.URL.Path] = string(body)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return strings.TrimPrefix(ts.URL, "http://"), objects, func() []string {
+		mut.Lock()
+		defer mut.Unlock()
+		return append([]string(nil), reqs...)
+	}
+}
+
+func testIfNotExistsWrite(t *testing.T, url, extraConf, body string) error {
+	t.Helper()
+
+	m := testMinioOutput(t, `
+endpoint: `+url+`
+bucket_name: foo
+secret_id: id
+secret_key: key
+force_path_style: true
+verify_on_connect: false
+directory: bar
+path: baz.txt
+`+extraConf)
+	require.NoError(t, m.Connect(context.Background()))
+	return m.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte(body))})
+}
+
+func TestMinioOutputIfNotExists(t *testing.T) {
+	url, objects, requests := testConditionalServer(t, false, false)
+
+	require.NoError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\n", "first"))
+	require.NoError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\n", "second"))
+	require.EqualError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\nerror_if_exists: true\n", "third"), "object bar/baz.txt already exists")
+	require.NoError(t, testIfNotExistsWrite(t, url, "", "fourth"))
+
+	assert.Equal(t, []string{"HEAD", "PUT *", "HEAD", "HEAD", "PUT"}, requests())
+	require.Len(t, objects, 1)
+	// Bodies are streamed with signed chunks.
+	assert.Contains(t, objects["/foo/bar/baz.txt"], "fourth")
+}
+
+func TestMinioOutputIfNotExistsCreatedAfterCheck(t *testing.T) {
+	// Objects created between the existence check and the upload are caught
+	// by the conditional header.
+	url, objects, requests := testConditionalServer(t, true, false)
+
+	require.NoError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\n", "first"))
+	require.NoError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\n", "second"))
+	require.EqualError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\nerror_if_exists: true\n", "third"), "object bar/baz.txt already exists")
+
+	assert.Equal(t, []string{"HEAD", "PUT *", "HEAD", "PUT *", "HEAD", "PUT *"}, requests())
+	assert.Contains(t, objects["/foo/bar/baz.txt"], "first")
+}
+
+func TestMinioOutputIfNotExistsConditionIgnored(t *testing.T) {
+	// Servers that ignore the conditional header still do not have existing
+	// objects overwritten.
+	url, objects, requests := testConditionalServer(t, false, true)
+
+	require.NoError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\n", "first"))
+	require.NoError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\n", "second"))
+	require.EqualError(t, testIfNotExistsWrite(t, url, "if_not_exists: true\nerror_if_exists: true\n", "third"), "object bar/baz.txt already exists")
+
+	assert.Equal(t, []string{"HEAD", "PUT *", "HEAD", "HEAD"}, requests())
+	assert.Contains(t, objects["/foo/bar/baz.txt"], "first")
+}
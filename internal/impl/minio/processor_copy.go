@@ -0,0 +1,210 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func minioCopyProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Copies an object within or between minio buckets using a server-side copy, without downloading the object.").
+		Description(`
+The source and destination of the copy are resolved from each message. The first time a bucket is encountered its existence is checked, and messages referencing buckets that do not exist or are unreachable fail with an error.
+
+### Metadata
+
+This processor adds the following metadata fields to each message once the object has been copied:
+
+`+"```"+`
+- minio_copy_bucket
+- minio_copy_key
+- minio_copy_etag
+- minio_copy_size
+- minio_copy_version_id
+`+"```"+`
+
+When the field `+"`overwrite_message`"+` is enabled the contents of each message are also replaced with a JSON document describing the copied object, containing the fields `+"`bucket`, `key`, `etag`, `size` and `version_id`"+`.`).
+		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to the buckets.")).
+		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
+		Field(service.NewStringField("secret_key").Description("User's Secret key.").Secret()).
+		Field(service.NewInterpolatedStringField("source_bucket").Description("The bucket containing the object to copy.")).
+		Field(service.NewInterpolatedStringField("source_key").
+			Description("The key of the object to copy.").
+			Example(`${! meta("minio_key") }`)).
+		Field(service.NewInterpolatedStringField("destination_bucket").Description("The bucket to copy the object to.")).
+		Field(service.NewInterpolatedStringField("destination_key").
+			Description("The key to copy the object to.").
+			Example(`processed/${! meta("minio_key") }`)).
+		Field(service.NewBoolField("overwrite_message").
+			Description("Whether to replace the contents of each message with a JSON document describing the copied object.").
+			Default(false)).
+		Example("Move Processed Objects",
+			"Here we copy each object that has been processed to an archive bucket, and then delete the original.",
+			`
+pipeline:
+  processors:
+    - minio_copy:
+        endpoint: localhost:9000
+        secret_id: xxxxxxxxxxxxxx
+        secret_key: xxxxxxxxxxxxxx
+        source_bucket: incoming
+        source_key: ${! meta("key") }
+        destination_bucket: archive
+        destination_key: ${! now().ts_format("2006/01/02") }/${! meta("key") }
+
+output:
+  minio_delete:
+    endpoint: localhost:9000
+    bucket: incoming
+    secret_id: xxxxxxxxxxxxxx
+    secret_key: xxxxxxxxxxxxxx
+    key: ${! meta("key") }
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("minio_copy", minioCopyProcessorConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+		return newMinioCopyProcessorFromConfig(conf)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type minioCopyProcessor struct {
+	sourceBucket      *service.InterpolatedString
+	sourceKey         *service.InterpolatedString
+	destinationBucket *service.InterpolatedString
+	destinationKey    *service.InterpolatedString
+	overwriteMessage  bool
+
+	client *minio.Client
+
+	// Buckets that are known to exist.
+	checkedBuckets sync.Map
+}
+
+func newMinioCopyProcessorFromConfig(conf *service.ParsedConfig) (p *minioCopyProcessor, err error) {
+	p = &minioCopyProcessor{}
+
+	var endpoint, secretID, secretKey string
+	if endpoint, err = conf.FieldString("endpoint"); err != nil {
+		return nil, err
+	}
+	if secretID, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if p.sourceBucket, err = conf.FieldInterpolatedString("source_bucket"); err != nil {
+		return nil, err
+	}
+	if p.sourceKey, err = conf.FieldInterpolatedString("source_key"); err != nil {
+		return nil, err
+	}
+	if p.destinationBucket, err = conf.FieldInterpolatedString("destination_bucket"); err != nil {
+		return nil, err
+	}
+	if p.destinationKey, err = conf.FieldInterpolatedString("destination_key"); err != nil {
+		return nil, err
+	}
+	if p.overwriteMessage, err = conf.FieldBool("overwrite_message"); err != nil {
+		return nil, err
+	}
+
+	if p.client, err = newMinioClient(endpoint, secretID, secretKey, "", minio.BucketLookupAuto); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// checkBucket returns an error if a bucket does not exist or is unreachable.
+// Buckets that exist are only checked once.
+func (p *minioCopyProcessor) checkBucket(ctx context.Context, bucket string) error {
+	if _, exists := p.checkedBuckets.Load(bucket); exists {
+		return nil
+	}
+	exists, err := p.client.BucketExists(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("failed to reach bucket %v: %w", bucket, err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %v does not exist", bucket)
+	}
+	p.checkedBuckets.Store(bucket, struct{}{})
+	return nil
+}
+
+// copyOptions resolves the source and destination of a copy from a message.
+func (p *minioCopyProcessor) copyOptions(msg *service.Message) (minio.CopySrcOptions, minio.CopyDestOptions, error) {
+	src := minio.CopySrcOptions{
+		Bucket: p.sourceBucket.String(msg),
+		Object: p.sourceKey.String(msg),
+	}
+	dst := minio.CopyDestOptions{
+		Bucket: p.destinationBucket.String(msg),
+		Object: p.destinationKey.String(msg),
+	}
+	if src.Bucket == "" || src.Object == "" {
+		return src, dst, errors.New("source bucket and key interpolations must not resolve to empty strings")
+	}
+	if dst.Bucket == "" || dst.Object == "" {
+		return src, dst, errors.New("destination bucket and key interpolations must not resolve to empty strings")
+	}
+	return src, dst, nil
+}
+
+// setResult adds the result of a copy to a message.
+func (p *minioCopyProcessor) setResult(msg *service.Message, info minio.UploadInfo) {
+	msg.MetaSetMut("minio_copy_bucket", info.Bucket)
+	msg.MetaSetMut("minio_copy_key", info.Key)
+	msg.MetaSetMut("minio_copy_etag", info.ETag)
+	msg.MetaSetMut("minio_copy_size", strconv.FormatInt(info.Size, 10))
+	msg.MetaSetMut("minio_copy_version_id", info.VersionID)
+
+	if p.overwriteMessage {
+		msg.SetStructuredMut(map[string]any{
+			"bucket":     info.Bucket,
+			"key":        info.Key,
+			"etag":       info.ETag,
+			"size":       info.Size,
+			"version_id": info.VersionID,
+		})
+	}
+}
+
+func (p *minioCopyProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	src, dst, err := p.copyOptions(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkBucket(ctx, src.Bucket); err != nil {
+		return nil, err
+	}
+	if err := p.checkBucket(ctx, dst.Bucket); err != nil {
+		return nil, err
+	}
+
+	info, err := p.client.CopyObject(ctx, dst, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy object %v/%v to %v/%v: %w", src.Bucket, src.Object, dst.Bucket, dst.Object, err)
+	}
+
+	p.setResult(msg, info)
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *minioCopyProcessor) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package minio
+
+import (
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testCopyProcessor(t *testing.T, conf string) *minioCopyProcessor {
+	t.Helper()
+
+	pConf, err := minioCopyProcessorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newMinioCopyProcessorFromConfig(pConf)
+	require.NoError(t, err)
+	return p
+}
+
+func TestMinioCopyOptions(t *testing.T) {
+	p := testCopyProcessor(t, `
+endpoint: localhost:9000
+secret_id: id
+secret_key: key
+source_bucket: incoming
+source_key: ${! meta("key").or("") }
+destination_bucket: ${! meta("dest").or("") }
+destination_key: archive/${! meta("key") }
+`)
+
+	msg := service.NewMessage(nil)
+	msg.MetaSet("key", "foo.txt")
+	msg.MetaSet("dest", "bar")
+
+	src, dst, err := p.copyOptions(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "incoming", src.Bucket)
+	assert.Equal(t, "foo.txt", src.Object)
+	assert.Equal(t, "bar", dst.Bucket)
+	assert.Equal(t, "archive/foo.txt", dst.Object)
+
+	_, _, err = p.copyOptions(service.NewMessage(nil))
+	require.Error(t, err)
+}
+
+func TestMinioCopyResult(t *testing.T) {
+	conf := `
+endpoint: localhost:9000
+secret_id: id
+secret_key: key
+source_bucket: foo
+source_key: a.txt
+destination_bucket: bar
+destination_key: b.txt
+`
+	info := minio.UploadInfo{
+		Bucket: "bar",
+		Key:    "b.txt",
+		ETag:   "abc123",
+		Size:   42,
+	}
+
+	p := testCopyProcessor(t, conf)
+	msg := service.NewMessage([]byte("hello"))
+	p.setResult(msg, info)
+
+	body, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+
+	for k, v := range map[string]string{
+		"minio_copy_bucket": "bar",
+		"minio_copy_key":    "b.txt",
+		"minio_copy_etag":   "abc123",
+		"minio_copy_size":   "42",
+	} {
+		act, exists := msg.MetaGet(k)
+		assert.True(t, exists, k)
+		assert.Equal(t, v, act, k)
+	}
+
+	p = testCopyProcessor(t, conf+"overwrite_message: true\n")
+	msg = service.NewMessage([]byte("hello"))
+	p.setResult(msg, info)
+
+	body, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bucket":"bar","key":"b.txt","etag":"abc123","size":42,"version_id":""}`, string(body))
+}
@@ -0,0 +1,169 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/minio/minio-go/v7"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// minioMaxPresignExpiry is the longest expiry period accepted by minio for
+// presigned URLs.
+const minioMaxPresignExpiry = 7 * 24 * time.Hour
+
+func minioPresignProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Generates a time-limited presigned URL for downloading an object from a minio bucket, and adds it to each message.").
+		Description("The URL is stored within the metadata field `metadata_key` unless the field `result_path` is set, in which case the message is parsed as a structured document and the URL is set at the provided path. Generating a URL does not require a request to be made to the bucket, and therefore the existence of the object is not checked.").
+		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to bucket.")).
+		Field(service.NewStringField("bucket").Description("The name of the bucket containing the objects.")).
+		Field(service.NewStringField("region").
+			Description("The region of the bucket, which is used to sign URLs without querying the location of the bucket.").
+			Advanced().
+			Default("us-east-1")).
+		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
+		Field(service.NewStringField("secret_key").Description("User's Secret key.").Secret()).
+		Field(service.NewInterpolatedStringField("key").
+			Description("The key of the object to generate a URL for.").
+			Example(`${! meta("path") }`)).
+		Field(service.NewDurationField("expiry").
+			Description("The period after which the generated URL expires, which must not exceed seven days.").
+			Default("1h")).
+		Field(service.NewStringField("metadata_key").
+			Description("The metadata field to store the URL within.").
+			Default("minio_presigned_url")).
+		Field(service.NewStringField("result_path").
+			Description("An optional dot separated path at which to set the URL within the structured contents of the message, instead of storing it as metadata.").
+			Example("doc.download_url").
+			Optional()).
+		Example("Presign Uploaded Objects",
+			"Here we upload messages to a bucket and then generate a download link for each object that is valid for a day.",
+			`
+output:
+  broker:
+    pattern: fan_out_sequential
+    outputs:
+      - minio:
+          endpoint: localhost:9000
+          bucket_name: foo
+          secret_id: xxxxxxxxxxxxxx
+          secret_key: xxxxxxxxxxxxxx
+          directory: uploads
+          path: ${! meta("id") }.json
+      - processors:
+          - minio_presign:
+              endpoint: localhost:9000
+              bucket: foo
+              secret_id: xxxxxxxxxxxxxx
+              secret_key: xxxxxxxxxxxxxx
+              key: uploads/${! meta("id") }.json
+              expiry: 24h
+          - mapping: 'root.url = @minio_presigned_url'
+        http_client:
+          url: http://example.com/links
+          verb: POST
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("minio_presign", minioPresignProcessorConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+		return newMinioPresignProcessorFromConfig(conf)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type minioPresignProcessor struct {
+	bucketName  string
+	key         *service.InterpolatedString
+	expiry      time.Duration
+	metadataKey string
+	resultPath  string
+
+	client *minio.Client
+}
+
+func newMinioPresignProcessorFromConfig(conf *service.ParsedConfig) (p *minioPresignProcessor, err error) {
+	p = &minioPresignProcessor{}
+
+	var endpoint, region, secretID, secretKey string
+	if endpoint, err = conf.FieldString("endpoint"); err != nil {
+		return nil, err
+	}
+	if p.bucketName, err = conf.FieldString("bucket"); err != nil {
+		return nil, err
+	}
+	if region, err = conf.FieldString("region"); err != nil {
+		return nil, err
+	}
+	if secretID, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if p.key, err = conf.FieldInterpolatedString("key"); err != nil {
+		return nil, err
+	}
+	if p.expiry, err = conf.FieldDuration("expiry"); err != nil {
+		return nil, err
+	}
+	if p.expiry <= 0 || p.expiry > minioMaxPresignExpiry {
+		return nil, fmt.Errorf("expiry must be greater than zero and must not exceed %v, got %v", minioMaxPresignExpiry, p.expiry)
+	}
+	if p.metadataKey, err = conf.FieldString("metadata_key"); err != nil {
+		return nil, err
+	}
+	if conf.Contains("result_path") {
+		if p.resultPath, err = conf.FieldString("result_path"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.client, err = newMinioClient(endpoint, secretID, secretKey, region, minio.BucketLookupAuto); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *minioPresignProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	key := p.key.String(msg)
+	if key == "" {
+		return nil, errors.New("key interpolation resolved to an empty string")
+	}
+
+	u, err := p.client.PresignedGetObject(ctx, p.bucketName, key, p.expiry, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.resultPath == "" {
+		msg.MetaSetMut(p.metadataKey, u.String())
+		return service.MessageBatch{msg}, nil
+	}
+
+	v, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as structured: %w", err)
+	}
+	gObj := gabs.Wrap(v)
+	if _, err = gObj.SetP(u.String(), p.resultPath); err != nil {
+		return nil, err
+	}
+	msg.SetStructuredMut(gObj.Data())
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *minioPresignProcessor) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package minio
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testPresignProcessor(t *testing.T, conf string) (*minioPresignProcessor, error) {
+	t.Helper()
+
+	pConf, err := minioPresignProcessorConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	return newMinioPresignProcessorFromConfig(pConf)
+}
+
+func TestMinioPresignMetadata(t *testing.T) {
+	p, err := testPresignProcessor(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+key: bar/${! content() }.txt
+expiry: 2h
+`)
+	require.NoError(t, err)
+
+	batch, err := p.Process(context.Background(), service.NewMessage([]byte("baz")))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	urlStr, exists := batch[0].MetaGet("minio_presigned_url")
+	require.True(t, exists)
+
+	u, err := url.Parse(urlStr)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:9000", u.Host)
+	assert.Equal(t, "/foo/bar/baz.txt", u.Path)
+	assert.Equal(t, "7200", u.Query().Get("X-Amz-Expires"))
+}
+
+func TestMinioPresignResultPath(t *testing.T) {
+	p, err := testPresignProcessor(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+key: ${! json("key") }
+result_path: links.download
+`)
+	require.NoError(t, err)
+
+	batch, err := p.Process(context.Background(), service.NewMessage([]byte(`{"key":"bar.txt"}`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+
+	urlStr, _ := v.(map[string]any)["links"].(map[string]any)["download"].(string)
+	u, err := url.Parse(urlStr)
+	require.NoError(t, err)
+	assert.Equal(t, "/foo/bar.txt", u.Path)
+
+	_, exists := batch[0].MetaGet("minio_presigned_url")
+	assert.False(t, exists)
+}
+
+func TestMinioPresignExpiryLimit(t *testing.T) {
+	_, err := testPresignProcessor(t, `
+endpoint: localhost:9000
+bucket: foo
+secret_id: id
+secret_key: key
+key: bar.txt
+expiry: 169h
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not exceed")
+}
@@ -0,0 +1,242 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/minio/minio-go/v7"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func minioStatProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Fetches the metadata of an object within a minio bucket, such as its size and content type, and adds it to each message without downloading the object.").
+		Description(`
+The bucket and key of the object are resolved from each message. Messages referencing objects that do not exist, or that could not be reached, fail with an error that can be handled using the methods outlined [here](/docs/configuration/error_handling), and the remaining messages of the batch are processed as normal.
+
+### Metadata
+
+Unless the field `+"`result_path`"+` is set, this processor adds the following metadata fields to each message, where each name is prefixed with the field `+"`metadata_prefix`"+`:
+
+`+"```"+`
+- size
+- content_type
+- last_modified
+- etag
+- version_id
+- meta_<name>
+- tag_<name>
+`+"```"+`
+
+The field `+"`last_modified`"+` is formatted as an RFC 3339 timestamp, the `+"`meta_`"+` fields contain the user metadata of the object, and the `+"`tag_`"+` fields are only added when `+"`include_tags`"+` is enabled.
+
+When `+"`result_path`"+` is set the message is instead parsed as a structured document and an object is set at the provided path, containing the fields `+"`bucket`, `key`, `size`, `content_type`, `last_modified`, `etag`, `version_id`, `user_metadata` and, when `include_tags` is enabled, `tags`"+`.`).
+		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to the buckets.")).
+		Field(service.NewStringField("region").
+			Description("The region of the buckets, which is resolved from the location of each bucket when empty.").
+			Advanced().
+			Default("")).
+		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
+		Field(service.NewStringField("secret_key").Description("User's Secret key.").Secret()).
+		Field(service.NewInterpolatedStringField("bucket").Description("The bucket containing the object.")).
+		Field(service.NewInterpolatedStringField("key").
+			Description("The key of the object.").
+			Example(`${! meta("minio_key") }`)).
+		Field(service.NewBoolField("include_tags").
+			Description("Whether to also fetch the tags of each object, which requires an additional request per message.").
+			Default(false)).
+		Field(service.NewStringField("metadata_prefix").
+			Description("The prefix of the metadata fields that the result is added as.").
+			Default("minio_stat_")).
+		Field(service.NewStringField("result_path").
+			Description("An optional dot separated path at which to set the result within the structured contents of the message, instead of adding it as metadata.").
+			Example("object").
+			Optional()).
+		Example("Route Large Objects",
+			"Here we consume the keys of uploaded objects from a queue, and route objects larger than a megabyte to a separate topic without downloading them.",
+			`
+pipeline:
+  processors:
+    - minio_stat:
+        endpoint: localhost:9000
+        secret_id: xxxxxxxxxxxxxx
+        secret_key: xxxxxxxxxxxxxx
+        bucket: uploads
+        key: ${! json("key") }
+
+output:
+  switch:
+    cases:
+      - check: '@minio_stat_size.number() > 1000000'
+        output:
+          kafka:
+            addresses: [ localhost:9092 ]
+            topic: large_uploads
+      - output:
+          kafka:
+            addresses: [ localhost:9092 ]
+            topic: uploads
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("minio_stat", minioStatProcessorConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+		return newMinioStatProcessorFromConfig(conf)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type minioStatProcessor struct {
+	bucket         *service.InterpolatedString
+	key            *service.InterpolatedString
+	includeTags    bool
+	metadataPrefix string
+	resultPath     string
+
+	client *minio.Client
+}
+
+func newMinioStatProcessorFromConfig(conf *service.ParsedConfig) (p *minioStatProcessor, err error) {
+	p = &minioStatProcessor{}
+
+	var endpoint, region, secretID, secretKey string
+	if endpoint, err = conf.FieldString("endpoint"); err != nil {
+		return nil, err
+	}
+	if region, err = conf.FieldString("region"); err != nil {
+		return nil, err
+	}
+	if secretID, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if p.bucket, err = conf.FieldInterpolatedString("bucket"); err != nil {
+		return nil, err
+	}
+	if p.key, err = conf.FieldInterpolatedString("key"); err != nil {
+		return nil, err
+	}
+	if p.includeTags, err = conf.FieldBool("include_tags"); err != nil {
+		return nil, err
+	}
+	if p.metadataPrefix, err = conf.FieldString("metadata_prefix"); err != nil {
+		return nil, err
+	}
+	if conf.Contains("result_path") {
+		if p.resultPath, err = conf.FieldString("result_path"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.client, err = newMinioClient(endpoint, secretID, secretKey, region, minio.BucketLookupAuto); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// stat returns the metadata of an object, and its tags when enabled.
+func (p *minioStatProcessor) stat(ctx context.Context, bucket, key string) (info minio.ObjectInfo, tags map[string]string, err error) {
+	if info, err = p.client.StatObject(ctx, bucket, key, minio.StatObjectOptions{}); err != nil {
+		if isNoSuchKey(err) {
+			err = fmt.Errorf("object %v/%v does not exist", bucket, key)
+		} else {
+			err = fmt.Errorf("failed to stat object %v/%v: %w", bucket, key, err)
+		}
+		return
+	}
+	if !p.includeTags {
+		return
+	}
+	t, err := p.client.GetObjectTagging(ctx, bucket, key, minio.GetObjectTaggingOptions{})
+	if err != nil {
+		err = fmt.Errorf("failed to get tags of object %v/%v: %w", bucket, key, err)
+		return
+	}
+	tags = t.ToMap()
+	return
+}
+
+// setMetadata adds the metadata of an object to a message as metadata fields.
+func (p *minioStatProcessor) setMetadata(msg *service.Message, info minio.ObjectInfo, tags map[string]string) {
+	msg.MetaSetMut(p.metadataPrefix+"size", strconv.FormatInt(info.Size, 10))
+	msg.MetaSetMut(p.metadataPrefix+"content_type", info.ContentType)
+	msg.MetaSetMut(p.metadataPrefix+"last_modified", info.LastModified.Format(time.RFC3339))
+	msg.MetaSetMut(p.metadataPrefix+"etag", info.ETag)
+	msg.MetaSetMut(p.metadataPrefix+"version_id", info.VersionID)
+	for k, v := range info.UserMetadata {
+		msg.MetaSetMut(p.metadataPrefix+"meta_"+k, v)
+	}
+	for k, v := range tags {
+		msg.MetaSetMut(p.metadataPrefix+"tag_"+k, v)
+	}
+}
+
+// result returns the metadata of an object as a structured document.
+func (p *minioStatProcessor) result(bucket, key string, info minio.ObjectInfo, tags map[string]string) map[string]any {
+	userMeta := make(map[string]any, len(info.UserMetadata))
+	for k, v := range info.UserMetadata {
+		userMeta[k] = v
+	}
+	res := map[string]any{
+		"bucket":        bucket,
+		"key":           key,
+		"size":          info.Size,
+		"content_type":  info.ContentType,
+		"last_modified": info.LastModified.Format(time.RFC3339),
+		"etag":          info.ETag,
+		"version_id":    info.VersionID,
+		"user_metadata": userMeta,
+	}
+	if p.includeTags {
+		tagsObj := make(map[string]any, len(tags))
+		for k, v := range tags {
+			tagsObj[k] = v
+		}
+		res["tags"] = tagsObj
+	}
+	return res
+}
+
+func (p *minioStatProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	bucket, key := p.bucket.String(msg), p.key.String(msg)
+	if bucket == "" || key == "" {
+		return nil, errors.New("bucket and key interpolations must not resolve to empty strings")
+	}
+
+	info, tags, err := p.stat(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.resultPath == "" {
+		p.setMetadata(msg, info, tags)
+		return service.MessageBatch{msg}, nil
+	}
+
+	v, err := msg.AsStructuredMut()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message as structured: %w", err)
+	}
+	gObj := gabs.Wrap(v)
+	if _, err = gObj.SetP(p.result(bucket, key, info, tags), p.resultPath); err != nil {
+		return nil, err
+	}
+	msg.SetStructuredMut(gObj.Data())
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *minioStatProcessor) Close(ctx context.Context) error {
+	return nil
+}
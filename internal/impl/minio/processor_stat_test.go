@@ -0,0 +1,125 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testStatProcessor(t *testing.T, conf string) *minioStatProcessor {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/foo/bar.txt":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && r.URL.Query().Has("tagging"):
+			_, _ = w.Write([]byte(`<Tagging><TagSet><Tag><Key>retention</Key><Value>short</Value></Tag></TagSet></Tagging>`))
+		case r.Method == http.MethodHead:
+			w.Header().Set("Content-Length", "11")
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2026 07:28:00 GMT")
+			w.Header().Set("ETag", `"abc123"`)
+			w.Header().Set("X-Amz-Meta-Source", "upload")
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	pConf, err := minioStatProcessorConfig().ParseYAML(`
+endpoint: `+strings.TrimPrefix(ts.URL, "http://")+`
+region: us-east-1
+secret_id: id
+secret_key: key
+bucket: foo
+key: ${! meta("key").or("") }
+`+conf, nil)
+	require.NoError(t, err)
+
+	p, err := newMinioStatProcessorFromConfig(pConf)
+	require.NoError(t, err)
+	return p
+}
+
+func TestMinioStatMetadata(t *testing.T) {
+	p := testStatProcessor(t, `
+include_tags: true
+`)
+
+	msg := service.NewMessage(nil)
+	msg.MetaSet("key", "bar.txt")
+
+	batch, err := p.Process(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	meta := map[string]any{}
+	_ = batch[0].MetaWalkMut(func(k string, v any) error {
+		if k == "key" {
+			return nil
+		}
+		meta[k] = v
+		return nil
+	})
+	assert.Equal(t, map[string]any{
+		"minio_stat_size":          "11",
+		"minio_stat_content_type":  "text/plain",
+		"minio_stat_last_modified": "2026-10-21T07:28:00Z",
+		"minio_stat_etag":          "abc123",
+		"minio_stat_version_id":    "",
+		"minio_stat_meta_Source":   "upload",
+		"minio_stat_tag_retention": "short",
+	}, meta)
+}
+
+func TestMinioStatResultPath(t *testing.T) {
+	p := testStatProcessor(t, `
+result_path: object
+`)
+
+	msg := service.NewMessage([]byte(`{"id":"a"}`))
+	msg.MetaSet("key", "bar.txt")
+
+	batch, err := p.Process(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id": "a",
+		"object": map[string]any{
+			"bucket":        "foo",
+			"key":           "bar.txt",
+			"size":          int64(11),
+			"content_type":  "text/plain",
+			"last_modified": "2026-10-21T07:28:00Z",
+			"etag":          "abc123",
+			"version_id":    "",
+			"user_metadata": map[string]any{
+				"Source": "upload",
+			},
+		},
+	}, v)
+}
+
+func TestMinioStatMissingObject(t *testing.T) {
+	p := testStatProcessor(t, "")
+
+	msg := service.NewMessage(nil)
+	msg.MetaSet("key", "nope.txt")
+
+	_, err := p.Process(context.Background(), msg)
+	require.EqualError(t, err, "object foo/nope.txt does not exist")
+
+	_, err = p.Process(context.Background(), service.NewMessage(nil))
+	require.EqualError(t, err, "bucket and key interpolations must not resolve to empty strings")
+}
@@ -49,7 +49,7 @@ messages these interpolations are performed per message part.`),
 			docs.FieldString("dynamic_client_id_suffix", "Append a dynamically generated suffix to the specified `client_id` on each run of the pipeline. This can be useful when clustering Benthos producers.").Optional().Advanced().HasAnnotatedOptions(
 				"nanoid", "append a nanoid of length 21 characters",
 			).LinterFunc(nil),
-			docs.FieldInt("qos", "The QoS value to set for each message.").HasOptions("0", "1", "2").LinterFunc(nil),
+			docs.FieldInt("qos", "The QoS value to set for each message.").HasOptions("0", "1", "2"),
 			docs.FieldString("connect_timeout", "The maximum amount of time to wait in order to establish a connection before the attempt is abandoned.", "1s", "500ms").HasDefault("30s").AtVersion("3.58.0"),
 			docs.FieldString("write_timeout", "The maximum amount of time to wait to write data before the attempt is abandoned.", "1s", "500ms").HasDefault("3s").AtVersion("3.58.0"),
 			docs.FieldBool("retained", "Set message as retained on the topic."),
@@ -3,17 +3,24 @@ package nsq
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
 	llog "log"
+	"math"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/nsqio/go-nsq"
 
+	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/input/processors"
+	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -24,14 +31,56 @@ func init() {
 	err := bundle.AllInputs.Add(processors.WrapConstructor(newNSQInput), docs.ComponentSpec{
 		Name:    "nsq",
 		Summary: `Subscribe to an NSQ instance topic and channel.`,
+		Description: `
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "``` text" + `
+- nsq_topic
+` + "```" + `
+
+You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).
+
+### Multiple Topics
+
+Multiple topics can be consumed by listing them within the field ` + "`topics`" + `, in which case a consumer is created for each topic and all of them share the same channel. The field ` + "`nsq_topic`" + ` can be used to distinguish the topic a message was consumed from.
+
+### Dead Letter Output
+
+When the field ` + "`dead_letter`" + ` is set, messages that exceed ` + "`max_attempts`" + ` are written to the child output before they are finished rather than being dropped. These messages contain the metadata field ` + "`nsq_topic`" + ` along with ` + "`nsq_attempts`" + `, which is the number of times the message was delivered. The message is requeued rather than finished if the input is shut down before the write is acknowledged.
+
+### Batching
+
+Use the ` + "`batching`" + ` fields to configure an optional [batching policy](/docs/configuration/batching#batch-policy). Messages are accumulated until the policy triggers a flush, and each message of a batch is finished or requeued individually once the batch is acknowledged.`,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("nsqd_tcp_addresses", "A list of nsqd addresses to connect to.").Array(),
 			docs.FieldString("lookupd_http_addresses", "A list of nsqlookupd addresses to connect to.").Array(),
+			docs.FieldString("mode", "Determines which of the address lists are used to discover and connect to nsqd instances. The addresses of the modes `direct` and `lookupd` must not be empty.").HasAnnotatedOptions(
+				"both", "Connect to the nsqd addresses and also to the producers discovered via the nsqlookupd addresses, skipping either list when it is empty.",
+				"direct", "Connect only to the nsqd addresses, which is useful in environments where nsqlookupd is unreliable.",
+				"lookupd", "Connect only to the producers discovered via the nsqlookupd addresses.",
+			).Advanced(),
 			btls.FieldSpec(),
-			docs.FieldString("topic", "The topic to consume from."),
+			docs.FieldString("topic", "The topic to consume from. May be left empty when topics are listed in the field `topics`."),
+			docs.FieldString("topics", "A list of topics to consume from in addition to `topic`, where each topic is consumed with the same channel.").Array().Advanced(),
 			docs.FieldString("channel", "The channel to consume from."),
 			docs.FieldString("user_agent", "A user agent to assume when connecting."),
+			docs.FieldString("auth_secret", "An optional secret to authenticate with when connecting to nsqd instances that have authentication enabled.").Secret().Advanced(),
 			docs.FieldInt("max_in_flight", "The maximum number of pending messages to consume at any given time."),
+			docs.FieldInt("max_attempts", "The maximum number of times a message is delivered before it is automatically finished, where `0` allows unlimited attempts.").LinterNumericRange(0, math.MaxUint16).Advanced(),
+			docs.FieldDuration("dial_timeout", "The maximum period to wait when establishing a connection to nsqd.").Advanced(),
+			docs.FieldDuration("read_timeout", "The deadline for network reads.").Advanced(),
+			docs.FieldDuration("write_timeout", "The deadline for network writes.").Advanced(),
+			docs.FieldDuration("lookupd_poll_interval", "The period between each poll of the nsqlookupd addresses for new producers of the topic. When no lookupd addresses are configured this is the period between reconnection attempts to nsqd.").Advanced(),
+			docs.FieldInt("sample_rate", "A percentage of messages to receive from the channel, between `0` and `99` where `0` disables sampling and all messages are received.").LinterNumericRange(0, 99).Advanced(),
+			docs.FieldDuration("drain_timeout", "The maximum period to wait during shutdown for consumed messages to be acknowledged before they are requeued.").Advanced(),
+			docs.FieldObject("connection_backoff", "Determines how long to wait between failed attempts to connect to the nsqd and nsqlookupd addresses, which increases exponentially with each consecutive failure. The same backoff is applied after each failed attempt to read messages, including reads that time out while no messages are available, and is reset once messages are read.").WithChildren(
+				docs.FieldDuration("initial_interval", "The period to wait after the first failed connection or read attempt."),
+				docs.FieldDuration("max_interval", "The maximum period to wait between connection or read attempts."),
+			).Advanced(),
+			policy.FieldSpec(),
+			docs.FieldOutput("dead_letter", "An optional output that messages exceeding `max_attempts` are written to before they are finished.").Optional().Advanced(),
 		).ChildDefaultAndTypesFromStruct(input.NewNSQConfig()),
 		Categories: []string{
 			"Services",
@@ -42,37 +91,121 @@ func init() {
 	}
 }
 
+const (
+	nsqDrainPollPeriod       = time.Millisecond * 50
+	nsqDeadLetterRetryPeriod = time.Second
+)
+
+// Modes determining which address lists the NSQ input connects with.
+const (
+	nsqModeBoth    = "both"
+	nsqModeDirect  = "direct"
+	nsqModeLookupd = "lookupd"
+)
+
 func newNSQInput(conf input.Config, mgr bundle.NewManagement) (input.Streamed, error) {
+	boff, err := nsqConnBackOff(conf.NSQ.ConnectionBackoff)
+	if err != nil {
+		return nil, err
+	}
 	var n input.Async
-	var err error
 	if n, err = newNSQReader(conf.NSQ, mgr); err != nil {
 		return nil, err
 	}
-	return input.NewAsyncReader("nsq", true, n, mgr)
+	return input.NewAsyncReader("nsq", true, n, mgr, input.AsyncReaderWithConnBackOff(boff))
+}
+
+// nsqConnBackOff creates the backoff between failed attempts to connect the
+// NSQ input, which is waited out by the async reader. The async reader also
+// waits it out after failed reads.
+func nsqConnBackOff(conf input.NSQBackoffConfig) (*backoff.ExponentialBackOff, error) {
+	boff := backoff.NewExponentialBackOff()
+	boff.MaxElapsedTime = 0
+
+	var err error
+	if boff.InitialInterval, err = time.ParseDuration(conf.InitialInterval); err != nil {
+		return nil, fmt.Errorf("failed to parse connection backoff initial interval string: %v", err)
+	}
+	if boff.MaxInterval, err = time.ParseDuration(conf.MaxInterval); err != nil {
+		return nil, fmt.Errorf("failed to parse connection backoff max interval string: %v", err)
+	}
+	boff.Reset()
+	return boff, nil
+}
+
+// nsqTopicMessage is a message along with the topic it was consumed from.
+type nsqTopicMessage struct {
+	*nsq.Message
+	topic string
+}
+
+// nsqTopicHandler passes the messages of a consumer of a topic to a reader.
+type nsqTopicHandler struct {
+	topic  string
+	reader *nsqReader
+}
+
+func (h *nsqTopicHandler) HandleMessage(message *nsq.Message) error {
+	return h.reader.handleMessage(h.topic, message)
+}
+
+// LogFailedMessage is called by the consumer for messages that exceed the
+// maximum number of attempts, before they are finished.
+func (h *nsqTopicHandler) LogFailedMessage(message *nsq.Message) {
+	h.reader.deadLetter(h.topic, message)
 }
 
 type nsqReader struct {
-	consumer *nsq.Consumer
-	cMut     sync.Mutex
+	consumers []*nsq.Consumer
+	cMut      sync.Mutex
+
+	unAckMut    sync.Mutex
+	unAckMsgs   map[nsq.MessageID]*nsq.Message
+	pendingMsgs []*nsq.Message
 
-	unAckMsgs []*nsq.Message
+	batchPolicy *policy.Batcher
 
 	tlsConf         *tls.Config
 	addresses       []string
 	lookupAddresses []string
-	conf            input.NSQConfig
-	log             log.Modular
+	mode            string
+	topics          []string
+
+	dialTimeout         time.Duration
+	readTimeout         time.Duration
+	writeTimeout        time.Duration
+	lookupdPollInterval time.Duration
+	drainTimeout        time.Duration
+
+	connFailed bool
+
+	deadLetterOut   output.Streamed
+	deadLetterMut   sync.RWMutex
+	deadLetterTChan chan message.Transaction
+
+	conf input.NSQConfig
+	log  log.Modular
 
-	internalMessages chan *nsq.Message
+	internalMessages chan nsqTopicMessage
 	interruptChan    chan struct{}
 	interruptOnce    sync.Once
 }
 
 func newNSQReader(conf input.NSQConfig, mgr bundle.NewManagement) (*nsqReader, error) {
+	if conf.Batching.IsNoop() {
+		conf.Batching.Count = 1
+	}
+	batchPolicy, err := policy.New(conf.Batching, mgr.IntoPath("nsq", "batching"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise batch policy: %w", err)
+	}
+
 	n := nsqReader{
+		batchPolicy:      batchPolicy,
+		unAckMsgs:        map[nsq.MessageID]*nsq.Message{},
 		conf:             conf,
 		log:              mgr.Logger(),
-		internalMessages: make(chan *nsq.Message),
+		internalMessages: make(chan nsqTopicMessage),
 		interruptChan:    make(chan struct{}),
 	}
 	for _, addr := range conf.Addresses {
@@ -89,19 +222,121 @@ func newNSQReader(conf input.NSQConfig, mgr bundle.NewManagement) (*nsqReader, e
 			}
 		}
 	}
+	switch n.mode = conf.Mode; n.mode {
+	case nsqModeBoth:
+	case nsqModeDirect:
+		if len(n.addresses) == 0 {
+			return nil, errors.New("at least one nsqd address must be specified in the field nsqd_tcp_addresses when mode is direct")
+		}
+	case nsqModeLookupd:
+		if len(n.lookupAddresses) == 0 {
+			return nil, errors.New("at least one nsqlookupd address must be specified in the field lookupd_http_addresses when mode is lookupd")
+		}
+	default:
+		return nil, fmt.Errorf("mode not recognised: %v", conf.Mode)
+	}
+	if conf.Topic != "" {
+		n.topics = append(n.topics, conf.Topic)
+	}
+	for _, topic := range conf.Topics {
+		for _, splitTopic := range strings.Split(topic, ",") {
+			if len(splitTopic) > 0 {
+				n.topics = append(n.topics, splitTopic)
+			}
+		}
+	}
+	if len(n.topics) == 0 {
+		return nil, errors.New("at least one topic must be specified")
+	}
 	if conf.TLS.Enabled {
-		var err error
 		if n.tlsConf, err = conf.TLS.Get(mgr.FS()); err != nil {
 			return nil, err
 		}
 	}
+	if n.dialTimeout, err = time.ParseDuration(conf.DialTimeout); err != nil {
+		return nil, fmt.Errorf("failed to parse dial timeout string: %v", err)
+	}
+	if n.readTimeout, err = time.ParseDuration(conf.ReadTimeout); err != nil {
+		return nil, fmt.Errorf("failed to parse read timeout string: %v", err)
+	}
+	if n.writeTimeout, err = time.ParseDuration(conf.WriteTimeout); err != nil {
+		return nil, fmt.Errorf("failed to parse write timeout string: %v", err)
+	}
+	if n.lookupdPollInterval, err = time.ParseDuration(conf.LookupdPollInterval); err != nil {
+		return nil, fmt.Errorf("failed to parse lookupd poll interval string: %v", err)
+	}
+	if n.drainTimeout, err = time.ParseDuration(conf.DrainTimeout); err != nil {
+		return nil, fmt.Errorf("failed to parse drain timeout string: %v", err)
+	}
+	if conf.MaxAttempts < 0 || conf.MaxAttempts > math.MaxUint16 {
+		return nil, fmt.Errorf("max attempts must be between 0 and %v, got: %v", math.MaxUint16, conf.MaxAttempts)
+	}
+	if conf.SampleRate < 0 || conf.SampleRate > 99 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 99, got: %v", conf.SampleRate)
+	}
+	if conf.DeadLetter != nil {
+		if n.deadLetterOut, err = mgr.IntoPath("nsq", "dead_letter").NewOutput(*conf.DeadLetter); err != nil {
+			return nil, fmt.Errorf("failed to create dead letter output: %w", err)
+		}
+		n.deadLetterTChan = make(chan message.Transaction)
+		if err = n.deadLetterOut.Consume(n.deadLetterTChan); err != nil {
+			n.deadLetterOut.TriggerCloseNow()
+			return nil, err
+		}
+	}
 	return &n, nil
 }
 
-func (n *nsqReader) HandleMessage(message *nsq.Message) error {
+// deadLetter writes a message that exceeded the maximum number of attempts to
+// the dead letter output, retrying until the write is acknowledged. When the
+// reader is closed before then the message is requeued, which prevents the
+// consumer from finishing it.
+func (n *nsqReader) deadLetter(topic string, msg *nsq.Message) {
+	n.deadLetterMut.RLock()
+	defer n.deadLetterMut.RUnlock()
+
+	if n.deadLetterTChan == nil {
+		return
+	}
+
+	for {
+		part := message.NewPart(msg.Body)
+		part.MetaSetMut("nsq_topic", topic)
+		part.MetaSetMut("nsq_attempts", int64(msg.Attempts))
+
+		resChan := make(chan error, 1)
+		select {
+		case n.deadLetterTChan <- message.NewTransaction(message.Batch{part}, resChan):
+		case <-n.interruptChan:
+			msg.Requeue(-1)
+			return
+		}
+
+		var err error
+		select {
+		case err = <-resChan:
+		case <-n.interruptChan:
+			msg.Requeue(-1)
+			return
+		}
+		if err == nil {
+			return
+		}
+
+		n.log.Errorf("Failed to write NSQ message to dead letter output: %v\n", err)
+		select {
+		case <-time.After(nsqDeadLetterRetryPeriod):
+		case <-n.interruptChan:
+			msg.Requeue(-1)
+			return
+		}
+	}
+}
+
+func (n *nsqReader) handleMessage(topic string, message *nsq.Message) error {
 	message.DisableAutoResponse()
 	select {
-	case n.internalMessages <- message:
+	case n.internalMessages <- nsqTopicMessage{Message: message, topic: topic}:
 	case <-n.interruptChan:
 		message.Requeue(-1)
 		message.Finish()
@@ -113,88 +348,256 @@ func (n *nsqReader) Connect(ctx context.Context) (err error) {
 	n.cMut.Lock()
 	defer n.cMut.Unlock()
 
-	if n.consumer != nil {
+	if len(n.consumers) > 0 {
 		return nil
 	}
 
 	cfg := nsq.NewConfig()
 	cfg.UserAgent = n.conf.UserAgent
+	cfg.AuthSecret = n.conf.AuthSecret
 	cfg.MaxInFlight = n.conf.MaxInFlight
-	if n.tlsConf != nil {
-		cfg.TlsV1 = true
-		cfg.TlsConfig = n.tlsConf
+	cfg.MaxAttempts = uint16(n.conf.MaxAttempts)
+	cfg.DialTimeout = n.dialTimeout
+	cfg.ReadTimeout = n.readTimeout
+	cfg.WriteTimeout = n.writeTimeout
+	cfg.LookupdPollInterval = n.lookupdPollInterval
+	cfg.SampleRate = int32(n.conf.SampleRate)
+	setTLSConfig(cfg, n.tlsConf)
+
+	consumers := make([]*nsq.Consumer, 0, len(n.topics))
+	for _, topic := range n.topics {
+		var consumer *nsq.Consumer
+		if consumer, err = n.connectTopic(topic, cfg); err != nil {
+			for _, c := range consumers {
+				c.Stop()
+			}
+			n.connFailed = true
+			return
+		}
+		consumers = append(consumers, consumer)
 	}
 
-	var consumer *nsq.Consumer
-	if consumer, err = nsq.NewConsumer(n.conf.Topic, n.conf.Channel, cfg); err != nil {
-		return
+	if n.connFailed {
+		n.log.Infof("Reconnected to NSQ after failed attempts\n")
+	}
+	n.connFailed = false
+	n.consumers = consumers
+	n.log.Infof("Receiving NSQ messages of topics %s from addresses: %s\n", n.topics, n.connectAddresses())
+	return
+}
+
+func (n *nsqReader) connectTopic(topic string, cfg *nsq.Config) (*nsq.Consumer, error) {
+	consumer, err := nsq.NewConsumer(topic, n.conf.Channel, cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	consumer.SetLogger(llog.New(io.Discard, "", llog.Flags()), nsq.LogLevelError)
-	consumer.AddHandler(n)
+	consumer.AddHandler(&nsqTopicHandler{topic: topic, reader: n})
 
-	if err = consumer.ConnectToNSQDs(n.addresses); err != nil {
-		consumer.Stop()
-		return
+	if n.mode != nsqModeLookupd && len(n.addresses) > 0 {
+		if err = consumer.ConnectToNSQDs(n.addresses); err != nil {
+			consumer.Stop()
+			return nil, err
+		}
 	}
-	if err = consumer.ConnectToNSQLookupds(n.lookupAddresses); err != nil {
-		consumer.Stop()
-		return
+	if n.mode != nsqModeDirect && len(n.lookupAddresses) > 0 {
+		if err = consumer.ConnectToNSQLookupds(n.lookupAddresses); err != nil {
+			consumer.Stop()
+			return nil, err
+		}
 	}
+	return consumer, nil
+}
 
-	n.consumer = consumer
-	n.log.Infof("Receiving NSQ messages from addresses: %s\n", n.addresses)
-	return
+// connectAddresses returns the addresses the reader connects with according to
+// its mode.
+func (n *nsqReader) connectAddresses() []string {
+	switch n.mode {
+	case nsqModeDirect:
+		return n.addresses
+	case nsqModeLookupd:
+		return n.lookupAddresses
+	}
+	return append(append([]string{}, n.addresses...), n.lookupAddresses...)
 }
 
 func (n *nsqReader) disconnect() error {
 	n.cMut.Lock()
 	defer n.cMut.Unlock()
 
-	if n.consumer != nil {
-		n.consumer.Stop()
-		n.consumer = nil
+	if len(n.consumers) > 0 {
+		n.log.Infof("Disconnecting from NSQ\n")
 	}
+	for _, consumer := range n.consumers {
+		consumer.Stop()
+	}
+	n.consumers = nil
 	return nil
 }
 
-func (n *nsqReader) read(ctx context.Context) (*nsq.Message, error) {
-	var msg *nsq.Message
+// requeueMsgs requeues a slice of messages that are yet to be acknowledged.
+func requeueMsgs(msgs []*nsq.Message) {
+	for _, m := range msgs {
+		m.Requeue(-1)
+		m.Finish()
+	}
+}
+
+func (n *nsqReader) ReadBatch(ctx context.Context) (message.Batch, input.AsyncAckFn, error) {
+	for {
+		var flushTimer *time.Timer
+		var flushChan <-chan time.Time
+		if tNext := n.batchPolicy.UntilNext(); tNext >= 0 {
+			flushTimer = time.NewTimer(tNext)
+			flushChan = flushTimer.C
+		}
+
+		flush := false
+		select {
+		case msg := <-n.internalMessages:
+			n.unAckMut.Lock()
+			n.pendingMsgs = append(n.pendingMsgs, msg.Message)
+			n.unAckMut.Unlock()
+			part := message.NewPart(msg.Body)
+			part.MetaSetMut("nsq_topic", msg.topic)
+			flush = n.batchPolicy.Add(part)
+		case <-flushChan:
+			flush = true
+		case <-ctx.Done():
+		case <-n.interruptChan:
+			if flushTimer != nil {
+				flushTimer.Stop()
+			}
+			_ = n.batchPolicy.Flush(context.Background())
+			n.unAckMut.Lock()
+			requeueMsgs(n.pendingMsgs)
+			n.pendingMsgs = nil
+			n.unAckMut.Unlock()
+			return nil, nil, component.ErrShuttingDown
+		}
+		if flushTimer != nil {
+			flushTimer.Stop()
+		}
+		if ctx.Err() != nil {
+			return nil, nil, n.ctxDoneErr()
+		}
+		if !flush {
+			continue
+		}
+
+		batch := n.batchPolicy.Flush(ctx)
+		if len(batch) == 0 {
+			continue
+		}
+
+		n.unAckMut.Lock()
+		msgs := n.pendingMsgs
+		n.pendingMsgs = nil
+		for _, msg := range msgs {
+			n.unAckMsgs[msg.ID] = msg
+		}
+		n.unAckMut.Unlock()
+
+		return batch, func(rctx context.Context, res error) error {
+			n.unAckMut.Lock()
+			for _, msg := range msgs {
+				delete(n.unAckMsgs, msg.ID)
+			}
+			n.unAckMut.Unlock()
+
+			for _, msg := range msgs {
+				if res != nil {
+					msg.Requeue(-1)
+				}
+				msg.Finish()
+			}
+			return nil
+		}, nil
+	}
+}
+
+// ctxDoneErr returns the error of a read that was abandoned due to its context
+// ending, which is ErrShuttingDown when the reader has also been closed so that
+// it is not mistaken for a lack of data.
+func (n *nsqReader) ctxDoneErr() error {
 	select {
-	case msg = <-n.internalMessages:
-		return msg, nil
-	case <-ctx.Done():
 	case <-n.interruptChan:
-		for _, m := range n.unAckMsgs {
-			m.Requeue(-1)
-			m.Finish()
-		}
-		n.unAckMsgs = nil
-		_ = n.disconnect()
-		return nil, component.ErrTypeClosed
+		return component.ErrShuttingDown
+	default:
 	}
-	return nil, component.ErrTimeout
+	return component.ErrTimeout
 }
 
-func (n *nsqReader) ReadBatch(ctx context.Context) (message.Batch, input.AsyncAckFn, error) {
-	msg, err := n.read(ctx)
-	if err != nil {
-		return nil, nil, err
+// drain stops the consumers from receiving any more messages and then waits,
+// bounded by the drain timeout, for outstanding messages to be acknowledged.
+// Any messages that remain unacknowledged are requeued.
+func (n *nsqReader) drain(ctx context.Context) {
+	n.cMut.Lock()
+	for _, consumer := range n.consumers {
+		consumer.ChangeMaxInFlight(0)
 	}
-	n.unAckMsgs = append(n.unAckMsgs, msg)
-	return message.QuickBatch([][]byte{msg.Body}), func(rctx context.Context, res error) error {
-		if res != nil {
-			msg.Requeue(-1)
+	n.cMut.Unlock()
+
+	drainCtx, done := context.WithTimeout(ctx, n.drainTimeout)
+	defer done()
+
+	ticker := time.NewTicker(nsqDrainPollPeriod)
+	defer ticker.Stop()
+
+drainLoop:
+	for {
+		n.unAckMut.Lock()
+		remaining := len(n.unAckMsgs)
+		n.unAckMut.Unlock()
+		if remaining == 0 {
+			break
 		}
-		msg.Finish()
-		return nil
-	}, nil
+		select {
+		case <-ticker.C:
+		case <-drainCtx.Done():
+			break drainLoop
+		}
+	}
+
+	n.unAckMut.Lock()
+	defer n.unAckMut.Unlock()
+
+	requeueMsgs(n.pendingMsgs)
+	n.pendingMsgs = nil
+	if len(n.unAckMsgs) > 0 {
+		n.log.Warnf("Requeueing %v NSQ messages that were not acknowledged within the drain timeout\n", len(n.unAckMsgs))
+	}
+	for id, m := range n.unAckMsgs {
+		m.Requeue(-1)
+		m.Finish()
+		delete(n.unAckMsgs, id)
+	}
 }
 
 func (n *nsqReader) Close(ctx context.Context) (err error) {
 	n.interruptOnce.Do(func() {
 		close(n.interruptChan)
 	})
+	n.drain(ctx)
 	err = n.disconnect()
+	_ = n.batchPolicy.Close(ctx)
+	n.closeDeadLetter(ctx)
 	return
 }
+
+// closeDeadLetter shuts down the dead letter output once no more messages are
+// being written to it.
+func (n *nsqReader) closeDeadLetter(ctx context.Context) {
+	n.deadLetterMut.Lock()
+	defer n.deadLetterMut.Unlock()
+
+	if n.deadLetterTChan == nil {
+		return
+	}
+	close(n.deadLetterTChan)
+	n.deadLetterTChan = nil
+	if err := n.deadLetterOut.WaitForClose(ctx); err != nil {
+		n.deadLetterOut.TriggerCloseNow()
+	}
+}
@@ -0,0 +1,260 @@
+package nsq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestNSQReaderTopics(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Topic = "foo"
+	conf.Topics = []string{"bar,baz", "qux"}
+	conf.Channel = "benthos"
+
+	n, err := newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar", "baz", "qux"}, n.topics)
+
+	conf.Topic = ""
+	n, err = newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar", "baz", "qux"}, n.topics)
+}
+
+func TestNSQReaderNoTopics(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Channel = "benthos"
+
+	_, err := newNSQReader(conf, mock.NewManager())
+	require.Error(t, err)
+}
+
+func TestNSQConnBackOff(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.ConnectionBackoff.InitialInterval = "1m"
+	conf.ConnectionBackoff.MaxInterval = "2m"
+
+	boff, err := nsqConnBackOff(conf.ConnectionBackoff)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, boff.InitialInterval)
+	assert.Equal(t, 2*time.Minute, boff.MaxInterval)
+	assert.Equal(t, time.Duration(0), boff.MaxElapsedTime)
+}
+
+func TestNSQReaderConnectFailsWithoutWaiting(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Addresses = []string{"127.0.0.1:1"}
+	conf.Topic = "foo"
+	conf.Channel = "benthos"
+	conf.ConnectionBackoff.InitialInterval = "1m"
+
+	n, err := newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	// The connection backoff is waited out by the async reader, and therefore
+	// consecutive attempts should fail immediately.
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+	for i := 0; i < 2; i++ {
+		err := n.Connect(ctx)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, context.DeadlineExceeded)
+	}
+	assert.True(t, n.connFailed)
+}
+
+func TestNSQInputBadConnectBackoff(t *testing.T) {
+	conf := input.NewConfig()
+	conf.NSQ.Topic = "foo"
+	conf.NSQ.Channel = "benthos"
+	conf.NSQ.ConnectionBackoff.InitialInterval = "nope"
+
+	_, err := newNSQInput(conf, mock.NewManager())
+	require.EqualError(t, err, "failed to parse connection backoff initial interval string: time: invalid duration \"nope\"")
+}
+
+func TestNSQReaderModes(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		nsqd        []string
+		lookupd     []string
+		errContains string
+	}{
+		{name: "both without addresses", mode: "both"},
+		{name: "direct", mode: "direct", nsqd: []string{"localhost:4150"}},
+		{name: "lookupd", mode: "lookupd", lookupd: []string{"localhost:4161"}},
+		{
+			name:        "direct without nsqd addresses",
+			mode:        "direct",
+			lookupd:     []string{"localhost:4161"},
+			errContains: "nsqd_tcp_addresses",
+		},
+		{
+			name:        "lookupd without lookupd addresses",
+			mode:        "lookupd",
+			nsqd:        []string{"localhost:4150"},
+			errContains: "lookupd_http_addresses",
+		},
+		{
+			name:        "unknown mode",
+			mode:        "nope",
+			errContains: "mode not recognised",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := input.NewNSQConfig()
+			conf.Topic = "foo"
+			conf.Channel = "benthos"
+			conf.Mode = test.mode
+			conf.Addresses = append(conf.Addresses, test.nsqd...)
+			conf.LookupAddresses = append(conf.LookupAddresses, test.lookupd...)
+
+			_, err := newNSQReader(conf, mock.NewManager())
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNSQReaderConnectAddresses(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Addresses = []string{"localhost:4150"}
+	conf.LookupAddresses = []string{"localhost:4161"}
+	conf.Topic = "foo"
+	conf.Channel = "benthos"
+
+	n, err := newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:4150", "localhost:4161"}, n.connectAddresses())
+
+	conf.Mode = "direct"
+	n, err = newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:4150"}, n.connectAddresses())
+
+	conf.Mode = "lookupd"
+	n, err = newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:4161"}, n.connectAddresses())
+}
+
+type testMessageDelegate struct {
+	requeued bool
+	finished bool
+}
+
+func (d *testMessageDelegate) OnFinish(*nsq.Message) {
+	d.finished = true
+}
+
+func (d *testMessageDelegate) OnRequeue(*nsq.Message, time.Duration, bool) {
+	d.requeued = true
+}
+
+func (d *testMessageDelegate) OnTouch(*nsq.Message) {}
+
+func TestNSQReaderDeadLetter(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Topic = "foo"
+	conf.Channel = "benthos"
+
+	n, err := newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	tChan := make(chan message.Transaction)
+	n.deadLetterTChan = tChan
+
+	delegate := &testMessageDelegate{}
+	msg := nsq.NewMessage(nsq.MessageID{}, []byte("hello world"))
+	msg.Attempts = 6
+	msg.Delegate = delegate
+
+	done := make(chan struct{})
+	go func() {
+		n.deadLetter("foo", msg)
+		close(done)
+	}()
+
+	var tran message.Transaction
+	select {
+	case tran = <-tChan:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	require.Len(t, tran.Payload, 1)
+	assert.Equal(t, "hello world", string(tran.Payload.Get(0).AsBytes()))
+	assert.Equal(t, "foo", tran.Payload.Get(0).MetaGetStr("nsq_topic"))
+	assert.Equal(t, "6", tran.Payload.Get(0).MetaGetStr("nsq_attempts"))
+	require.NoError(t, tran.Ack(context.Background(), nil))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	assert.False(t, delegate.requeued)
+}
+
+func TestNSQReaderDeadLetterInterrupted(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Topic = "foo"
+	conf.Channel = "benthos"
+
+	n, err := newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+	n.deadLetterTChan = make(chan message.Transaction)
+
+	delegate := &testMessageDelegate{}
+	msg := nsq.NewMessage(nsq.MessageID{}, []byte("hello world"))
+	msg.Delegate = delegate
+
+	close(n.interruptChan)
+	n.deadLetter("foo", msg)
+	assert.True(t, delegate.requeued)
+
+	// Once requeued the message is no longer finished by the consumer.
+	msg.Finish()
+	assert.False(t, delegate.finished)
+}
+
+func TestNSQReaderReadBatchErrors(t *testing.T) {
+	conf := input.NewNSQConfig()
+	conf.Topic = "foo"
+	conf.Channel = "benthos"
+
+	n, err := newNSQReader(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	ctx, done := context.WithCancel(context.Background())
+	done()
+
+	// A read that ends with its context is only a lack of data.
+	_, _, err = n.ReadBatch(ctx)
+	assert.Equal(t, component.ErrTimeout, err)
+
+	close(n.interruptChan)
+
+	_, _, err = n.ReadBatch(context.Background())
+	assert.Equal(t, component.ErrShuttingDown, err)
+	assert.True(t, errors.Is(err, component.ErrTypeClosed))
+	assert.False(t, errors.Is(err, component.ErrTimeout))
+}
@@ -85,10 +85,7 @@ func (n *nsqWriter) Connect(ctx context.Context) error {
 
 	cfg := nsq.NewConfig()
 	cfg.UserAgent = n.conf.UserAgent
-	if n.tlsConf != nil {
-		cfg.TlsV1 = true
-		cfg.TlsConfig = n.tlsConf
-	}
+	setTLSConfig(cfg, n.tlsConf)
 
 	producer, err := nsq.NewProducer(n.conf.Address, cfg)
 	if err != nil {
@@ -0,0 +1,26 @@
+package nsq
+
+import (
+	"crypto/tls"
+
+	"github.com/nsqio/go-nsq"
+)
+
+// setTLSConfig enables TLS for connections to nsqd with the provided config.
+//
+// The go-nsq field TlsV1 enables the TLS feature of the nsqd protocol (named
+// tls_v1) rather than restricting connections to TLS 1.0, the negotiated
+// version is determined entirely by the provided config. The config is cloned
+// so that the minimum version of the btls config is respected, and a minimum of
+// TLS 1.2 is applied when no minimum is set.
+func setTLSConfig(cfg *nsq.Config, tlsConf *tls.Config) {
+	if tlsConf == nil {
+		return
+	}
+	tlsConf = tlsConf.Clone()
+	if tlsConf.MinVersion == 0 {
+		tlsConf.MinVersion = tls.VersionTLS12
+	}
+	cfg.TlsV1 = true
+	cfg.TlsConfig = tlsConf
+}
@@ -0,0 +1,26 @@
+package nsq
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTLSConfig(t *testing.T) {
+	cfg := nsq.NewConfig()
+	setTLSConfig(cfg, nil)
+	assert.False(t, cfg.TlsV1)
+
+	cfg = nsq.NewConfig()
+	setTLSConfig(cfg, &tls.Config{InsecureSkipVerify: true})
+	assert.True(t, cfg.TlsV1)
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.TlsConfig.MinVersion)
+	assert.True(t, cfg.TlsConfig.InsecureSkipVerify)
+
+	cfg = nsq.NewConfig()
+	setTLSConfig(cfg, &tls.Config{MinVersion: tls.VersionTLS13})
+	assert.True(t, cfg.TlsV1)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.TlsConfig.MinVersion)
+}
@@ -0,0 +1,35 @@
+package objstore
+
+import (
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/docs/interop"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// BatchPolicyField returns a config field spec for the batching policy of an
+// output, which extends the common batching fields with jitter fields that
+// randomise the period of each batch. This prevents the uploads of many outputs
+// that share a period from all landing on the object store at once.
+func BatchPolicyField() *service.ConfigField {
+	spec := interop.Unwrap(service.NewBatchPolicyField("batching"))
+
+	var children docs.FieldSpecs
+	for _, child := range spec.Children {
+		children = append(children, child)
+		if child.Name == "period" {
+			children = append(children,
+				docs.FieldString(
+					"jitter",
+					"A maximum random duration added to the `period` of each batch, which spreads the flushes of many outputs or instances that share the same period over a window rather than aligning them, smoothing the load on the object store. Each flush waits for the period plus a duration chosen uniformly between zero and the jitter.",
+					"100ms", "5s",
+				).HasDefault("").Advanced().AtVersion("4.11.0"),
+				docs.FieldInt(
+					"jitter_seed",
+					"An optional seed for the random durations of `jitter`, which makes the sequence of durations deterministic for each instance, e.g. for reproducible tests. When `0` the durations are seeded randomly.",
+				).HasDefault(0).Advanced().AtVersion("4.11.0"),
+			)
+		}
+	}
+	spec.Children = children
+	return service.NewInternalField(spec)
+}
@@ -0,0 +1,59 @@
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestBatchPolicyFieldJitter(t *testing.T) {
+	spec := service.NewConfigSpec().Field(BatchPolicyField())
+
+	pConf, err := spec.ParseYAML(`
+batching:
+  count: 10
+  period: 5s
+  jitter: 1s
+  jitter_seed: 5
+`, nil)
+	require.NoError(t, err)
+
+	bConf, err := pConf.FieldBatchPolicy("batching")
+	require.NoError(t, err)
+	assert.Equal(t, 10, bConf.Count)
+	assert.Equal(t, "5s", bConf.Period)
+	assert.Equal(t, "1s", bConf.Jitter)
+	assert.Equal(t, int64(5), bConf.JitterSeed)
+
+	_, err = bConf.NewBatcher(service.MockResources())
+	require.NoError(t, err)
+
+	pConf, err = spec.ParseYAML(`
+batching:
+  count: 10
+`, nil)
+	require.NoError(t, err)
+
+	bConf, err = pConf.FieldBatchPolicy("batching")
+	require.NoError(t, err)
+	assert.Equal(t, "", bConf.Jitter)
+	assert.Equal(t, int64(0), bConf.JitterSeed)
+}
+
+func TestBatchPolicyFieldJitterRequiresPeriod(t *testing.T) {
+	pConf, err := service.NewConfigSpec().Field(BatchPolicyField()).ParseYAML(`
+batching:
+  count: 10
+  jitter: 1s
+`, nil)
+	require.NoError(t, err)
+
+	bConf, err := pConf.FieldBatchPolicy("batching")
+	require.NoError(t, err)
+
+	_, err = bConf.NewBatcher(service.MockResources())
+	require.EqualError(t, err, "jitter requires a period to be set")
+}
@@ -0,0 +1,130 @@
+package objstore
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// BundleFormat is a format used to write all messages of a batch as a single
+// object.
+type BundleFormat string
+
+// BundleFormat variants.
+const (
+	BundleNone      BundleFormat = "none"
+	BundleLines     BundleFormat = "lines"
+	BundleJSONArray BundleFormat = "json_array"
+	BundleTar       BundleFormat = "tar"
+	BundleZip       BundleFormat = "zip"
+)
+
+// BundleField returns a config field spec for the format used to write each
+// batch as a single object.
+func BundleField() *service.ConfigField {
+	return service.NewStringAnnotatedEnumField("bundle", map[string]string{
+		string(BundleNone):      "Each message is written as an individual object.",
+		string(BundleLines):     "The raw contents of the messages are joined with a line break between each one.",
+		string(BundleJSONArray): "Each message is parsed as a JSON document and appended to an array.",
+		string(BundleTar):       "The messages are archived to a unix standard tape archive, where each file is named after the index of its message within the batch.",
+		string(BundleZip):       "The messages are archived to a zip file, where each file is named after the index of its message within the batch.",
+	}).Description("A format with which to write all messages of a batch as a single object, which avoids writing many small objects when combined with batching. When bundled the fields `directory` and `path` are evaluated once per batch against the first message, and batch wide interpolation functions such as `batch_size()` are supported.").
+		Advanced().
+		Default(string(BundleNone))
+}
+
+// BundleFormatFromConfig returns the bundle format of a parsed config
+// containing the field BundleField.
+func BundleFormatFromConfig(conf *service.ParsedConfig) (BundleFormat, error) {
+	str, err := conf.FieldString("bundle")
+	if err != nil {
+		return "", err
+	}
+	switch b := BundleFormat(str); b {
+	case BundleNone, BundleLines, BundleJSONArray, BundleTar, BundleZip:
+		return b, nil
+	}
+	return "", fmt.Errorf("bundle format not recognised: %v", str)
+}
+
+// Bundle returns the contents of a single object containing all messages of a
+// batch.
+func (b BundleFormat) Bundle(batch service.MessageBatch) ([]byte, error) {
+	parts := make([][]byte, len(batch))
+	for i, msg := range batch {
+		var err error
+		if parts[i], err = msg.AsBytes(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch b {
+	case BundleLines:
+		return bytes.Join(parts, []byte("\n")), nil
+	case BundleJSONArray:
+		array := make([]json.RawMessage, len(parts))
+		for i, p := range parts {
+			if !json.Valid(p) {
+				return nil, fmt.Errorf("failed to parse message %v as JSON", i)
+			}
+			array[i] = p
+		}
+		return json.Marshal(array)
+	case BundleTar:
+		return tarBundle(parts)
+	case BundleZip:
+		return zipBundle(parts)
+	}
+	return nil, fmt.Errorf("bundle format not recognised: %v", b)
+}
+
+func tarBundle(parts [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	modTime := time.Now()
+	for i, p := range parts {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    strconv.Itoa(i),
+			Mode:    0o644,
+			Size:    int64(len(p)),
+			ModTime: modTime,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(p); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func zipBundle(parts [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i, p := range parts {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     strconv.Itoa(i),
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write(p); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package objstore
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testBundleBatch() service.MessageBatch {
+	return service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"foo"}`)),
+		service.NewMessage([]byte(`{"id": "bar"}`)),
+	}
+}
+
+func TestBundleLines(t *testing.T) {
+	data, err := BundleLines.Bundle(testBundleBatch())
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"foo\"}\n{\"id\": \"bar\"}", string(data))
+}
+
+func TestBundleJSONArray(t *testing.T) {
+	data, err := BundleJSONArray.Bundle(testBundleBatch())
+	require.NoError(t, err)
+	assert.Equal(t, `[{"id":"foo"},{"id":"bar"}]`, string(data))
+
+	_, err = BundleJSONArray.Bundle(service.MessageBatch{service.NewMessage([]byte("nope"))})
+	require.Error(t, err)
+}
+
+func TestBundleTar(t *testing.T) {
+	data, err := BundleTar.Bundle(testBundleBatch())
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(body)
+	}
+	assert.Equal(t, map[string]string{
+		"0": `{"id":"foo"}`,
+		"1": `{"id": "bar"}`,
+	}, files)
+}
+
+func TestBundleZip(t *testing.T) {
+	data, err := BundleZip.Bundle(testBundleBatch())
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[f.Name] = string(body)
+	}
+	assert.Equal(t, map[string]string{
+		"0": `{"id":"foo"}`,
+		"1": `{"id": "bar"}`,
+	}, files)
+}
@@ -0,0 +1,108 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// CheckpointCacheField returns a config field spec for a cache resource that
+// records the objects consumed by an input.
+func CheckpointCacheField() *service.ConfigField {
+	return service.NewStringField("checkpoint_cache").
+		Description("An optional [cache resource](/docs/components/caches/about) used to record the key and ETag of each object once it has been successfully processed, objects that are recorded with the same ETag are skipped, which prevents objects from being consumed again after a restart. Objects that are modified after being processed obtain a new ETag and are therefore consumed again. Checkpointing is bypassed when `delete_objects` is enabled, as processed objects are removed from the bucket instead.").
+		Advanced().
+		Optional()
+}
+
+// Checkpoint records the objects that have been processed by an input within
+// a cache resource, where each object is identified by its key and ETag.
+type Checkpoint struct {
+	cache     string
+	namespace string
+	mgr       *service.Resources
+}
+
+// CheckpointFromConfig creates a Checkpoint from a parsed config optionally
+// containing the field CheckpointCacheField, returning nil when it is not set.
+// The namespace is prefixed to the key of each object recorded, allowing a
+// single cache to be shared by inputs consuming different buckets.
+func CheckpointFromConfig(conf *service.ParsedConfig, namespace string, mgr *service.Resources) (*Checkpoint, error) {
+	if !conf.Contains("checkpoint_cache") {
+		return nil, nil
+	}
+	cache, err := conf.FieldString("checkpoint_cache")
+	if err != nil {
+		return nil, err
+	}
+	if cache == "" {
+		return nil, nil
+	}
+	if !mgr.HasCache(cache) {
+		return nil, fmt.Errorf("cache resource %v not found", cache)
+	}
+	return &Checkpoint{
+		cache:     cache,
+		namespace: namespace,
+		mgr:       mgr,
+	}, nil
+}
+
+func (c *Checkpoint) cacheKey(key string) string {
+	return c.namespace + "/" + key
+}
+
+// Processed returns true if an object with the provided key and ETag has
+// already been recorded as processed. A nil Checkpoint records nothing.
+func (c *Checkpoint) Processed(ctx context.Context, key, etag string) (processed bool, err error) {
+	if c == nil {
+		return false, nil
+	}
+	if cerr := c.mgr.AccessCache(ctx, c.cache, func(cache service.Cache) {
+		var v []byte
+		if v, err = cache.Get(ctx, c.cacheKey(key)); err != nil {
+			if errors.Is(err, service.ErrKeyNotFound) {
+				err = nil
+			}
+			return
+		}
+		processed = string(v) == etag
+	}); cerr != nil {
+		return false, cerr
+	}
+	return
+}
+
+// Record records an object with the provided key and ETag as processed. A nil
+// Checkpoint records nothing.
+func (c *Checkpoint) Record(ctx context.Context, key, etag string) (err error) {
+	if c == nil {
+		return nil
+	}
+	if cerr := c.mgr.AccessCache(ctx, c.cache, func(cache service.Cache) {
+		err = cache.Set(ctx, c.cacheKey(key), []byte(etag), nil)
+	}); cerr != nil {
+		return cerr
+	}
+	return
+}
+
+// Ack returns an AckFunc that records an object as processed once the provided
+// AckFunc succeeds for a successfully processed message. A nil Checkpoint
+// returns the provided AckFunc unchanged.
+func (c *Checkpoint) Ack(key, etag string, fn service.AckFunc) service.AckFunc {
+	if c == nil {
+		return fn
+	}
+	return func(ctx context.Context, res error) error {
+		if err := fn(ctx, res); err != nil || res != nil {
+			return err
+		}
+		if err := c.Record(ctx, key, etag); err != nil {
+			return fmt.Errorf("failed to record checkpoint of object %v: %w", key, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestCheckpointUnset(t *testing.T) {
+	pConf, err := service.NewConfigSpec().Field(CheckpointCacheField()).ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+
+	c, err := CheckpointFromConfig(pConf, "foo", service.MockResources())
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	processed, err := c.Processed(context.Background(), "a.txt", "etag")
+	require.NoError(t, err)
+	assert.False(t, processed)
+	require.NoError(t, c.Record(context.Background(), "a.txt", "etag"))
+}
+
+func TestCheckpointMissingCache(t *testing.T) {
+	pConf, err := service.NewConfigSpec().Field(CheckpointCacheField()).ParseYAML(`checkpoint_cache: nope`, nil)
+	require.NoError(t, err)
+
+	_, err = CheckpointFromConfig(pConf, "foo", service.MockResources())
+	require.EqualError(t, err, "cache resource nope not found")
+}
+
+func TestCheckpointAck(t *testing.T) {
+	ctx := context.Background()
+
+	pConf, err := service.NewConfigSpec().Field(CheckpointCacheField()).ParseYAML(`checkpoint_cache: foo`, nil)
+	require.NoError(t, err)
+
+	mgr := service.MockResources(service.MockResourcesOptAddCache("foo"))
+	c, err := CheckpointFromConfig(pConf, "bucket", mgr)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	var acked int
+	ack := c.Ack("a.txt", "etag1", func(ctx context.Context, err error) error {
+		acked++
+		return nil
+	})
+
+	require.NoError(t, ack(ctx, errors.New("nope")))
+	processed, err := c.Processed(ctx, "a.txt", "etag1")
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	require.NoError(t, ack(ctx, nil))
+	assert.Equal(t, 2, acked)
+
+	processed, err = c.Processed(ctx, "a.txt", "etag1")
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	processed, err = c.Processed(ctx, "a.txt", "etag2")
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	processed, err = c.Processed(ctx, "b.txt", "etag1")
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
@@ -0,0 +1,189 @@
+package objstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// Compression is an algorithm used to compress the bodies of objects.
+type Compression string
+
+// Compression variants.
+const (
+	CompressionNone   Compression = "none"
+	CompressionGzip   Compression = "gzip"
+	CompressionZstd   Compression = "zstd"
+	CompressionSnappy Compression = "snappy"
+)
+
+// CompressionField returns a config field spec for the compression algorithm
+// applied to the bodies of objects.
+func CompressionField() *service.ConfigField {
+	return service.NewStringAnnotatedEnumField("compression", map[string]string{
+		string(CompressionNone):   "No compression is applied.",
+		string(CompressionGzip):   "Objects are gzip compressed with the extension `.gz`.",
+		string(CompressionZstd):   "Objects are zstd compressed with the extension `.zst`.",
+		string(CompressionSnappy): "Objects are snappy compressed with the extension `.snappy`.",
+	}).Description("A compression algorithm applied to the bodies of objects. Outputs compress each message before upload and append the extension of the algorithm to the object key, and inputs decompress each object once downloaded.").
+		Advanced().
+		Default(string(CompressionNone))
+}
+
+// CompressionFromConfig returns the compression algorithm of a parsed config
+// containing the field CompressionField.
+func CompressionFromConfig(conf *service.ParsedConfig) (Compression, error) {
+	str, err := conf.FieldString("compression")
+	if err != nil {
+		return "", err
+	}
+	switch c := Compression(str); c {
+	case CompressionNone, CompressionGzip, CompressionZstd, CompressionSnappy:
+		return c, nil
+	}
+	return "", fmt.Errorf("unrecognised compression algorithm: %v", str)
+}
+
+// Extension returns the file extension associated with the algorithm, or an
+// empty string when no compression is applied.
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	case CompressionSnappy:
+		return ".snappy"
+	}
+	return ""
+}
+
+// ContentEncoding returns the value of the Content-Encoding header that
+// describes the algorithm, or an empty string when no compression is applied.
+func (c Compression) ContentEncoding() string {
+	switch c {
+	case CompressionGzip, CompressionZstd, CompressionSnappy:
+		return string(c)
+	}
+	return ""
+}
+
+// CompressionFromContentEncoding returns the compression algorithm described by
+// the value of a Content-Encoding header, and false if the encoding is not
+// recognised.
+func CompressionFromContentEncoding(encoding string) (Compression, bool) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		return CompressionGzip, true
+	case "zstd":
+		return CompressionZstd, true
+	case "snappy", "x-snappy":
+		return CompressionSnappy, true
+	}
+	return CompressionNone, false
+}
+
+// SkipPrecompressedField returns a config field spec for whether bodies that
+// are already compressed are written without compression.
+func SkipPrecompressedField() *service.ConfigField {
+	return service.NewBoolField("skip_precompressed").
+		Description("Whether to write messages that are detected as already being compressed, such as gzip, zstd, zip, and common image, audio and video formats, without compressing them again. These objects are written without the extension and content encoding of the compression algorithm.").
+		Advanced().
+		Default(false)
+}
+
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// IsPrecompressed returns true if a body appears to already be compressed,
+// based on the signature of common compressed formats.
+func IsPrecompressed(body []byte) bool {
+	if bytes.HasPrefix(body, zstdMagic) {
+		return true
+	}
+	contentType := http.DetectContentType(body)
+	switch contentType {
+	case "application/x-gzip", "application/zip", "application/x-rar-compressed":
+		return true
+	}
+	for _, prefix := range []string{"image/", "audio/", "video/"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func zstdCodecs() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// Compress returns the compressed form of a body. Empty bodies are compressed
+// into a valid empty stream of the algorithm.
+func (c Compression) Compress(body []byte) ([]byte, error) {
+	switch c {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(body); err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		enc, _, err := zstdCodecs()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(body, nil), nil
+	case CompressionSnappy:
+		return snappy.Encode(nil, body), nil
+	}
+	return body, nil
+}
+
+// Decompress returns the decompressed form of a body.
+func (c Compression) Decompress(body []byte) ([]byte, error) {
+	switch c {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case CompressionZstd:
+		_, dec, err := zstdCodecs()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(body, nil)
+	case CompressionSnappy:
+		return snappy.Decode(nil, body)
+	}
+	return body, nil
+}
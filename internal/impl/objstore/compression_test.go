@@ -0,0 +1,65 @@
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZstd, CompressionSnappy} {
+		for _, body := range []string{"", "hello world", "hello world hello world hello world"} {
+			compressed, err := c.Compress([]byte(body))
+			require.NoError(t, err, c)
+			if c != CompressionNone && body != "" {
+				assert.NotEmpty(t, compressed, c)
+			}
+
+			decompressed, err := c.Decompress(compressed)
+			require.NoError(t, err, c)
+			assert.Equal(t, body, string(decompressed), c)
+		}
+	}
+}
+
+func TestCompressionDecompressCorrupt(t *testing.T) {
+	for _, c := range []Compression{CompressionGzip, CompressionZstd, CompressionSnappy} {
+		_, err := c.Decompress([]byte("not compressed"))
+		assert.Error(t, err, c)
+	}
+}
+
+func TestCompressionContentEncoding(t *testing.T) {
+	assert.Equal(t, "", CompressionNone.ContentEncoding())
+
+	for _, c := range []Compression{CompressionGzip, CompressionZstd, CompressionSnappy} {
+		parsed, ok := CompressionFromContentEncoding(c.ContentEncoding())
+		require.True(t, ok, c)
+		assert.Equal(t, c, parsed)
+	}
+
+	parsed, ok := CompressionFromContentEncoding(" X-GZIP ")
+	require.True(t, ok)
+	assert.Equal(t, CompressionGzip, parsed)
+
+	_, ok = CompressionFromContentEncoding("br")
+	assert.False(t, ok)
+
+	_, ok = CompressionFromContentEncoding("")
+	assert.False(t, ok)
+}
+
+func TestIsPrecompressed(t *testing.T) {
+	for _, c := range []Compression{CompressionGzip, CompressionZstd} {
+		compressed, err := c.Compress([]byte("hello world"))
+		require.NoError(t, err)
+		assert.True(t, IsPrecompressed(compressed), c)
+	}
+
+	assert.True(t, IsPrecompressed([]byte("\x89PNG\x0D\x0A\x1A\x0A")))
+	assert.True(t, IsPrecompressed([]byte("PK\x03\x04")))
+	assert.False(t, IsPrecompressed([]byte("hello world")))
+	assert.False(t, IsPrecompressed([]byte(`{"hello":"world"}`)))
+	assert.False(t, IsPrecompressed(nil))
+}
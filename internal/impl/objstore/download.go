@@ -0,0 +1,165 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// DownloadThreadsField returns a config field spec for the number of objects
+// that an input downloads concurrently.
+func DownloadThreadsField() *service.ConfigField {
+	return service.NewIntField("download_threads").
+		Description("The maximum number of objects to download concurrently. When greater than one, objects are downloaded ahead of being consumed and each is acknowledged independently, therefore the order in which objects are processed is best-effort.").
+		Advanced().
+		Default(1)
+}
+
+// maxDownloadAttempts is the maximum number of times that a download is
+// attempted before its object is skipped.
+const maxDownloadAttempts = 3
+
+// DownloadFunc downloads an object and returns it as a message along with the
+// function used to acknowledge it.
+type DownloadFunc func(ctx context.Context) (*service.Message, service.AckFunc, error)
+
+type downloadResult struct {
+	msg *service.Message
+	ack service.AckFunc
+	err error
+}
+
+type download struct {
+	fn       DownloadFunc
+	attempts int
+	res      chan downloadResult
+}
+
+// permanentError wraps an error of a download that would recur if the download
+// were retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// PermanentError marks an error returned by a download as one that would recur
+// if the download were retried, such as an object that cannot be decoded, in
+// which case the download is not retried.
+func PermanentError(err error) error {
+	return &permanentError{err: err}
+}
+
+// Downloads runs a bounded number of object downloads concurrently, and yields
+// their results in the order that they were started. Downloads that fail with
+// an error that is not permanent are kept so that they can be restarted with
+// Retry, which prevents an object from being skipped when its download fails
+// transiently. Downloads are not safe for concurrent use, and are expected to
+// be guarded by the mutex of an input.
+type Downloads struct {
+	threads int
+
+	ctx      context.Context
+	cancel   func()
+	inFlight []download
+	failed   []download
+}
+
+// DownloadsFromConfig creates Downloads from a parsed config containing the
+// field DownloadThreadsField.
+func DownloadsFromConfig(conf *service.ParsedConfig) (*Downloads, error) {
+	threads, err := conf.FieldInt("download_threads")
+	if err != nil {
+		return nil, err
+	}
+	if threads < 1 {
+		return nil, errors.New("download_threads must be at least 1")
+	}
+	return &Downloads{threads: threads}, nil
+}
+
+// Full returns true when the maximum number of downloads are in flight.
+func (d *Downloads) Full() bool {
+	return len(d.inFlight) >= d.threads
+}
+
+// Len returns the number of downloads that are in flight, including those that
+// have finished but have not yet been consumed with Next.
+func (d *Downloads) Len() int {
+	return len(d.inFlight)
+}
+
+// Start begins a download in the background. The context provided to the
+// download is cancelled when Reset is called.
+func (d *Downloads) Start(fn DownloadFunc) {
+	d.start(fn, 1)
+}
+
+func (d *Downloads) start(fn DownloadFunc, attempt int) {
+	if d.ctx == nil {
+		d.ctx, d.cancel = context.WithCancel(context.Background())
+	}
+	ctx, resChan := d.ctx, make(chan downloadResult, 1)
+	d.inFlight = append(d.inFlight, download{fn: fn, attempts: attempt, res: resChan})
+	go func() {
+		msg, ack, err := fn(ctx)
+		resChan <- downloadResult{msg: msg, ack: ack, err: err}
+	}()
+}
+
+// Retry restarts the oldest download that has failed, and returns false when
+// there are none. Inputs should retry failed downloads before starting new
+// ones.
+func (d *Downloads) Retry() bool {
+	if len(d.failed) == 0 {
+		return false
+	}
+	failed := d.failed[0]
+	d.failed[0] = download{}
+	d.failed = d.failed[1:]
+	d.start(failed.fn, failed.attempts+1)
+	return true
+}
+
+// Next waits for the oldest download in flight to finish and returns its
+// result. When the context is cancelled before the download finishes the
+// download remains in flight and is returned by a subsequent call. When the
+// download fails it is kept for a subsequent call to Retry, unless the error is
+// permanent or the download has already been attempted the maximum number of
+// times, in which case the object is skipped.
+func (d *Downloads) Next(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	if len(d.inFlight) == 0 {
+		return nil, nil, errors.New("no downloads in flight")
+	}
+	select {
+	case res := <-d.inFlight[0].res:
+		var pErr *permanentError
+		if res.err != nil && !errors.As(res.err, &pErr) && d.inFlight[0].attempts < maxDownloadAttempts {
+			d.failed = append(d.failed, d.inFlight[0])
+		}
+		d.inFlight[0] = download{}
+		d.inFlight = d.inFlight[1:]
+		return res.msg, res.ack, res.err
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// Reset cancels all downloads in flight and discards their results along with
+// any failed downloads, which is safe as objects are only removed or
+// checkpointed once acknowledged.
+func (d *Downloads) Reset() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.ctx, d.cancel = nil, nil
+	d.inFlight = nil
+	d.failed = nil
+}
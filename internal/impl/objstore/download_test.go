@@ -0,0 +1,163 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testDownloads(t *testing.T, conf string) *Downloads {
+	t.Helper()
+
+	pConf, err := service.NewConfigSpec().Field(DownloadThreadsField()).ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	d, err := DownloadsFromConfig(pConf)
+	require.NoError(t, err)
+	return d
+}
+
+func TestDownloadsOrdered(t *testing.T) {
+	d := testDownloads(t, `download_threads: 3`)
+
+	release := make(chan struct{})
+	for _, body := range []string{"foo", "bar", "baz"} {
+		body := body
+		d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+			if body == "foo" {
+				<-release
+			}
+			return service.NewMessage([]byte(body)), nil, nil
+		})
+	}
+	assert.True(t, d.Full())
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer done()
+	_, _, err := d.Next(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 3, d.Len())
+
+	close(release)
+	for _, exp := range []string{"foo", "bar", "baz"} {
+		msg, _, err := d.Next(context.Background())
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(body))
+	}
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestDownloadsReset(t *testing.T) {
+	d := testDownloads(t, `{}`)
+
+	cancelled := make(chan error, 1)
+	d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return nil, nil, ctx.Err()
+	})
+	assert.True(t, d.Full())
+
+	d.Reset()
+	assert.Equal(t, 0, d.Len())
+
+	select {
+	case err := <-cancelled:
+		assert.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(time.Second):
+		t.Fatal("download was not cancelled")
+	}
+
+	d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+		return nil, nil, ctx.Err()
+	})
+	_, _, err := d.Next(context.Background())
+	require.NoError(t, err)
+}
+
+func TestDownloadsRetry(t *testing.T) {
+	d := testDownloads(t, `{}`)
+	assert.False(t, d.Retry())
+
+	var attempts int
+	d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+		if attempts++; attempts == 1 {
+			return nil, nil, errors.New("nope")
+		}
+		return service.NewMessage([]byte("foo")), nil, nil
+	})
+
+	_, _, err := d.Next(context.Background())
+	require.EqualError(t, err, "nope")
+	assert.Equal(t, 0, d.Len())
+
+	require.True(t, d.Retry())
+	assert.False(t, d.Retry())
+
+	msg, _, err := d.Next(context.Background())
+	require.NoError(t, err)
+
+	body, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(body))
+	assert.Equal(t, 2, attempts)
+	assert.False(t, d.Retry())
+
+	d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+		return nil, nil, errors.New("nope")
+	})
+	_, _, err = d.Next(context.Background())
+	require.Error(t, err)
+
+	d.Reset()
+	assert.False(t, d.Retry())
+}
+
+func TestDownloadsRetryLimit(t *testing.T) {
+	d := testDownloads(t, `{}`)
+
+	var attempts int
+	d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+		attempts++
+		return nil, nil, errors.New("nope")
+	})
+
+	for i := 0; i < maxDownloadAttempts; i++ {
+		if i > 0 {
+			require.True(t, d.Retry())
+		}
+		_, _, err := d.Next(context.Background())
+		require.EqualError(t, err, "nope")
+	}
+	assert.False(t, d.Retry())
+	assert.Equal(t, maxDownloadAttempts, attempts)
+}
+
+func TestDownloadsPermanentError(t *testing.T) {
+	d := testDownloads(t, `{}`)
+
+	d.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+		return nil, nil, PermanentError(errors.New("nope"))
+	})
+
+	_, _, err := d.Next(context.Background())
+	require.EqualError(t, err, "nope")
+	assert.False(t, d.Retry())
+}
+
+func TestDownloadsBadThreads(t *testing.T) {
+	pConf, err := service.NewConfigSpec().Field(DownloadThreadsField()).ParseYAML(`download_threads: 0`, nil)
+	require.NoError(t, err)
+
+	_, err = DownloadsFromConfig(pConf)
+	require.EqualError(t, err, "download_threads must be at least 1")
+}
@@ -0,0 +1,60 @@
+package objstore
+
+import (
+	"net/url"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// FilenameUserMetadataKey is the key of the object user metadata that the
+// original filename of an object is stored under.
+const FilenameUserMetadataKey = "original-filename"
+
+// FilenameMetadataField returns a config field spec for a message metadata key
+// that holds the original filename of objects.
+func FilenameMetadataField() *service.ConfigField {
+	return service.NewStringField("filename_metadata").
+		Description("An optional metadata key of messages that holds the original filename of each object. When set, outputs store the value of this key with each object as the user metadata `" + FilenameUserMetadataKey + "`, and inputs restore the user metadata `" + FilenameUserMetadataKey + "` of each object into this key, allowing filenames to be preserved when archiving files within a bucket. Characters of filenames that are not valid within a URL path segment, such as spaces and non-ASCII characters, are stored percent-encoded.").
+		Example("original_filename").
+		Advanced().
+		Default("")
+}
+
+// Filename copies the original filename of objects between message metadata
+// and object user metadata.
+type Filename struct {
+	metaKey string
+}
+
+// FilenameFromConfig creates a Filename from a parsed config containing the
+// field FilenameMetadataField.
+func FilenameFromConfig(conf *service.ParsedConfig) (f Filename, err error) {
+	f.metaKey, err = conf.FieldString("filename_metadata")
+	return
+}
+
+// UserMetadata returns the encoded original filename of the object of a
+// message, or false when the field is disabled or the message has no filename.
+func (f Filename) UserMetadata(msg *service.Message) (string, bool) {
+	if f.metaKey == "" {
+		return "", false
+	}
+	name, _ := msg.MetaGet(f.metaKey)
+	if name == "" {
+		return "", false
+	}
+	return url.PathEscape(name), true
+}
+
+// SetMessageMetadata decodes the original filename of an object from its user
+// metadata value and sets it as metadata of a message. Empty values, and all
+// values when the field is disabled, are ignored.
+func (f Filename) SetMessageMetadata(msg *service.Message, value string) {
+	if f.metaKey == "" || value == "" {
+		return
+	}
+	if name, err := url.PathUnescape(value); err == nil {
+		value = name
+	}
+	msg.MetaSetMut(f.metaKey, value)
+}
@@ -0,0 +1,60 @@
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testFilename(t *testing.T, conf string) Filename {
+	t.Helper()
+
+	pConf, err := service.NewConfigSpec().Field(FilenameMetadataField()).ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	f, err := FilenameFromConfig(pConf)
+	require.NoError(t, err)
+	return f
+}
+
+func TestFilenameUnset(t *testing.T) {
+	f := testFilename(t, `{}`)
+
+	msg := service.NewMessage([]byte("hello"))
+	msg.MetaSet("original_filename", "foo.txt")
+
+	_, ok := f.UserMetadata(msg)
+	assert.False(t, ok)
+
+	msg = service.NewMessage([]byte("hello"))
+	f.SetMessageMetadata(msg, "foo.txt")
+	_, exists := msg.MetaGet("original_filename")
+	assert.False(t, exists)
+}
+
+func TestFilenameRoundTrip(t *testing.T) {
+	f := testFilename(t, `filename_metadata: original_filename`)
+
+	_, ok := f.UserMetadata(service.NewMessage([]byte("hello")))
+	assert.False(t, ok)
+
+	for _, name := range []string{"foo.txt", "my report.csv", "données/été.json"} {
+		msg := service.NewMessage([]byte("hello"))
+		msg.MetaSet("original_filename", name)
+
+		value, ok := f.UserMetadata(msg)
+		require.True(t, ok)
+		for _, r := range value {
+			assert.Less(t, r, rune(0x80), name)
+			assert.NotEqual(t, ' ', r, name)
+		}
+
+		restored := service.NewMessage(nil)
+		f.SetMessageMetadata(restored, value)
+		v, _ := restored.MetaGet("original_filename")
+		assert.Equal(t, name, v)
+	}
+}
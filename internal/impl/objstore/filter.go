@@ -0,0 +1,61 @@
+package objstore
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// KeySuffixField returns a config field spec for a suffix that the keys of
+// listed objects must have in order to be consumed.
+func KeySuffixField() *service.ConfigField {
+	return service.NewStringField("suffix").
+		Description("An optional suffix, if set only objects with keys ending with the suffix are consumed. Objects are filtered after being listed and before being downloaded.").
+		Example(".json").
+		Default("")
+}
+
+// KeyRegexField returns a config field spec for a regular expression that the
+// keys of listed objects must match in order to be consumed.
+func KeyRegexField() *service.ConfigField {
+	return service.NewStringField("key_regex").
+		Description("An optional regular expression, if set only objects with keys that match the expression are consumed. Objects are filtered after being listed and before being downloaded.").
+		Example(`^logs/[0-9]{4}-[0-9]{2}-[0-9]{2}/`).
+		Advanced().
+		Default("")
+}
+
+// KeyFilter determines which listed objects are consumed by an input by their
+// keys.
+type KeyFilter struct {
+	suffix string
+	regex  *regexp.Regexp
+}
+
+// KeyFilterFromConfig creates a KeyFilter from a parsed config containing the
+// fields KeySuffixField and KeyRegexField.
+func KeyFilterFromConfig(conf *service.ParsedConfig) (f KeyFilter, err error) {
+	if f.suffix, err = conf.FieldString("suffix"); err != nil {
+		return
+	}
+	var expr string
+	if expr, err = conf.FieldString("key_regex"); err != nil {
+		return
+	}
+	if expr != "" {
+		if f.regex, err = regexp.Compile(expr); err != nil {
+			err = fmt.Errorf("failed to compile key_regex: %w", err)
+		}
+	}
+	return
+}
+
+// Matches returns true if an object with the provided key should be consumed.
+func (f KeyFilter) Matches(key string) bool {
+	if !strings.HasSuffix(key, f.suffix) {
+		return false
+	}
+	return f.regex == nil || f.regex.MatchString(key)
+}
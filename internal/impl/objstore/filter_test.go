@@ -0,0 +1,76 @@
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testKeyFilter(t *testing.T, conf string) (KeyFilter, error) {
+	t.Helper()
+
+	spec := service.NewConfigSpec().Field(KeySuffixField()).Field(KeyRegexField())
+	pConf, err := spec.ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	return KeyFilterFromConfig(pConf)
+}
+
+func TestKeyFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    string
+		matches []string
+		skips   []string
+	}{
+		{
+			name:    "no filter",
+			conf:    `{}`,
+			matches: []string{"foo.json", "bar/baz.txt", ""},
+		},
+		{
+			name:    "suffix",
+			conf:    `suffix: .json`,
+			matches: []string{"foo.json", "bar/baz.json"},
+			skips:   []string{"foo.txt", "foo.json.gz"},
+		},
+		{
+			name:    "regex",
+			conf:    `key_regex: ^logs/[0-9]+/`,
+			matches: []string{"logs/2023/a.txt"},
+			skips:   []string{"logs/foo/a.txt", "other/logs/2023/a.txt"},
+		},
+		{
+			name: "suffix and regex",
+			conf: `
+suffix: .json
+key_regex: ^logs/
+`,
+			matches: []string{"logs/a.json"},
+			skips:   []string{"logs/a.txt", "other/a.json"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			f, err := testKeyFilter(t, test.conf)
+			require.NoError(t, err)
+			for _, k := range test.matches {
+				assert.True(t, f.Matches(k), k)
+			}
+			for _, k := range test.skips {
+				assert.False(t, f.Matches(k), k)
+			}
+		})
+	}
+}
+
+func TestKeyFilterBadRegex(t *testing.T) {
+	_, err := testKeyFilter(t, `key_regex: '^foo('`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to compile key_regex")
+}
@@ -0,0 +1,82 @@
+package objstore
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// CacheControlField returns a config field spec for the Cache-Control header
+// of each object.
+func CacheControlField() *service.ConfigField {
+	return service.NewInterpolatedStringField("cache_control").
+		Description("The `Cache-Control` header to set for each object, which determines how clients and CDNs cache the object.").
+		Example("max-age=3600").
+		Example(`${! meta("cache_policy") }`).
+		Advanced().
+		Optional()
+}
+
+// ExpiresField returns a config field spec for the Expires header of each
+// object.
+func ExpiresField() *service.ConfigField {
+	return service.NewInterpolatedStringField("expires").
+		Description("The `Expires` header to set for each object, after which clients and CDNs consider the object stale. The value must be either an HTTP date or an RFC 3339 timestamp.").
+		Example("Wed, 21 Oct 2026 07:28:00 GMT").
+		Example(`${! (timestamp_unix() + 86400).ts_format("Mon, 02 Jan 2006 15:04:05 GMT", "UTC") }`).
+		Advanced().
+		Optional()
+}
+
+// Headers resolves the caching headers of the object of each message.
+type Headers struct {
+	cacheControl *service.InterpolatedString
+	expires      *service.InterpolatedString
+}
+
+// HeadersFromConfig creates Headers from a parsed config optionally containing
+// the fields CacheControlField and ExpiresField.
+func HeadersFromConfig(conf *service.ParsedConfig) (h Headers, err error) {
+	if conf.Contains("cache_control") {
+		if h.cacheControl, err = conf.FieldInterpolatedString("cache_control"); err != nil {
+			return
+		}
+	}
+	if conf.Contains("expires") {
+		if h.expires, err = conf.FieldInterpolatedString("expires"); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// CacheControl returns the Cache-Control header of the object of a message,
+// or an empty string when none is set.
+func (h Headers) CacheControl(msg *service.Message) string {
+	if h.cacheControl == nil {
+		return ""
+	}
+	return h.cacheControl.String(msg)
+}
+
+// Expires returns the expiry time of the object of a message, or the zero time
+// when none is set. An error is returned when the header cannot be parsed.
+func (h Headers) Expires(msg *service.Message) (time.Time, error) {
+	if h.expires == nil {
+		return time.Time{}, nil
+	}
+	str := h.expires.String(msg)
+	if str == "" {
+		return time.Time{}, nil
+	}
+	if t, err := http.ParseTime(str); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expires value %v is neither an HTTP date nor an RFC 3339 timestamp", str)
+	}
+	return t, nil
+}
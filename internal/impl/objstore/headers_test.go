@@ -0,0 +1,80 @@
+package objstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testHeaders(t *testing.T, conf string) Headers {
+	t.Helper()
+
+	spec := service.NewConfigSpec().
+		Field(CacheControlField()).
+		Field(ExpiresField())
+
+	pConf, err := spec.ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	h, err := HeadersFromConfig(pConf)
+	require.NoError(t, err)
+	return h
+}
+
+func TestHeadersUnset(t *testing.T) {
+	h := testHeaders(t, `{}`)
+	msg := service.NewMessage([]byte("hello"))
+
+	assert.Equal(t, "", h.CacheControl(msg))
+
+	expires, err := h.Expires(msg)
+	require.NoError(t, err)
+	assert.True(t, expires.IsZero())
+}
+
+func TestHeadersInterpolated(t *testing.T) {
+	h := testHeaders(t, `
+cache_control: max-age=${! meta("age") }
+expires: ${! meta("expires").or("") }
+`)
+
+	expected := time.Date(2026, 10, 21, 7, 28, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		expires     string
+		errContains string
+	}{
+		{name: "http date", expires: "Wed, 21 Oct 2026 07:28:00 GMT"},
+		{name: "rfc3339", expires: "2026-10-21T09:28:00+02:00"},
+		{name: "invalid", expires: "tomorrow", errContains: "neither an HTTP date nor an RFC 3339 timestamp"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			msg := service.NewMessage([]byte("hello"))
+			msg.MetaSet("age", "60")
+			msg.MetaSet("expires", test.expires)
+
+			assert.Equal(t, "max-age=60", h.CacheControl(msg))
+
+			expires, err := h.Expires(msg)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, expected.Equal(expires), expires)
+		})
+	}
+
+	expires, err := h.Expires(service.NewMessage(nil))
+	require.NoError(t, err)
+	assert.True(t, expires.IsZero())
+}
@@ -0,0 +1,99 @@
+package objstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// SerializeKeyWritesField returns a config field spec for whether writes to
+// the same object key are serialized.
+func SerializeKeyWritesField() *service.ConfigField {
+	return service.NewBoolField("serialize_key_writes").
+		Description("Whether writes of messages that resolve to the same object key are serialized across batches that are written in parallel, in which case they are written in the order that their batches were sent to the output. Messages of the same batch that resolve to the same key are always written in order, and writes of different keys are performed in parallel up to `max_in_flight`.").
+		Advanced().
+		Default(false)
+}
+
+// keyTurn is a turn to hold the lock of a key, which is reserved with a
+// keyedMutex.
+type keyTurn struct {
+	k    *keyedMutex
+	key  string
+	prev <-chan struct{}
+	done chan struct{}
+}
+
+// Lock blocks until the turns of the key that were reserved before this one
+// have ended, or the context is cancelled.
+func (t *keyTurn) Lock(ctx context.Context) error {
+	if t.prev == nil {
+		return nil
+	}
+	select {
+	case <-t.prev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock ends the turn, and must be called for every reserved turn regardless
+// of whether the lock was acquired. When the turns reserved before this one
+// have not yet ended the lock is passed on once they have.
+func (t *keyTurn) Unlock() {
+	if t.prev != nil {
+		select {
+		case <-t.prev:
+		default:
+			go func() {
+				<-t.prev
+				t.release()
+			}()
+			return
+		}
+	}
+	t.release()
+}
+
+func (t *keyTurn) release() {
+	t.k.mut.Lock()
+	if t.k.tails[t.key] == t {
+		delete(t.k.tails, t.key)
+	}
+	t.k.mut.Unlock()
+	close(t.done)
+}
+
+// keyedMutex provides a mutual exclusion lock per key, which is granted in the
+// order that turns to hold it were reserved. Locks are released from memory
+// once no longer held or reserved.
+type keyedMutex struct {
+	mut   sync.Mutex
+	tails map[string]*keyTurn
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{tails: map[string]*keyTurn{}}
+}
+
+// Reserve reserves a turn to hold the lock of each key in order. The turns of a
+// call are reserved atomically and are therefore never interleaved with those
+// of another call, which means that turns reserved by a call only ever wait on
+// those of earlier calls and of preceding keys of the same call.
+func (k *keyedMutex) Reserve(keys ...string) []*keyTurn {
+	k.mut.Lock()
+	defer k.mut.Unlock()
+
+	turns := make([]*keyTurn, len(keys))
+	for i, key := range keys {
+		t := &keyTurn{k: k, key: key, done: make(chan struct{})}
+		if prev, exists := k.tails[key]; exists {
+			t.prev = prev.done
+		}
+		k.tails[key] = t
+		turns[i] = t
+	}
+	return turns
+}
@@ -0,0 +1,130 @@
+package objstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedMutexSameKey(t *testing.T) {
+	k := newKeyedMutex()
+
+	var active, maxActive int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			turn := k.Reserve("foo")[0]
+			defer turn.Unlock()
+			require.NoError(t, turn.Lock(context.Background()))
+			n := atomic.AddInt32(&active, 1)
+			for {
+				m := atomic.LoadInt32(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), maxActive)
+	assert.Empty(t, k.tails)
+}
+
+func TestKeyedMutexDifferentKeys(t *testing.T) {
+	k := newKeyedMutex()
+
+	turns := k.Reserve("foo", "bar")
+	require.NoError(t, turns[0].Lock(context.Background()))
+
+	locked := make(chan struct{})
+	go func() {
+		assert.NoError(t, turns[1].Lock(context.Background()))
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for lock of a different key")
+	}
+
+	turns[1].Unlock()
+	turns[0].Unlock()
+	assert.Empty(t, k.tails)
+}
+
+func TestKeyedMutexOrder(t *testing.T) {
+	k := newKeyedMutex()
+
+	turns := k.Reserve("foo", "foo", "foo")
+
+	var mut sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := len(turns) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer turns[i].Unlock()
+			require.NoError(t, turns[i].Lock(context.Background()))
+			mut.Lock()
+			order = append(order, i)
+			mut.Unlock()
+		}(i)
+		time.Sleep(time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{0, 1, 2}, order)
+	assert.Empty(t, k.tails)
+}
+
+func TestKeyedMutexCancelledTurn(t *testing.T) {
+	k := newKeyedMutex()
+
+	turns := k.Reserve("foo", "foo", "foo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, turns[1].Lock(ctx))
+	turns[1].Unlock()
+
+	// The lock is only passed on once every earlier turn has ended, including
+	// those that ended without acquiring it.
+	locked := make(chan struct{})
+	go func() {
+		assert.NoError(t, turns[2].Lock(context.Background()))
+		close(locked)
+	}()
+
+	require.NoError(t, turns[0].Lock(context.Background()))
+	select {
+	case <-locked:
+		t.Fatal("lock acquired out of turn")
+	case <-time.After(time.Millisecond * 10):
+	}
+	turns[0].Unlock()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for lock")
+	}
+	turns[2].Unlock()
+
+	require.Eventually(t, func() bool {
+		k.mut.Lock()
+		defer k.mut.Unlock()
+		return len(k.tails) == 0
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,47 @@
+package objstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// Metrics records the throughput, latency and errors of object uploads labelled
+// by bucket.
+type Metrics struct {
+	uploadedBytes  *service.MetricCounter
+	objectsWritten *service.MetricCounter
+	uploadErrors   *service.MetricCounter
+	uploadLatency  *service.MetricTimer
+}
+
+// NewMetrics creates the object upload metrics of an output.
+func NewMetrics(m *service.Metrics) *Metrics {
+	return &Metrics{
+		uploadedBytes:  m.NewCounter("object_storage_uploaded_bytes", "bucket"),
+		objectsWritten: m.NewCounter("object_storage_objects_written", "bucket"),
+		uploadErrors:   m.NewCounter("object_storage_upload_errors", "bucket"),
+		uploadLatency:  m.NewTimer("object_storage_upload_latency_ns", "bucket"),
+	}
+}
+
+// Wrap returns a PutObjectFunc that records metrics around each call of the
+// provided PutObjectFunc, where the bucket label of each object is obtained
+// from the provided closure.
+func (m *Metrics) Wrap(put PutObjectFunc, bucket func(msg *service.Message) string) PutObjectFunc {
+	return func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error {
+		b := bucket(msg)
+
+		t0 := time.Now()
+		err := put(ctx, msg, key, body, compression)
+		m.uploadLatency.Timing(time.Since(t0).Nanoseconds(), b)
+		if err != nil {
+			m.uploadErrors.Incr(1, b)
+			return err
+		}
+		m.objectsWritten.Incr(1, b)
+		m.uploadedBytes.Incr(int64(len(body)), b)
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestMetricsWrap(t *testing.T) {
+	local := metrics.NewLocal()
+	res := service.MockResources(func(m *mock.Manager) {
+		m.M = local
+	})
+
+	m := NewMetrics(res.Metrics())
+	put := m.Wrap(func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error {
+		if key == "bad" {
+			return errors.New("nope")
+		}
+		return nil
+	}, func(msg *service.Message) string {
+		b, _ := msg.MetaGet("bucket")
+		return b
+	})
+
+	msgFoo := service.NewMessage(nil)
+	msgFoo.MetaSet("bucket", "foo")
+	msgBar := service.NewMessage(nil)
+	msgBar.MetaSet("bucket", "bar")
+
+	require.NoError(t, put(context.Background(), msgFoo, "a", []byte("hello"), CompressionNone))
+	require.NoError(t, put(context.Background(), msgFoo, "b", []byte("world!"), CompressionNone))
+	require.NoError(t, put(context.Background(), msgBar, "c", []byte("hi"), CompressionNone))
+	require.Error(t, put(context.Background(), msgBar, "bad", []byte("hi"), CompressionNone))
+
+	counters := local.GetCounters()
+	assert.Equal(t, int64(2), counters[`object_storage_objects_written{bucket="foo"}`])
+	assert.Equal(t, int64(11), counters[`object_storage_uploaded_bytes{bucket="foo"}`])
+	assert.Equal(t, int64(1), counters[`object_storage_objects_written{bucket="bar"}`])
+	assert.Equal(t, int64(2), counters[`object_storage_uploaded_bytes{bucket="bar"}`])
+	assert.Equal(t, int64(1), counters[`object_storage_upload_errors{bucket="bar"}`])
+	assert.NotContains(t, counters, `object_storage_upload_errors{bucket="foo"}`)
+
+	assert.Contains(t, local.GetTimings(), `object_storage_upload_latency_ns{bucket="foo"}`)
+	assert.Contains(t, local.GetTimings(), `object_storage_upload_latency_ns{bucket="bar"}`)
+}
@@ -0,0 +1,98 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// LocalMirrorField returns a config field spec for a local directory that
+// objects are also written to.
+func LocalMirrorField() *service.ConfigField {
+	return service.NewStringField("local_mirror").
+		Description("An optional directory of the local filesystem that every object is also written to, using the same key as a path relative to the directory. A write only fails when both the object store and the local directory fail, which guarantees a local copy exists even when the object store is briefly unavailable.").
+		Example("/var/lib/benthos/mirror").
+		Advanced().
+		Optional()
+}
+
+// LocalMirror writes objects to a directory of the local filesystem alongside
+// an object store.
+type LocalMirror struct {
+	dir    string
+	fs     *service.FS
+	logger *service.Logger
+}
+
+// LocalMirrorFromConfig creates a LocalMirror from a parsed config optionally
+// containing the field LocalMirrorField, returning nil when it is not set.
+func LocalMirrorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*LocalMirror, error) {
+	if !conf.Contains("local_mirror") {
+		return nil, nil
+	}
+	dir, err := conf.FieldString("local_mirror")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	return &LocalMirror{
+		dir:    filepath.Clean(dir),
+		fs:     mgr.FS(),
+		logger: mgr.Logger(),
+	}, nil
+}
+
+// write writes the body of an object to the path of its key within the mirror
+// directory.
+func (l *LocalMirror) write(key string, body []byte) error {
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(l.dir, path); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("key %v resolves to a path outside of the mirror directory", key)
+	}
+	if err := l.fs.MkdirAll(filepath.Dir(path), fs.FileMode(0o777)); err != nil {
+		return err
+	}
+
+	file, err := l.fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fs.FileMode(0o666))
+	if err != nil {
+		return err
+	}
+	w, ok := file.(io.Writer)
+	if !ok {
+		_ = file.Close()
+		return errors.New("failed to open file for writing")
+	}
+	_, err = w.Write(body)
+	if cErr := file.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// Wrap returns a PutObjectFunc that writes each object with the provided
+// PutObjectFunc as well as to the mirror directory. An error is only returned
+// when both writes fail, and a single failed write is logged instead.
+func (l *LocalMirror) Wrap(put PutObjectFunc) PutObjectFunc {
+	return func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error {
+		putErr := put(ctx, msg, key, body, compression)
+		mirrorErr := l.write(key, body)
+		switch {
+		case putErr != nil && mirrorErr != nil:
+			return fmt.Errorf("%w (local mirror: %v)", putErr, mirrorErr)
+		case putErr != nil:
+			l.logger.Warnf("Failed to write object %v, a copy was written to the local mirror: %v", key, putErr)
+		case mirrorErr != nil:
+			l.logger.Warnf("Failed to write object %v to the local mirror: %v", key, mirrorErr)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testLocalMirror(t *testing.T, dir string) *LocalMirror {
+	t.Helper()
+
+	spec := service.NewConfigSpec().Field(LocalMirrorField())
+	pConf, err := spec.ParseYAML(`local_mirror: `+dir, nil)
+	require.NoError(t, err)
+
+	l, err := LocalMirrorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	return l
+}
+
+func TestLocalMirrorUnset(t *testing.T) {
+	pConf, err := service.NewConfigSpec().Field(LocalMirrorField()).ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+
+	l, err := LocalMirrorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	assert.Nil(t, l)
+}
+
+func TestLocalMirrorWrites(t *testing.T) {
+	dir := t.TempDir()
+	l := testLocalMirror(t, dir)
+
+	remoteErr := errors.New("remote unavailable")
+	var failRemote bool
+	put := l.Wrap(func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error {
+		if failRemote {
+			return remoteErr
+		}
+		return nil
+	})
+
+	msg := service.NewMessage(nil)
+	require.NoError(t, put(context.Background(), msg, "a/b/foo.txt", []byte("foo"), CompressionNone))
+
+	b, err := os.ReadFile(filepath.Join(dir, "a", "b", "foo.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(b))
+
+	// A failed remote write succeeds as long as the mirror succeeds.
+	failRemote = true
+	require.NoError(t, put(context.Background(), msg, "a/b/foo.txt", []byte("bar"), CompressionNone))
+
+	b, err = os.ReadFile(filepath.Join(dir, "a", "b", "foo.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(b))
+
+	// Both destinations failing results in an error.
+	err = put(context.Background(), msg, "../escape.txt", []byte("baz"), CompressionNone)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, remoteErr)
+	assert.Contains(t, err.Error(), "outside of the mirror directory")
+
+	_, err = os.Stat(filepath.Join(filepath.Dir(dir), "escape.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	// A failed mirror write succeeds as long as the remote succeeds.
+	failRemote = false
+	require.NoError(t, put(context.Background(), msg, "../escape.txt", []byte("baz"), CompressionNone))
+}
@@ -0,0 +1,126 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// ManifestSuffix is appended to the key of an object that was split into parts
+// in order to obtain the key of its manifest.
+const ManifestSuffix = ".manifest"
+
+var partKeyRegex = regexp.MustCompile(`\.part-[0-9]{4,}$`)
+
+// MaxObjectSizeField returns a config field spec for the size above which
+// objects are split into parts.
+func MaxObjectSizeField() *service.ConfigField {
+	return service.NewIntField("max_object_size").
+		Description("The maximum size in bytes of each object written, objects that exceed it are split into parts with the keys `<key>.part-0000`, `<key>.part-0001` and so on, followed by a JSON manifest listing the parts with the key `<key>.manifest`. The parts are written after compression, and inputs with the field `reassemble_parts` enabled consume the parts listed by each manifest as a single message. Set to zero in order to disable splitting.").
+		Advanced().
+		Default(0)
+}
+
+// ReassemblePartsField returns a config field spec for whether an input
+// reassembles objects that were split into parts.
+func ReassemblePartsField() *service.ConfigField {
+	return service.NewBoolField("reassemble_parts").
+		Description("Whether to consume objects that were split into parts by an output with the field `max_object_size` as a single message. When enabled each manifest object is replaced by the contents of its parts joined in order, the parts themselves are not consumed individually, and deleting objects also deletes the parts of each manifest.").
+		Advanced().
+		Default(false)
+}
+
+// PartKey returns the key of a part of an object that was split into parts.
+func PartKey(key string, index int) string {
+	return fmt.Sprintf("%v.part-%04d", key, index)
+}
+
+// IsPartKey returns true if a key is that of a part of an object that was
+// split into parts.
+func IsPartKey(key string) bool {
+	return partKeyRegex.MatchString(key)
+}
+
+// IsManifestKey returns true if a key is that of the manifest of an object
+// that was split into parts.
+func IsManifestKey(key string) bool {
+	return strings.HasSuffix(key, ManifestSuffix)
+}
+
+// Manifest lists the parts of an object that was split into parts.
+type Manifest struct {
+	Parts       []string    `json:"parts"`
+	Size        int         `json:"size"`
+	Compression Compression `json:"compression"`
+}
+
+// ParseManifest parses the contents of a manifest object.
+func ParseManifest(data []byte) (m Manifest, err error) {
+	if err = json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(m.Parts) == 0 {
+		return m, fmt.Errorf("manifest lists no parts")
+	}
+	return m, nil
+}
+
+// ObjectCompression returns the compression algorithm of the object described
+// by the manifest, or the provided fallback when the object was written without
+// compression.
+func (m Manifest) ObjectCompression(fallback Compression) Compression {
+	if m.Compression == "" || m.Compression == CompressionNone {
+		return fallback
+	}
+	return m.Compression
+}
+
+// Reassemble downloads each part of the manifest in order with the provided
+// closure and returns their joined contents, which is checked against the size
+// recorded by the manifest.
+func (m Manifest) Reassemble(ctx context.Context, get func(ctx context.Context, key string) ([]byte, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(m.Size)
+	for _, key := range m.Parts {
+		data, err := get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download part %v: %w", key, err)
+		}
+		_, _ = buf.Write(data)
+	}
+	if buf.Len() != m.Size {
+		return nil, fmt.Errorf("reassembled parts are %v bytes but the manifest expects %v", buf.Len(), m.Size)
+	}
+	return buf.Bytes(), nil
+}
+
+// putParts splits the body of an object into parts no larger than the
+// maximum object size and writes each part, followed by a manifest listing
+// them. The parts and manifest are written without compression, and the
+// compression of the body as a whole is recorded by the manifest instead.
+func putParts(ctx context.Context, msg *service.Message, key string, data []byte, compression Compression, maxSize int, put PutObjectFunc) error {
+	m := Manifest{Size: len(data), Compression: compression}
+	for i := 0; len(data) > 0; i++ {
+		n := maxSize
+		if n > len(data) {
+			n = len(data)
+		}
+		partKey := PartKey(key, i)
+		if err := put(ctx, msg, partKey, data[:n], CompressionNone); err != nil {
+			return fmt.Errorf("failed to write part %v: %w", partKey, err)
+		}
+		m.Parts = append(m.Parts, partKey)
+		data = data[n:]
+	}
+
+	manifestBytes, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return put(ctx, msg, key+ManifestSuffix, manifestBytes, CompressionNone)
+}
@@ -0,0 +1,123 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestPartKeys(t *testing.T) {
+	assert.Equal(t, "foo.txt.part-0000", PartKey("foo.txt", 0))
+	assert.Equal(t, "foo.txt.part-0012", PartKey("foo.txt", 12))
+	assert.Equal(t, "foo.txt.part-12345", PartKey("foo.txt", 12345))
+
+	assert.True(t, IsPartKey("foo.txt.part-0000"))
+	assert.True(t, IsPartKey("foo.txt.part-12345"))
+	assert.False(t, IsPartKey("foo.txt"))
+	assert.False(t, IsPartKey("foo.part-1"))
+	assert.False(t, IsPartKey("foo.txt.part-0000.manifest"))
+
+	assert.True(t, IsManifestKey("foo.txt.manifest"))
+	assert.False(t, IsManifestKey("foo.txt"))
+}
+
+func TestWriterWriteBatchSplit(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! meta("name") }.txt
+max_object_size: 4
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("abc")),
+		service.NewMessage([]byte("abcdefghij")),
+	}
+	batch[0].MetaSet("name", "small")
+	batch[1].MetaSet("name", "large")
+
+	written := map[string]string{}
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		written[key] = string(body)
+		return nil
+	}))
+
+	assert.Equal(t, map[string]string{
+		"foo/small.txt":           "abc",
+		"foo/large.txt.part-0000": "abcd",
+		"foo/large.txt.part-0001": "efgh",
+		"foo/large.txt.part-0002": "ij",
+		"foo/large.txt.manifest":  `{"parts":["foo/large.txt.part-0000","foo/large.txt.part-0001","foo/large.txt.part-0002"],"size":10,"compression":"none"}`,
+	}, written)
+
+	m, err := ParseManifest([]byte(written["foo/large.txt.manifest"]))
+	require.NoError(t, err)
+
+	data, err := m.Reassemble(context.Background(), func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(written[key]), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefghij", string(data))
+}
+
+func TestWriterWriteBatchSplitCompressed(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: bar.txt
+compression: gzip
+max_object_size: 8
+`)
+
+	written := map[string][]byte{}
+	compressions := map[string]Compression{}
+	require.NoError(t, w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte("hello world")),
+	}, func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error {
+		written[key] = body
+		compressions[key] = compression
+		return nil
+	}))
+
+	for key, compression := range compressions {
+		assert.Equal(t, CompressionNone, compression, key)
+	}
+
+	m, err := ParseManifest(written["foo/bar.txt.gz.manifest"])
+	require.NoError(t, err)
+	assert.Equal(t, CompressionGzip, m.ObjectCompression(CompressionNone))
+
+	data, err := m.Reassemble(context.Background(), func(ctx context.Context, key string) ([]byte, error) {
+		return written[key], nil
+	})
+	require.NoError(t, err)
+
+	data, err = m.ObjectCompression(CompressionNone).Decompress(data)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
+
+func TestManifestReassembleErrors(t *testing.T) {
+	_, err := ParseManifest([]byte(`{"parts":[]}`))
+	require.Error(t, err)
+
+	_, err = ParseManifest([]byte(`not json`))
+	require.Error(t, err)
+
+	m, err := ParseManifest([]byte(`{"parts":["a","b"],"size":5}`))
+	require.NoError(t, err)
+	assert.Equal(t, CompressionZstd, m.ObjectCompression(CompressionZstd))
+
+	_, err = m.Reassemble(context.Background(), func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("ab"), nil
+	})
+	require.EqualError(t, err, "reassembled parts are 4 bytes but the manifest expects 5")
+
+	_, err = m.Reassemble(context.Background(), func(ctx context.Context, key string) ([]byte, error) {
+		return nil, errors.New("nope")
+	})
+	require.EqualError(t, err, "failed to download part a: nope")
+}
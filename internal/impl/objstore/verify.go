@@ -0,0 +1,14 @@
+package objstore
+
+import (
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// VerifyOnConnectField returns a config field spec for whether an output
+// checks that its bucket is reachable when connecting.
+func VerifyOnConnectField() *service.ConfigField {
+	return service.NewBoolField("verify_on_connect").
+		Description("Whether to check that the bucket exists and is accessible with the configured credentials when connecting, so that a misconfigured endpoint, credentials or bucket fails fast rather than on the first write. Disable this when the service cannot be reached at startup, or the credentials are not permitted to inspect buckets.").
+		Advanced().
+		Default(true)
+}
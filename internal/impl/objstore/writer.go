@@ -0,0 +1,325 @@
+// Package objstore contains utilities shared by the object storage outputs,
+// which each write the messages of a batch as individual objects.
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// DirectoryField returns a config field spec for the directory that objects
+// are stored within.
+func DirectoryField() *service.ConfigField {
+	return service.NewInternalField(docs.FieldInterpolatedString(
+		"directory", "A directory to store message files within. If the directory does not exist it will be created.",
+	).LinterFunc(docs.LintObjectKeyDirectory))
+}
+
+// PathField returns a config field spec for the path of each object, relative
+// to the directory.
+func PathField() *service.ConfigField {
+	return service.NewInternalField(docs.FieldInterpolatedString(
+		"path", "The path of each message to upload.",
+	).LinterFunc(docs.LintObjectKeyPath).LintWhenEmpty())
+}
+
+// FailFastField returns a config field spec for whether a batch fails as a
+// whole on the first failed write.
+func FailFastField() *service.ConfigField {
+	return service.NewBoolField("fail_fast").
+		Description("Whether to abandon writing a batch on the first message that fails to be written, which results in the entire batch being retried. When disabled all messages of a batch are attempted, failed messages are marked with their errors, and only the failed messages are retried.").
+		Advanced().
+		Default(true)
+}
+
+// MaxMessageBytesField returns a config field spec for the size above which
+// messages are rejected rather than written.
+func MaxMessageBytesField() *service.ConfigField {
+	return service.NewIntField("max_message_bytes").
+		Description("The maximum size in bytes of the body of each message, messages that exceed it are not uploaded and fail with an error instead. The size is checked before compression, and when messages are bundled the batch fails when any of its messages exceed it. When `fail_fast` is disabled only the oversized messages fail, which allows them to be routed elsewhere with a [`fallback`](/docs/components/outputs/fallback) output, or dropped with a [`reject`](/docs/components/outputs/reject) output. Set to zero in order to disable the limit.").
+		Advanced().
+		Default(0)
+}
+
+// PutObjectFunc uploads the body of a message as an object with a given key,
+// where the body has been compressed with the given compression algorithm.
+type PutObjectFunc func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error
+
+// Writer computes the object key of each message of a batch from the
+// interpolated directory and path fields, and writes them in parallel with a
+// backend specific PutObjectFunc.
+type Writer struct {
+	directory   *service.InterpolatedString
+	path        *service.InterpolatedString
+	compression Compression
+	skipPrecomp bool
+	bundle      BundleFormat
+	failFast    bool
+	maxSize     int
+	maxMsgBytes int
+	maxInFlight int
+	keyLocks    *keyedMutex
+}
+
+// NewWriterFromConfig creates a Writer from a parsed config containing the
+// fields DirectoryField and PathField, and optionally CompressionField,
+// SkipPrecompressedField, BundleField, FailFastField, MaxObjectSizeField,
+// MaxMessageBytesField and SerializeKeyWritesField, as well as the output field
+// max_in_flight, which bounds the number of messages of a batch that are written
+// in parallel.
+func NewWriterFromConfig(conf *service.ParsedConfig) (w *Writer, err error) {
+	w = &Writer{compression: CompressionNone, bundle: BundleNone, failFast: true, maxInFlight: 1}
+	if w.directory, err = conf.FieldInterpolatedString("directory"); err != nil {
+		return nil, err
+	}
+	if w.path, err = conf.FieldInterpolatedString("path"); err != nil {
+		return nil, err
+	}
+	if conf.Contains("compression") {
+		if w.compression, err = CompressionFromConfig(conf); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains("skip_precompressed") {
+		if w.skipPrecomp, err = conf.FieldBool("skip_precompressed"); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains("bundle") {
+		if w.bundle, err = BundleFormatFromConfig(conf); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains("fail_fast") {
+		if w.failFast, err = conf.FieldBool("fail_fast"); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains("max_object_size") {
+		if w.maxSize, err = conf.FieldInt("max_object_size"); err != nil {
+			return nil, err
+		}
+		if w.maxSize < 0 {
+			return nil, fmt.Errorf("max_object_size must not be negative, got %v", w.maxSize)
+		}
+	}
+	if conf.Contains("max_message_bytes") {
+		if w.maxMsgBytes, err = conf.FieldInt("max_message_bytes"); err != nil {
+			return nil, err
+		}
+		if w.maxMsgBytes < 0 {
+			return nil, fmt.Errorf("max_message_bytes must not be negative, got %v", w.maxMsgBytes)
+		}
+	}
+	if conf.Contains("max_in_flight") {
+		if w.maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
+			return nil, err
+		}
+		if w.maxInFlight < 1 {
+			return nil, fmt.Errorf("max_in_flight must be at least 1, got %v", w.maxInFlight)
+		}
+	}
+	if conf.Contains("serialize_key_writes") {
+		var serialize bool
+		if serialize, err = conf.FieldBool("serialize_key_writes"); err != nil {
+			return nil, err
+		}
+		if serialize {
+			w.keyLocks = newKeyedMutex()
+		}
+	}
+	return
+}
+
+// WriteBatch writes each message of a batch as an object, compressing the body
+// of each message when configured unless it is already compressed and skipping
+// precompressed bodies is enabled, and returns the first error encountered.
+// Messages are written in parallel up to the maximum in flight, where messages
+// that resolve to the same key are written in order. When fail fast is disabled
+// all messages are written regardless of errors, and a service.BatchError is
+// returned indicating which messages failed. When a bundle format is configured
+// the batch is instead written as a single object, which is passed to the
+// PutObjectFunc with the first message. When key writes are serialized the
+// writes of a key are also ordered across parallel calls, in the order that the
+// calls were made. When a maximum object size is configured larger objects are
+// written as parts followed by a manifest. When a maximum message size is
+// configured larger messages fail without being passed to the PutObjectFunc.
+func (w *Writer) WriteBatch(ctx context.Context, batch service.MessageBatch, put PutObjectFunc) error {
+	if w.bundle != BundleNone && len(batch) > 0 {
+		return w.writeBundle(ctx, batch, put)
+	}
+
+	// Objects are prepared up front so that the turns of their keys can be
+	// reserved in the order of the batch before any are written.
+	objects := make([]object, len(batch))
+	errs := make([]error, len(batch))
+	var keys []string
+	for i, msg := range batch {
+		if objects[i], errs[i] = w.prepareObject(msg); errs[i] != nil {
+			if w.failFast {
+				return errs[i]
+			}
+			continue
+		}
+		keys = append(keys, objects[i].key)
+	}
+
+	keyLocks := w.keyLocks
+	if keyLocks == nil {
+		keyLocks = newKeyedMutex()
+	}
+	turns := keyLocks.Reserve(keys...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var failOnce sync.Once
+	var failErr error
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < w.maxInFlight && n < len(keys); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if errs[i] = w.writeObject(ctx, batch[i], objects[i], put); errs[i] != nil && w.failFast {
+					failOnce.Do(func() {
+						failErr = errs[i]
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	for i := range batch {
+		if errs[i] == nil {
+			objects[i].turn, turns = turns[0], turns[1:]
+			indexes <- i
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if w.failFast {
+		return failErr
+	}
+
+	var bErr *service.BatchError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		batch[i].SetError(err)
+		if bErr == nil {
+			bErr = service.NewBatchError(batch, err)
+		}
+		bErr = bErr.Failed(i, err)
+	}
+	if bErr != nil {
+		return bErr
+	}
+	return nil
+}
+
+// object is a message body prepared to be written with a given key.
+type object struct {
+	key         string
+	data        []byte
+	compression Compression
+	turn        *keyTurn
+}
+
+func (w *Writer) prepareObject(msg *service.Message) (obj object, err error) {
+	if obj.data, err = msg.AsBytes(); err != nil {
+		return
+	}
+	if err = w.checkMessageSize(obj.data); err != nil {
+		return
+	}
+	obj.compression = w.compression
+	if w.skipPrecomp && IsPrecompressed(obj.data) {
+		obj.compression = CompressionNone
+	}
+	if obj.data, err = obj.compression.Compress(obj.data); err != nil {
+		return
+	}
+	obj.key = JoinKey(w.directory.String(msg), w.path.String(msg)) + obj.compression.Extension()
+	return
+}
+
+// writeObject writes an object once the turn of its key is reached, and ends
+// the turn regardless of the outcome.
+func (w *Writer) writeObject(ctx context.Context, msg *service.Message, obj object, put PutObjectFunc) error {
+	defer obj.turn.Unlock()
+	if err := obj.turn.Lock(ctx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return w.put(ctx, msg, obj.key, obj.data, obj.compression, put)
+}
+
+func (w *Writer) writeBundle(ctx context.Context, batch service.MessageBatch, put PutObjectFunc) error {
+	for _, msg := range batch {
+		data, err := msg.AsBytes()
+		if err != nil {
+			return err
+		}
+		if err := w.checkMessageSize(data); err != nil {
+			return err
+		}
+	}
+	data, err := w.bundle.Bundle(batch)
+	if err != nil {
+		return err
+	}
+	if data, err = w.compression.Compress(data); err != nil {
+		return err
+	}
+	key := JoinKey(batch.InterpolatedString(0, w.directory), batch.InterpolatedString(0, w.path)) + w.compression.Extension()
+	if w.keyLocks == nil {
+		return w.put(ctx, batch[0], key, data, w.compression, put)
+	}
+	return w.writeObject(ctx, batch[0], object{
+		key:         key,
+		data:        data,
+		compression: w.compression,
+		turn:        w.keyLocks.Reserve(key)[0],
+	}, put)
+}
+
+// checkMessageSize returns an error when the body of a message exceeds the
+// maximum message size.
+func (w *Writer) checkMessageSize(data []byte) error {
+	if w.maxMsgBytes > 0 && len(data) > w.maxMsgBytes {
+		return component.ErrMessageExceedsLimit{Size: len(data), Limit: w.maxMsgBytes}
+	}
+	return nil
+}
+
+func (w *Writer) put(ctx context.Context, msg *service.Message, key string, data []byte, compression Compression, put PutObjectFunc) error {
+	if w.maxSize > 0 && len(data) > w.maxSize {
+		return putParts(ctx, msg, key, data, compression, w.maxSize, put)
+	}
+	return put(ctx, msg, key, data, compression)
+}
+
+// JoinKey joins a directory and path into an object key separated by exactly
+// one slash. When either is empty the other is returned unchanged.
+func JoinKey(directory, path string) string {
+	if directory == "" {
+		return path
+	}
+	if path == "" {
+		return directory
+	}
+	return strings.TrimSuffix(directory, "/") + "/" + strings.TrimPrefix(path, "/")
+}
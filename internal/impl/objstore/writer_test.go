@@ -0,0 +1,444 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestJoinKey(t *testing.T) {
+	tests := []struct {
+		directory string
+		path      string
+		expected  string
+	}{
+		{directory: "", path: "foo.txt", expected: "foo.txt"},
+		{directory: "foo", path: "", expected: "foo"},
+		{directory: "foo", path: "bar.txt", expected: "foo/bar.txt"},
+		{directory: "foo/", path: "bar.txt", expected: "foo/bar.txt"},
+		{directory: "foo", path: "/bar.txt", expected: "foo/bar.txt"},
+		{directory: "foo/", path: "/bar.txt", expected: "foo/bar.txt"},
+		{directory: "/a/b/", path: "c/d.txt", expected: "/a/b/c/d.txt"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, JoinKey(test.directory, test.path), "%v + %v", test.directory, test.path)
+	}
+}
+
+func testWriter(t *testing.T, conf string) *Writer {
+	t.Helper()
+
+	spec := service.NewConfigSpec().Field(DirectoryField()).Field(PathField()).Field(CompressionField()).Field(SkipPrecompressedField()).Field(BundleField()).Field(FailFastField()).Field(MaxObjectSizeField()).Field(MaxMessageBytesField()).Field(SerializeKeyWritesField()).Field(service.NewIntField("max_in_flight").Default(1))
+	pConf, err := spec.ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	w, err := NewWriterFromConfig(pConf)
+	require.NoError(t, err)
+	return w
+}
+
+func TestWriterWriteBatch(t *testing.T) {
+	w := testWriter(t, `
+directory: ${! meta("dir") }
+path: ${! content() }.txt
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("foo")),
+		service.NewMessage([]byte("bar")),
+	}
+	batch[0].MetaSet("dir", "a/")
+	batch[1].MetaSet("dir", "b")
+
+	written := map[string]string{}
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		written[key] = string(body)
+		return nil
+	}))
+
+	assert.Equal(t, map[string]string{
+		"a/foo.txt": "foo",
+		"b/bar.txt": "bar",
+	}, written)
+}
+
+func TestWriterWriteBatchError(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! content() }
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("a")),
+		service.NewMessage([]byte("b")),
+		service.NewMessage([]byte("c")),
+	}
+
+	var keys []string
+	err := w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		keys = append(keys, key)
+		if key == "foo/b" {
+			return errors.New("nope")
+		}
+		return nil
+	})
+	require.EqualError(t, err, "nope")
+	assert.Equal(t, []string{"foo/a", "foo/b"}, keys)
+}
+
+func TestWriterWriteBatchCompressed(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! content() }.txt
+compression: gzip
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("bar")),
+		service.NewMessage(nil),
+	}
+
+	written := map[string][]byte{}
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		written[key] = body
+		return nil
+	}))
+	require.Len(t, written, 2)
+
+	for key, expected := range map[string]string{
+		"foo/bar.txt.gz": "bar",
+		"foo/.txt.gz":    "",
+	} {
+		require.Contains(t, written, key)
+		body, err := CompressionGzip.Decompress(written[key])
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	}
+}
+
+func TestWriterSerializeKeyWrites(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! content() }
+serialize_key_writes: true
+`)
+
+	var mut sync.Mutex
+	writing := map[string]bool{}
+	var overlapped bool
+
+	put := func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		mut.Lock()
+		if writing[key] {
+			overlapped = true
+		}
+		writing[key] = true
+		mut.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mut.Lock()
+		writing[key] = false
+		mut.Unlock()
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, w.WriteBatch(context.Background(), service.MessageBatch{
+				service.NewMessage([]byte("a")),
+				service.NewMessage([]byte("b")),
+			}, put))
+		}()
+	}
+	wg.Wait()
+
+	assert.False(t, overlapped)
+}
+
+func TestWriterWriteBatchParallel(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! content() }
+max_in_flight: 4
+`)
+
+	var batch service.MessageBatch
+	for i := 0; i < 8; i++ {
+		batch = append(batch, service.NewMessage([]byte(strconv.Itoa(i))))
+	}
+
+	var mut sync.Mutex
+	var active, maxActive int
+	var keys []string
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		mut.Lock()
+		keys = append(keys, key)
+		if active++; active > maxActive {
+			maxActive = active
+		}
+		mut.Unlock()
+
+		time.Sleep(time.Millisecond * 10)
+
+		mut.Lock()
+		active--
+		mut.Unlock()
+		return nil
+	}))
+
+	assert.Equal(t, 4, maxActive)
+	assert.ElementsMatch(t, []string{"foo/0", "foo/1", "foo/2", "foo/3", "foo/4", "foo/5", "foo/6", "foo/7"}, keys)
+}
+
+func TestWriterWriteBatchParallelSameKeyOrder(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! meta("key") }
+max_in_flight: 4
+`)
+
+	var batch service.MessageBatch
+	for i := 0; i < 20; i++ {
+		msg := service.NewMessage([]byte(strconv.Itoa(i)))
+		msg.MetaSet("key", strconv.Itoa(i%3))
+		batch = append(batch, msg)
+	}
+
+	var mut sync.Mutex
+	written := map[string][]string{}
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+		mut.Lock()
+		written[key] = append(written[key], string(body))
+		mut.Unlock()
+		return nil
+	}))
+
+	assert.Equal(t, map[string][]string{
+		"foo/0": {"0", "3", "6", "9", "12", "15", "18"},
+		"foo/1": {"1", "4", "7", "10", "13", "16", "19"},
+		"foo/2": {"2", "5", "8", "11", "14", "17"},
+	}, written)
+}
+
+func TestWriterSerializeKeyWritesOrder(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: a
+serialize_key_writes: true
+max_in_flight: 4
+`)
+
+	var mut sync.Mutex
+	var written []string
+	entered, release := make(chan struct{}), make(chan struct{})
+	put := func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		if string(body) == "first" {
+			close(entered)
+			<-release
+		}
+		mut.Lock()
+		written = append(written, string(body))
+		mut.Unlock()
+		return nil
+	}
+
+	tail := func() *keyTurn {
+		w.keyLocks.mut.Lock()
+		defer w.keyLocks.mut.Unlock()
+		return w.keyLocks.tails["foo/a"]
+	}
+
+	var wg sync.WaitGroup
+	writeBatch := func(bodies ...string) {
+		prev := tail()
+		var batch service.MessageBatch
+		for _, body := range bodies {
+			batch = append(batch, service.NewMessage([]byte(body)))
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, w.WriteBatch(context.Background(), batch, put))
+		}()
+		// Wait for the batch to reserve its turns before writing the next.
+		require.Eventually(t, func() bool {
+			return tail() != prev
+		}, time.Second, time.Millisecond)
+	}
+
+	writeBatch("first")
+	<-entered
+	writeBatch("second", "third")
+	writeBatch("fourth")
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, written)
+	assert.Empty(t, w.keyLocks.tails)
+}
+
+func TestWriterWriteBatchBundled(t *testing.T) {
+	w := testWriter(t, `
+directory: ${! meta("dir") }
+path: ${! batch_size() }.txt
+bundle: lines
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("foo")),
+		service.NewMessage([]byte("bar")),
+		service.NewMessage([]byte("baz")),
+	}
+	batch[0].MetaSet("dir", "a")
+	batch[1].MetaSet("dir", "b")
+
+	written := map[string]string{}
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		written[key] = string(body)
+		return nil
+	}))
+
+	assert.Equal(t, map[string]string{
+		"a/3.txt": "foo\nbar\nbaz",
+	}, written)
+}
+
+func TestWriterWriteBatchNoFailFast(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! content() }
+fail_fast: false
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("a")),
+		service.NewMessage([]byte("b")),
+		service.NewMessage([]byte("c")),
+	}
+
+	var keys []string
+	err := w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		keys = append(keys, key)
+		if key == "foo/b" {
+			return errors.New("nope")
+		}
+		return nil
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"foo/a", "foo/b", "foo/c"}, keys)
+
+	var bErr *service.BatchError
+	require.True(t, errors.As(err, &bErr))
+	assert.Equal(t, 1, bErr.IndexedErrors())
+
+	bErr.WalkMessages(func(i int, _ *service.Message, err error) bool {
+		if i == 1 {
+			assert.EqualError(t, err, "nope")
+		} else {
+			assert.NoError(t, err)
+		}
+		return true
+	})
+	assert.EqualError(t, batch[1].GetError(), "nope")
+	assert.NoError(t, batch[0].GetError())
+}
+
+func TestWriterWriteBatchSkipPrecompressed(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! meta("name") }
+compression: gzip
+skip_precompressed: true
+`)
+
+	precompressed, err := CompressionGzip.Compress([]byte("bar"))
+	require.NoError(t, err)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("baz")),
+		service.NewMessage(precompressed),
+	}
+	batch[0].MetaSet("name", "a.txt")
+	batch[1].MetaSet("name", "b.txt.gz")
+
+	written := map[string][]byte{}
+	compressions := map[string]Compression{}
+	require.NoError(t, w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, compression Compression) error {
+		written[key] = body
+		compressions[key] = compression
+		return nil
+	}))
+
+	assert.Equal(t, map[string]Compression{
+		"foo/a.txt.gz": CompressionGzip,
+		"foo/b.txt.gz": CompressionNone,
+	}, compressions)
+	assert.Equal(t, precompressed, written["foo/b.txt.gz"])
+
+	body, err := CompressionGzip.Decompress(written["foo/a.txt.gz"])
+	require.NoError(t, err)
+	assert.Equal(t, "baz", string(body))
+}
+
+func TestWriterWriteBatchMaxMessageBytes(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: ${! content() }
+compression: gzip
+fail_fast: false
+max_message_bytes: 3
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("abc")),
+		service.NewMessage([]byte("abcd")),
+	}
+
+	var keys []string
+	err := w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		keys = append(keys, key)
+		return nil
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"foo/abc.gz"}, keys)
+
+	assert.NoError(t, batch[0].GetError())
+	assert.EqualError(t, batch[1].GetError(), "message body of 4 bytes exceeds the limit of 3 bytes")
+	assert.True(t, errors.Is(batch[1].GetError(), component.ErrMessageTooLarge))
+}
+
+func TestWriterWriteBatchBundledMaxMessageBytes(t *testing.T) {
+	w := testWriter(t, `
+directory: foo
+path: bundle.txt
+bundle: lines
+max_message_bytes: 3
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("foo")),
+		service.NewMessage([]byte("barbaz")),
+	}
+
+	err := w.WriteBatch(context.Background(), batch, func(ctx context.Context, msg *service.Message, key string, body []byte, _ Compression) error {
+		t.Error("unexpected write")
+		return nil
+	})
+	require.EqualError(t, err, "message body of 6 bytes exceeds the limit of 3 bytes")
+}
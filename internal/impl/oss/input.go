@@ -0,0 +1,345 @@
+package oss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	maxOSSListObjectsResults = 100
+)
+
+func ossInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.11.0").
+		Summary("Downloads objects within an Alibaba Cloud OSS bucket, optionally filtered by a prefix.").
+		Description(`
+Each object is consumed as a single message containing the full contents of the object.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "```" + `
+- oss_key
+- oss_size
+- oss_last_modified
+` + "```" + `
+
+You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata).`).
+		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to bucket.")).
+		Field(service.NewStringField("bucket").Description("The name of the bucket from which to download objects.")).
+		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
+		Field(service.NewStringField("secret_key").Description("User's Secret key.").Secret()).
+		Field(service.NewStringField("prefix").
+			Description("An optional path prefix, if set only objects with the prefix are consumed.").
+			Default("")).
+		Field(objstore.KeySuffixField()).
+		Field(objstore.KeyRegexField()).
+		Field(service.NewBoolField("delete_objects").
+			Description("Whether to delete downloaded objects from the bucket once they are processed.").
+			Advanced().
+			Default(false)).
+		Field(objstore.CompressionField()).
+		Field(objstore.FilenameMetadataField()).
+		Field(objstore.ReassemblePartsField()).
+		Field(objstore.CheckpointCacheField()).
+		Field(objstore.DownloadThreadsField())
+}
+
+func init() {
+	err := service.RegisterInput("oss", ossInputConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+		i, err := newOSSInputFromConfig(conf, mgr)
+		if err != nil {
+			return nil, err
+		}
+		return service.AutoRetryNacks(i), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newOSSInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (o *ossInput, err error) {
+	o = &ossInput{}
+	o.logger = mgr.Logger()
+	if o.endpoint, err = conf.FieldString("endpoint"); err != nil {
+		return nil, err
+	}
+	if o.bucketName, err = conf.FieldString("bucket"); err != nil {
+		return nil, err
+	}
+	if o.secretId, err = conf.FieldString("secret_id"); err != nil {
+		return nil, err
+	}
+	if o.secretKey, err = conf.FieldString("secret_key"); err != nil {
+		return nil, err
+	}
+	if o.prefix, err = conf.FieldString("prefix"); err != nil {
+		return nil, err
+	}
+	if o.filter, err = objstore.KeyFilterFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if o.deleteObjects, err = conf.FieldBool("delete_objects"); err != nil {
+		return nil, err
+	}
+	if o.compression, err = objstore.CompressionFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if o.filename, err = objstore.FilenameFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if o.reassemble, err = conf.FieldBool("reassemble_parts"); err != nil {
+		return nil, err
+	}
+	if !o.deleteObjects {
+		if o.checkpoint, err = objstore.CheckpointFromConfig(conf, o.bucketName, mgr); err != nil {
+			return nil, err
+		}
+	}
+	if o.downloads, err = objstore.DownloadsFromConfig(conf); err != nil {
+		return nil, err
+	}
+	return
+}
+
+type ossInput struct {
+	endpoint      string
+	bucketName    string
+	secretId      string
+	secretKey     string
+	prefix        string
+	filter        objstore.KeyFilter
+	deleteObjects bool
+	compression   objstore.Compression
+	filename      objstore.Filename
+	reassemble    bool
+	checkpoint    *objstore.Checkpoint
+
+	bucketMut sync.Mutex
+	bucket    *oss.Bucket
+
+	pending   []oss.ObjectProperties
+	marker    string
+	exhausted bool
+	downloads *objstore.Downloads
+
+	logger *service.Logger
+}
+
+func (o *ossInput) Connect(ctx context.Context) error {
+	o.bucketMut.Lock()
+	defer o.bucketMut.Unlock()
+
+	client, err := oss.New(o.endpoint, o.secretId, o.secretKey)
+	if err != nil {
+		return err
+	}
+	if o.bucket, err = client.Bucket(o.bucketName); err != nil {
+		return err
+	}
+
+	o.pending = nil
+	o.marker = ""
+	o.exhausted = false
+	o.downloads.Reset()
+	o.logger.Infof("Downloading OSS objects from bucket: %s\n", o.bucketName)
+	return nil
+}
+
+// nextObject pops the next object from the pending listing, requesting a new
+// page of results from the bucket when the current one has been drained.
+func (o *ossInput) nextObject() (oss.ObjectProperties, error) {
+	for len(o.pending) == 0 {
+		if o.exhausted {
+			return oss.ObjectProperties{}, service.ErrEndOfInput
+		}
+		res, err := o.bucket.ListObjects(
+			oss.Prefix(o.prefix),
+			oss.Marker(o.marker),
+			oss.MaxKeys(maxOSSListObjectsResults),
+		)
+		if err != nil {
+			return oss.ObjectProperties{}, err
+		}
+		o.pending = res.Objects
+		o.marker = res.NextMarker
+		o.exhausted = !res.IsTruncated
+	}
+	obj := o.pending[0]
+	o.pending = o.pending[1:]
+	return obj, nil
+}
+
+// nextUnprocessedObject returns the next listed object to consume, skipping
+// parts that are consumed along with their manifest, objects with keys that do
+// not match the filter, and objects that have already been checkpointed.
+func (o *ossInput) nextUnprocessedObject(ctx context.Context) (oss.ObjectProperties, error) {
+	for {
+		obj, err := o.nextObject()
+		if err != nil {
+			return obj, err
+		}
+		if o.reassemble && objstore.IsPartKey(obj.Key) {
+			continue
+		}
+		if !o.filter.Matches(o.logicalKey(obj.Key)) {
+			continue
+		}
+		processed, err := o.checkpoint.Processed(ctx, obj.Key, obj.ETag)
+		if err != nil {
+			o.logger.Warnf("Failed to read checkpoint of object %v, consuming it regardless: %v", obj.Key, err)
+		}
+		if !processed {
+			return obj, nil
+		}
+	}
+}
+
+// logicalKey returns the key of the object that a listed key represents,
+// which for manifests is the key of the object that was split into parts.
+func (o *ossInput) logicalKey(key string) string {
+	if o.reassemble && objstore.IsManifestKey(key) {
+		return strings.TrimSuffix(key, objstore.ManifestSuffix)
+	}
+	return key
+}
+
+// getObject downloads the contents of an object along with its response
+// headers.
+func getObject(ctx context.Context, bucket *oss.Bucket, key string) ([]byte, http.Header, error) {
+	var header http.Header
+	body, err := bucket.GetObject(key, oss.WithContext(ctx), oss.GetResponseHeader(&header))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, header, nil
+}
+
+// deleteOnAck returns an AckFunc that deletes the provided keys once a message
+// is successfully processed, when deleting objects is enabled.
+func (o *ossInput) deleteOnAck(bucket *oss.Bucket, keys ...string) service.AckFunc {
+	return func(ctx context.Context, res error) error {
+		if res != nil || !o.deleteObjects {
+			return nil
+		}
+		for _, key := range keys {
+			if err := bucket.DeleteObject(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (o *ossInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	o.bucketMut.Lock()
+	defer o.bucketMut.Unlock()
+
+	if o.bucket == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	for !o.downloads.Full() {
+		if o.downloads.Retry() {
+			continue
+		}
+		obj, err := o.nextUnprocessedObject(ctx)
+		if err != nil {
+			// Listing errors, including the end of input, are returned once
+			// the downloads already in flight have been consumed.
+			if o.downloads.Len() > 0 {
+				break
+			}
+			return nil, nil, err
+		}
+		bucket := o.bucket
+		o.downloads.Start(func(ctx context.Context) (*service.Message, service.AckFunc, error) {
+			return o.download(ctx, bucket, obj)
+		})
+	}
+	return o.downloads.Next(ctx)
+}
+
+// download consumes a listed object as a message, which is called outside of
+// the bucket mutex and must therefore only access immutable fields.
+func (o *ossInput) download(ctx context.Context, bucket *oss.Bucket, obj oss.ObjectProperties) (*service.Message, service.AckFunc, error) {
+	if o.reassemble && objstore.IsManifestKey(obj.Key) {
+		return o.readManifest(ctx, bucket, obj)
+	}
+
+	data, header, err := getObject(ctx, bucket, obj.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data, err = o.compression.Decompress(data); err != nil {
+		return nil, nil, objstore.PermanentError(fmt.Errorf("failed to decompress object %v: %w", obj.Key, err))
+	}
+
+	msg := service.NewMessage(data)
+	msg.MetaSetMut("oss_key", obj.Key)
+	msg.MetaSetMut("oss_size", obj.Size)
+	msg.MetaSetMut("oss_last_modified", obj.LastModified.Format(time.RFC3339))
+	o.filename.SetMessageMetadata(msg, header.Get(oss.HTTPHeaderOssMetaPrefix+objstore.FilenameUserMetadataKey))
+	return msg, o.checkpoint.Ack(obj.Key, obj.ETag, o.deleteOnAck(bucket, obj.Key)), nil
+}
+
+// readManifest consumes the parts listed by a manifest object as a single
+// message, keyed by the object that was split into the parts.
+func (o *ossInput) readManifest(ctx context.Context, bucket *oss.Bucket, obj oss.ObjectProperties) (*service.Message, service.AckFunc, error) {
+	manifestBytes, header, err := getObject(ctx, bucket, obj.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := objstore.ParseManifest(manifestBytes)
+	if err != nil {
+		return nil, nil, objstore.PermanentError(fmt.Errorf("failed to read manifest %v: %w", obj.Key, err))
+	}
+
+	data, err := m.Reassemble(ctx, func(ctx context.Context, key string) ([]byte, error) {
+		data, _, err := getObject(ctx, bucket, key)
+		return data, err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reassemble manifest %v: %w", obj.Key, err)
+	}
+
+	key := o.logicalKey(obj.Key)
+	if data, err = m.ObjectCompression(o.compression).Decompress(data); err != nil {
+		return nil, nil, objstore.PermanentError(fmt.Errorf("failed to decompress object %v: %w", key, err))
+	}
+
+	msg := service.NewMessage(data)
+	msg.MetaSetMut("oss_key", key)
+	msg.MetaSetMut("oss_size", int64(m.Size))
+	msg.MetaSetMut("oss_last_modified", obj.LastModified.Format(time.RFC3339))
+	o.filename.SetMessageMetadata(msg, header.Get(oss.HTTPHeaderOssMetaPrefix+objstore.FilenameUserMetadataKey))
+	return msg, o.checkpoint.Ack(obj.Key, obj.ETag, o.deleteOnAck(bucket, append(m.Parts, obj.Key)...)), nil
+}
+
+func (o *ossInput) Close(ctx context.Context) error {
+	o.bucketMut.Lock()
+	o.downloads.Reset()
+	o.bucket = nil
+	o.bucketMut.Unlock()
+	return nil
+}
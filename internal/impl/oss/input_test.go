@@ -0,0 +1,159 @@
+package oss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func testOSSInput(t *testing.T, handler http.HandlerFunc, conf string) *ossInput {
+	t.Helper()
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	pConf, err := ossInputConfig().ParseYAML(`
+endpoint: `+ts.URL+`
+bucket: foo
+secret_id: id
+secret_key: key
+`+conf, nil)
+	require.NoError(t, err)
+
+	o, err := newOSSInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.Connect(context.Background()))
+	return o
+}
+
+const testOSSListing = `<ListBucketResult>
+  <Name>foo</Name>
+  <IsTruncated>false</IsTruncated>
+  <Contents><Key>a.txt</Key><ETag>"1"</ETag></Contents>
+  <Contents><Key>b.txt</Key><ETag>"2"</ETag></Contents>
+</ListBucketResult>`
+
+func TestOSSInputRead(t *testing.T) {
+	o := testOSSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/foo/":
+			_, _ = w.Write([]byte(testOSSListing))
+		case "/foo/a.txt":
+			_, _ = w.Write([]byte("bar"))
+		case "/foo/b.txt":
+			_, _ = w.Write([]byte("baz"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, "")
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	for _, exp := range []struct{ key, body string }{{"a.txt", "bar"}, {"b.txt", "baz"}} {
+		msg, ack, err := o.Read(ctx)
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp.body, string(body))
+
+		key, _ := msg.MetaGet("oss_key")
+		assert.Equal(t, exp.key, key)
+		require.NoError(t, ack(ctx, nil))
+	}
+
+	_, _, err := o.Read(ctx)
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+}
+
+func TestOSSInputRetriesFailedDownloads(t *testing.T) {
+	var attempts int
+	o := testOSSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/foo/":
+			_, _ = w.Write([]byte(testOSSListing))
+		case "/foo/a.txt":
+			if attempts++; attempts == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("bar"))
+		case "/foo/b.txt":
+			_, _ = w.Write([]byte("baz"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, "")
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	_, _, err := o.Read(ctx)
+	require.Error(t, err)
+
+	for _, exp := range []string{"bar", "baz"} {
+		msg, ack, err := o.Read(ctx)
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(body))
+		require.NoError(t, ack(ctx, nil))
+	}
+	assert.Equal(t, 2, attempts)
+
+	_, _, err = o.Read(ctx)
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+}
+
+func TestOSSInputSkipsCorruptObjects(t *testing.T) {
+	barBytes, err := objstore.CompressionGzip.Compress([]byte("bar"))
+	require.NoError(t, err)
+
+	var attempts int
+	o := testOSSInput(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/foo/":
+			_, _ = w.Write([]byte(testOSSListing))
+		case "/foo/a.txt":
+			attempts++
+			_, _ = w.Write([]byte("not gzip"))
+		case "/foo/b.txt":
+			_, _ = w.Write(barBytes)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, `
+compression: gzip
+`)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	_, _, err = o.Read(ctx)
+	require.ErrorContains(t, err, "failed to decompress object a.txt")
+
+	// Objects that cannot be decompressed are skipped rather than retried.
+	for _, exp := range []string{"bar"} {
+		msg, ack, err := o.Read(ctx)
+		require.NoError(t, err)
+
+		body, err := msg.AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(body))
+		require.NoError(t, ack(ctx, nil))
+	}
+	assert.Equal(t, 1, attempts)
+
+	_, _, err = o.Read(ctx)
+	require.ErrorIs(t, err, service.ErrEndOfInput)
+}
@@ -2,37 +2,59 @@ package oss
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/impl/objstore"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
 
-import (
-	"context"
-)
-
-func cosOutputConfig() *service.ConfigSpec {
+func ossOutputConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
 		Stable().
 		Categories("Services").
-		Summary("Sends message parts as files to a Oss.").
+		Summary("Sends message parts as files to an Alibaba Cloud OSS bucket.").
 		Description(``).
 		Field(service.NewStringField("endpoint").Description("Endpoint corresponding to bucket.")).
-		Field(service.NewStringField("bucket").Description("Bucket name")).
+		Field(service.NewInterpolatedStringField("bucket").Description("The bucket to upload messages to. This field supports interpolation, allowing messages to be routed to different buckets.")).
 		Field(service.NewStringField("secret_id").Description("User's Secret ID.")).
 		Field(service.NewStringField("secret_key").Description("User's Secret key.")).
-		Field(service.NewInterpolatedStringField("directory").Description("A directory to store message files within. If the directory does not exist it will be created.")).
-		Field(service.NewInterpolatedStringField("path").Description("The path of each message to upload.")).
+		Field(objstore.DirectoryField()).
+		Field(objstore.PathField()).
+		Field(objstore.CompressionField()).
+		Field(objstore.BundleField()).
+		Field(objstore.FailFastField()).
+		Field(objstore.SerializeKeyWritesField()).
+		Field(objstore.MaxObjectSizeField()).
+		Field(objstore.MaxMessageBytesField()).
+		Field(objstore.VerifyOnConnectField()).
+		Field(objstore.CacheControlField()).
+		Field(objstore.ExpiresField()).
+		Field(objstore.FilenameMetadataField()).
+		Field(service.NewStringEnumField("encryption", "AES256", "KMS").
+			Description("An optional server-side encryption algorithm to apply to uploaded objects.").
+			Advanced().
+			Optional()).
+		Field(service.NewStringEnumField("acl", "default", "private", "public-read", "public-read-write").
+			Description("An optional access control list to apply to uploaded objects.").
+			Advanced().
+			Optional()).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of inserts to run in parallel.").
 			Default(64))
-	spec = spec.Field(service.NewBatchPolicyField("batching")).
+	spec = spec.Field(objstore.BatchPolicyField()).
 		Version("3.65.0").
-		Example("file to cos",
-			`Here we send data to COS in batches`,
+		Example("file to oss",
+			`Here we send data to OSS in batches`,
 			`
 output:
-  cos:
+  oss:
     endpoint: xxxxx
     bucket: xxxx
     secret_id: xxxxxxxxxxxxxx
@@ -51,25 +73,27 @@ output:
 }
 
 func init() {
-	service.RegisterBatchOutput("oss", cosOutputConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+	service.RegisterBatchOutput("oss", ossOutputConfig(), func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
 		if batchPolicy, err = conf.FieldBatchPolicy("batching"); err != nil {
 			return
 		}
 		if maxInFlight, err = conf.FieldInt("max_in_flight"); err != nil {
 			return
 		}
-		out, err = newCosOutputFromConfig(conf, mgr.Logger())
+		out, err = newOSSOutputFromConfig(conf, mgr)
 		return
 	})
 }
 
-func newCosOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (o *oosOutput, err error) {
+func newOSSOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (o *oosOutput, err error) {
 	o = &oosOutput{}
-	o.logger = logger
+	o.logger = mgr.Logger()
+	o.shutSig = shutdown.NewSignaller()
+	o.metrics = objstore.NewMetrics(mgr.Metrics())
 	if o.endpoint, err = conf.FieldString("endpoint"); err != nil {
 		return nil, err
 	}
-	if o.bucketName, err = conf.FieldString("bucket_name"); err != nil {
+	if o.bucketName, err = conf.FieldInterpolatedString("bucket"); err != nil {
 		return nil, err
 	}
 	if o.secretId, err = conf.FieldString("secret_id"); err != nil {
@@ -78,25 +102,53 @@ func newCosOutputFromConfig(conf *service.ParsedConfig, logger *service.Logger)
 	if o.secretKey, err = conf.FieldString("secret_key"); err != nil {
 		return nil, err
 	}
-	if o.directory, err = conf.FieldInterpolatedString("directory"); err != nil {
+	if o.verifyOnConnect, err = conf.FieldBool("verify_on_connect"); err != nil {
 		return nil, err
 	}
-	if o.path, err = conf.FieldInterpolatedString("path"); err != nil {
+	if o.writer, err = objstore.NewWriterFromConfig(conf); err != nil {
 		return nil, err
 	}
+	if o.headers, err = objstore.HeadersFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if o.filename, err = objstore.FilenameFromConfig(conf); err != nil {
+		return nil, err
+	}
+	if conf.Contains("encryption") {
+		var encryption string
+		if encryption, err = conf.FieldString("encryption"); err != nil {
+			return nil, err
+		}
+		o.putOptions = append(o.putOptions, oss.ServerSideEncryption(encryption))
+	}
+	if conf.Contains("acl") {
+		var acl string
+		if acl, err = conf.FieldString("acl"); err != nil {
+			return nil, err
+		}
+		o.putOptions = append(o.putOptions, oss.ObjectACL(oss.ACLType(acl)))
+	}
 	return
 }
 
 type oosOutput struct {
 	endpoint   string
-	bucketName string
+	bucketName *service.InterpolatedString
 	secretId   string
 	secretKey  string
 
-	directory *service.InterpolatedString
-	path      *service.InterpolatedString
+	verifyOnConnect bool
 
-	bucket *oss.Bucket
+	writer   *objstore.Writer
+	headers  objstore.Headers
+	filename objstore.Filename
+	metrics  *objstore.Metrics
+
+	putOptions []oss.Option
+
+	client     *oss.Client
+	bucketsMut sync.Mutex
+	buckets    map[string]*oss.Bucket
 
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
@@ -107,28 +159,112 @@ func (o *oosOutput) Connect(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	o.bucket, err = client.Bucket(o.bucketName)
-	if err != nil {
-		return err
+
+	if o.verifyOnConnect {
+		if err = o.verify(client); err != nil {
+			return err
+		}
 	}
+
+	o.bucketsMut.Lock()
+	o.client = client
+	o.buckets = map[string]*oss.Bucket{}
+	o.bucketsMut.Unlock()
 	return nil
 }
 
-func (o *oosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
-	for _, msg := range batch {
-		data, err := msg.AsBytes()
-		if err != nil {
-			return err
+// verify checks that the bucket exists and is accessible with the configured
+// credentials. Buckets of interpolated names are resolved per message and are
+// therefore not checked.
+func (o *oosOutput) verify(client *oss.Client) error {
+	name, isStatic := o.bucketName.Static()
+	if !isStatic {
+		return nil
+	}
+	if _, err := client.GetBucketInfo(name); err != nil {
+		return fmt.Errorf("failed to access bucket %v: %w", name, err)
+	}
+	return nil
+}
+
+// getBucket returns a cached bucket handle for the given name, creating one
+// when the name hasn't been seen since the last connect.
+func (o *oosOutput) getBucket(name string) (*oss.Bucket, error) {
+	o.bucketsMut.Lock()
+	defer o.bucketsMut.Unlock()
+
+	if o.client == nil {
+		return nil, service.ErrNotConnected
+	}
+	if b, exists := o.buckets[name]; exists {
+		return b, nil
+	}
+	b, err := o.client.Bucket(name)
+	if err != nil {
+		return nil, err
+	}
+	o.buckets[name] = b
+	return b, nil
+}
+
+func (o *oosOutput) putObject(ctx context.Context, msg *service.Message, key string, body []byte, _ objstore.Compression) error {
+	bucketName := o.bucketName.String(msg)
+	if bucketName == "" {
+		return errors.New("bucket name interpolation resolved to an empty string")
+	}
+	opts, err := o.objectOptions(msg)
+	if err != nil {
+		return err
+	}
+	bucket, err := o.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, oss.WithContext(ctx))
+	if err := bucket.PutObject(key, bytes.NewReader(body), opts...); err != nil {
+		osErr := component.ErrObjectStorage{
+			Bucket: bucketName,
+			Key:    key,
+			Err:    err,
 		}
-		key := o.directory.String(msg) + o.path.String(msg)
-		err = o.bucket.PutObject(key, bytes.NewReader(data))
-		if err != nil {
-			return err
+		var sErr oss.ServiceError
+		if errors.As(err, &sErr) {
+			osErr.StatusCode = sErr.StatusCode
 		}
+		return osErr
 	}
 	return nil
 }
 
+// objectOptions returns the options to upload a message with, which are the
+// static options of the output followed by any headers resolved from the
+// message.
+func (o *oosOutput) objectOptions(msg *service.Message) ([]oss.Option, error) {
+	opts := append([]oss.Option(nil), o.putOptions...)
+	if cacheControl := o.headers.CacheControl(msg); cacheControl != "" {
+		opts = append(opts, oss.CacheControl(cacheControl))
+	}
+	expires, err := o.headers.Expires(msg)
+	if err != nil {
+		return nil, err
+	}
+	if !expires.IsZero() {
+		opts = append(opts, oss.Expires(expires))
+	}
+	if filename, ok := o.filename.UserMetadata(msg); ok {
+		opts = append(opts, oss.Meta(objstore.FilenameUserMetadataKey, filename))
+	}
+	return opts, nil
+}
+
+func (o *oosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	ctx, done := o.shutSig.CloseNowCtx(ctx)
+	defer done()
+
+	return o.writer.WriteBatch(ctx, batch, o.metrics.Wrap(o.putObject, o.bucketName.String))
+}
+
 func (o *oosOutput) Close(ctx context.Context) error {
+	o.shutSig.CloseNow()
 	return nil
 }
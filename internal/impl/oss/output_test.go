@@ -0,0 +1,269 @@
+package oss
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+type testOSSRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+// testOSSServer records the requests it receives and responds to them with
+// the provided handler.
+func testOSSServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, func() []testOSSRequest) {
+	t.Helper()
+
+	var mut sync.Mutex
+	var reqs []testOSSRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mut.Lock()
+		reqs = append(reqs, testOSSRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			header: r.Header.Clone(),
+			body:   string(body),
+		})
+		mut.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, func() []testOSSRequest {
+		mut.Lock()
+		defer mut.Unlock()
+		return append([]testOSSRequest(nil), reqs...)
+	}
+}
+
+func testOSSOutput(t *testing.T, endpoint, conf string) *oosOutput {
+	t.Helper()
+
+	pConf, err := ossOutputConfig().ParseYAML(`
+endpoint: `+endpoint+`
+secret_id: id
+secret_key: key
+`+conf, nil)
+	require.NoError(t, err)
+
+	o, err := newOSSOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	return o
+}
+
+func TestOSSOutputEncryptionAndACL(t *testing.T) {
+	ts, reqs := testOSSServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	o := testOSSOutput(t, ts.URL, `
+bucket: foo
+directory: bar/
+path: baz.txt
+verify_on_connect: false
+encryption: AES256
+acl: private
+`)
+	require.NoError(t, o.Connect(context.Background()))
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte("hello")),
+	}))
+
+	sent := reqs()
+	require.Len(t, sent, 1)
+	assert.Equal(t, http.MethodPut, sent[0].method)
+	assert.Equal(t, "/foo/bar/baz.txt", sent[0].path)
+	assert.Equal(t, "hello", sent[0].body)
+	assert.Equal(t, "AES256", sent[0].header.Get("X-Oss-Server-Side-Encryption"))
+	assert.Equal(t, "private", sent[0].header.Get("X-Oss-Object-Acl"))
+}
+
+func TestOSSOutputNoEncryptionOrACL(t *testing.T) {
+	ts, reqs := testOSSServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	o := testOSSOutput(t, ts.URL, `
+bucket: foo
+directory: bar/
+path: baz.txt
+verify_on_connect: false
+`)
+	require.NoError(t, o.Connect(context.Background()))
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte("hello")),
+	}))
+
+	sent := reqs()
+	require.Len(t, sent, 1)
+	assert.Empty(t, sent[0].header.Get("X-Oss-Server-Side-Encryption"))
+	assert.Empty(t, sent[0].header.Get("X-Oss-Object-Acl"))
+}
+
+func TestOSSOutputVerifyOnConnect(t *testing.T) {
+	ts, reqs := testOSSServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/nope/" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchBucket</Code></Error>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<BucketInfo><Bucket><Name>foo</Name></Bucket></BucketInfo>`))
+	})
+
+	o := testOSSOutput(t, ts.URL, `
+bucket: foo
+directory: bar/
+path: baz.txt
+`)
+	require.NoError(t, o.Connect(context.Background()))
+
+	sent := reqs()
+	require.Len(t, sent, 1)
+	assert.Equal(t, http.MethodGet, sent[0].method)
+	assert.Equal(t, "/foo/", sent[0].path)
+
+	o = testOSSOutput(t, ts.URL, `
+bucket: nope
+directory: bar/
+path: baz.txt
+`)
+	require.Error(t, o.Connect(context.Background()))
+
+	// Interpolated bucket names are not verified.
+	o = testOSSOutput(t, ts.URL, `
+bucket: ${! meta("bucket") }
+directory: bar/
+path: baz.txt
+`)
+	require.NoError(t, o.Connect(context.Background()))
+	assert.Len(t, reqs(), 2)
+}
+
+func TestOSSOutputInterpolatedBucket(t *testing.T) {
+	ts, reqs := testOSSServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	o := testOSSOutput(t, ts.URL, `
+bucket: ${! meta("bucket").or("") }
+directory: bar/
+path: baz.txt
+`)
+	require.NoError(t, o.Connect(context.Background()))
+
+	msgA, msgB := service.NewMessage([]byte("a")), service.NewMessage([]byte("b"))
+	msgA.MetaSet("bucket", "foo")
+	msgB.MetaSet("bucket", "qux")
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{msgA, msgB}))
+
+	sent := reqs()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "/foo/bar/baz.txt", sent[0].path)
+	assert.Equal(t, "/qux/bar/baz.txt", sent[1].path)
+
+	err := o.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte("c"))})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bucket name interpolation resolved to an empty string")
+}
+
+func TestOSSOutputBatchFailures(t *testing.T) {
+	testBatch := func() service.MessageBatch {
+		return service.MessageBatch{
+			service.NewMessage([]byte("a")),
+			service.NewMessage([]byte("b")),
+			service.NewMessage([]byte("c")),
+		}
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/foo/b.txt" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<Error><Code>AccessDenied</Code></Error>`))
+		}
+	}
+
+	// By default the batch is abandoned on the first failure and the error is
+	// returned for the entire batch, which with messages written one at a time
+	// means the remaining messages are never attempted.
+	ts, reqs := testOSSServer(t, handler)
+	o := testOSSOutput(t, ts.URL, `
+bucket: foo
+directory: ""
+path: ${! content() }.txt
+verify_on_connect: false
+max_in_flight: 1
+`)
+	require.NoError(t, o.Connect(context.Background()))
+
+	err := o.WriteBatch(context.Background(), testBatch())
+	require.Error(t, err)
+
+	var bErr *service.BatchError
+	require.False(t, errors.As(err, &bErr))
+	assert.Len(t, reqs(), 2)
+
+	// Otherwise every message is attempted and only failures are marked.
+	ts, reqs = testOSSServer(t, handler)
+	o = testOSSOutput(t, ts.URL, `
+bucket: foo
+directory: ""
+path: ${! content() }.txt
+verify_on_connect: false
+fail_fast: false
+`)
+	require.NoError(t, o.Connect(context.Background()))
+
+	err = o.WriteBatch(context.Background(), testBatch())
+	require.ErrorAs(t, err, &bErr)
+
+	var failed []int
+	bErr.WalkMessages(func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			failed = append(failed, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{1}, failed)
+	assert.Len(t, reqs(), 3)
+}
+
+func TestOSSOutputCloseCancelsWrites(t *testing.T) {
+	started := make(chan struct{})
+	ts, _ := testOSSServer(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	o := testOSSOutput(t, ts.URL, `
+bucket: foo
+directory: bar/
+path: baz.txt
+verify_on_connect: false
+`)
+	require.NoError(t, o.Connect(context.Background()))
+
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- o.WriteBatch(context.Background(), service.MessageBatch{
+			service.NewMessage([]byte("hello")),
+		})
+	}()
+
+	<-started
+	require.NoError(t, o.Close(context.Background()))
+
+	select {
+	case err := <-writeErr:
+		require.Error(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("write was not cancelled by close")
+	}
+}
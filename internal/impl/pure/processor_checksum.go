@@ -0,0 +1,149 @@
+package pure
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	cspFieldAlgorithm   = "algorithm"
+	cspFieldEncoding    = "encoding"
+	cspFieldMetadataKey = "metadata_key"
+)
+
+func checksumProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Summary("Computes a checksum of the raw contents of each message and stores it as a metadata field.").
+		Description(`
+This is useful for attaching integrity information to messages before they are uploaded as objects, allowing downstream consumers to verify the contents they receive. The contents of messages are not modified.
+
+In order to have the server verify the integrity of uploads to the `+"`minio`"+` output use its field `+"`send_content_md5`"+` instead, which results in uploads with a mismatched checksum being rejected.`).
+		Field(service.NewStringAnnotatedEnumField(cspFieldAlgorithm, map[string]string{
+			"md5":    "The MD5 hash of the contents.",
+			"sha256": "The SHA-256 hash of the contents.",
+			"crc32c": "The CRC-32 checksum of the contents using the Castagnoli polynomial, encoded in big-endian order.",
+		}).
+			Description("The checksum algorithm to apply.").
+			Default("sha256")).
+		Field(service.NewStringAnnotatedEnumField(cspFieldEncoding, map[string]string{
+			"hex":    "Hexadecimal encoding.",
+			"base64": "Standard base64 encoding, which is the format expected by headers such as `Content-MD5`.",
+		}).
+			Description("The encoding of the checksum stored as metadata.").
+			Default("hex")).
+		Field(service.NewStringField(cspFieldMetadataKey).
+			Description("The metadata key to store the checksum of each message in.").
+			Default("checksum")).
+		Example("Checksum Metadata", `
+Here we attach the SHA-256 checksum of each message as metadata to objects uploaded to OSS, which downstream consumers can use to verify their contents:`, `
+pipeline:
+  processors:
+    - checksum:
+        algorithm: sha256
+        metadata_key: content_sha256
+
+output:
+  oss:
+    endpoint: oss-cn-hangzhou.aliyuncs.com
+    bucket: foo
+    secret_id: xxxxx
+    secret_key: xxxxx
+    directory: bar
+    path: ${! meta("content_sha256") }.json
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"checksum", checksumProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newChecksumFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var checksumCastagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+func checksumHasher(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "md5":
+		return md5.New, nil
+	case "sha256":
+		return sha256.New, nil
+	case "crc32c":
+		return func() hash.Hash {
+			return crc32.New(checksumCastagnoliTable)
+		}, nil
+	}
+	return nil, fmt.Errorf("checksum algorithm not recognised: %v", algorithm)
+}
+
+func checksumEncoder(encoding string) (func([]byte) string, error) {
+	switch encoding {
+	case "hex":
+		return hex.EncodeToString, nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString, nil
+	}
+	return nil, fmt.Errorf("checksum encoding not recognised: %v", encoding)
+}
+
+type checksumProc struct {
+	newHash func() hash.Hash
+	encode  func([]byte) string
+	metaKey string
+}
+
+func newChecksumFromParsed(conf *service.ParsedConfig) (*checksumProc, error) {
+	algorithm, err := conf.FieldString(cspFieldAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	encoding, err := conf.FieldString(cspFieldEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checksumProc{}
+	if c.newHash, err = checksumHasher(algorithm); err != nil {
+		return nil, err
+	}
+	if c.encode, err = checksumEncoder(encoding); err != nil {
+		return nil, err
+	}
+	if c.metaKey, err = conf.FieldString(cspFieldMetadataKey); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *checksumProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	body, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	h := c.newHash()
+	_, _ = h.Write(body)
+
+	msg.MetaSet(c.metaKey, c.encode(h.Sum(nil)))
+	return service.MessageBatch{msg}, nil
+}
+
+func (c *checksumProc) Close(ctx context.Context) error {
+	return nil
+}
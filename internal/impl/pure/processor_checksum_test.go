@@ -0,0 +1,74 @@
+package pure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		conf     string
+		metaKey  string
+		expected string
+	}{
+		{
+			name:     "default sha256",
+			conf:     `{}`,
+			metaKey:  "checksum",
+			expected: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		},
+		{
+			name: "md5 base64",
+			conf: `
+algorithm: md5
+encoding: base64
+metadata_key: content_md5
+`,
+			metaKey:  "content_md5",
+			expected: "XrY7u+Ae7tCTyyK7j1rNww==",
+		},
+		{
+			name:     "crc32c hex",
+			conf:     `algorithm: crc32c`,
+			metaKey:  "checksum",
+			expected: "c99465aa",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := checksumProcConfig().ParseYAML(test.conf, nil)
+			require.NoError(t, err)
+
+			proc, err := newChecksumFromParsed(conf)
+			require.NoError(t, err)
+
+			batch, err := proc.Process(context.Background(), service.NewMessage([]byte("hello world")))
+			require.NoError(t, err)
+			require.Len(t, batch, 1)
+
+			body, err := batch[0].AsBytes()
+			require.NoError(t, err)
+			assert.Equal(t, "hello world", string(body))
+
+			v, exists := batch[0].MetaGet(test.metaKey)
+			require.True(t, exists)
+			assert.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestChecksumBadAlgorithm(t *testing.T) {
+	conf, err := checksumProcConfig().ParseYAML(`algorithm: crc64`, nil)
+	require.NoError(t, err)
+
+	_, err = newChecksumFromParsed(conf)
+	assert.EqualError(t, err, "checksum algorithm not recognised: crc64")
+}
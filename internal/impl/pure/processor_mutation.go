@@ -1,12 +1,33 @@
 package pure
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"text/template"
+	"time"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/public/bloblang"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
 
+// mutationFields are the fields of a mutation processor that is configured as
+// an object rather than directly with a mapping.
+var mutationFields = docs.FieldSpecs{
+	docs.FieldBloblang("mapping", "The [Bloblang](/docs/guides/bloblang/about) mapping to execute on each message."),
+	docs.FieldInt("threads", "The number of messages of a batch to execute the mapping on in parallel. When greater than one the order of messages within the resulting batch is preserved, and mappings that reference other messages of the batch (e.g. with the `from` method) observe them as they were before the batch was processed.").HasDefault(1).Advanced().AtVersion("4.11.0"),
+	docs.FieldBool("log_diff", "Whether to log a JSON diff between the contents of each message before and after the mapping at the `DEBUG` level. The diff lists the paths of fields that were added, removed or changed. This is intended for developing mappings and should not be enabled in production as it requires a deep copy of each message.").HasDefault(false).Advanced().AtVersion("4.11.0"),
+	docs.FieldBool("clear_metadata", "Whether to remove all metadata from each message after the mapping is executed, other than the keys listed in `keep_metadata`. This gives explicit control over which metadata is propagated downstream without the need for a separate processor.").HasDefault(false).Advanced().AtVersion("4.11.0"),
+	docs.FieldString("keep_metadata", "A list of metadata keys to preserve when `clear_metadata` is enabled, including any keys set by the mapping itself.").Array().HasDefault([]any{}).Advanced().AtVersion("4.11.0"),
+	docs.FieldDuration("timeout", "An optional maximum period of time to wait for the mapping of each message to execute. When exceeded, or when the processor is shutting down, the message is flagged as having failed and passed on unchanged. Note that the mapping itself cannot be interrupted and therefore continues in the background until it completes. The number of mappings executing at any given time, including those abandoned, is limited to `threads`, and therefore messages may also time out whilst waiting for abandoned mappings to complete.", "100ms", "5s").Optional().Advanced().AtVersion("4.11.0"),
+}
+
 func init() {
 	err := service.RegisterBatchProcessor(
 		"mutation",
@@ -14,7 +35,7 @@ func init() {
 			Stable().
 			Version("4.5.0").
 			Categories("Mapping", "Parsing").
-			Field(service.NewBloblangField("")).
+			Field(service.NewInternalField(docs.FieldAnything("", "").HasDefault("").LinterFunc(lintMutationConfig))).
 			Summary("Executes a [Bloblang](/docs/guides/bloblang/about) mapping and directly transforms the contents of messages, mutating (or deleting) them.").
 			Description(`
 Bloblang is a powerful language that enables a wide range of mapping, transformation and filtering tasks. For more information [check out the docs](/docs/guides/bloblang/about).
@@ -46,7 +67,16 @@ Mutations are advantageous over a standard mapping in situations where the resul
 Bloblang mappings can fail, in which case the error is logged and the message is flagged as having failed, allowing you to use [standard processor error handling patterns](/docs/configuration/error_handling).
 
 However, Bloblang itself also provides powerful ways of ensuring your mappings do not fail by specifying desired fallback behaviour, which you can read about [in this section](/docs/guides/bloblang/about#error-handling).
-			`).
+
+## Metrics
+
+In addition to the standard processor metrics this processor emits the counters `+"`mutation_processed`"+`, `+"`mutation_deleted`"+` and `+"`mutation_error`"+`, and the timing `+"`mutation_latency_ns`"+` which measures the execution of the mapping on each message.
+
+## Fields
+
+The mapping can either be specified directly as the value of this processor, or within the field `+"`mapping`"+` of an object alongside the following fields, which otherwise take their default values.
+
+`+mutationFieldsMarkdown()).
 			Example("Mapping", `
 Given JSON documents containing an array of fans:
 
@@ -114,41 +144,353 @@ pipeline:
                         sort().join(", ")
 `),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
-			mapping, err := conf.FieldBloblang()
-			if err != nil {
-				return nil, err
-			}
-			return newMutation(mapping, mgr.Logger()), nil
+			return mutationFromConfig(conf, mgr)
 		})
 	if err != nil {
 		panic(err)
 	}
 }
 
+// mutationFieldsMarkdown renders the documentation of the fields of a mutation
+// processor that is configured as an object.
+func mutationFieldsMarkdown() string {
+	var buf bytes.Buffer
+	err := template.Must(template.New("mutation").Parse(docs.FieldsTemplate(false)+`{{template "field_docs" . -}}`)).Execute(&buf, struct {
+		Fields []docs.FieldSpecCtx
+	}{
+		Fields: docs.FieldObject("", "").WithChildren(mutationFields...).FlattenChildrenForDocs(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// lintMutationConfig lints the config of a mutation processor, which is either
+// a mapping or an object containing the mapping alongside other fields.
+func lintMutationConfig(ctx docs.LintContext, line, col int, v any) []docs.Lint {
+	switch v.(type) {
+	case string:
+		return docs.LintBloblangMapping(ctx, line, col, v)
+	case map[string]any:
+	default:
+		return []docs.Lint{docs.NewLintError(line, docs.LintExpectedObject, "expected string or object value")}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return []docs.Lint{docs.NewLintError(line, docs.LintExpectedObject, err.Error())}
+	}
+
+	// The encoded node has no position of its own and so lints are given
+	// relative to the line of the processor config.
+	lints := mutationFields.LintYAML(ctx, &node)
+	for i := range lints {
+		lints[i].Line += line
+	}
+	return lints
+}
+
+func mutationFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*mutationProc, error) {
+	v, err := conf.FieldAny()
+	if err != nil {
+		return nil, err
+	}
+	if _, isObj := v.(map[string]any); !isObj {
+		mapping, err := conf.FieldBloblang()
+		if err != nil {
+			return nil, err
+		}
+		return newMutation(mapping, 1, 0, false, mgr), nil
+	}
+
+	mapping, err := conf.FieldBloblang("mapping")
+	if err != nil {
+		return nil, err
+	}
+
+	threads := 1
+	if conf.Contains("threads") {
+		if threads, err = conf.FieldInt("threads"); err != nil {
+			return nil, err
+		}
+		if threads < 1 {
+			return nil, fmt.Errorf("threads must be greater than zero, got %v", threads)
+		}
+	}
+
+	var timeout time.Duration
+	if conf.Contains("timeout") {
+		if timeout, err = conf.FieldDuration("timeout"); err != nil {
+			return nil, err
+		}
+	}
+
+	var logDiff bool
+	if conf.Contains("log_diff") {
+		if logDiff, err = conf.FieldBool("log_diff"); err != nil {
+			return nil, err
+		}
+	}
+
+	proc := newMutation(mapping, threads, timeout, logDiff, mgr)
+	if conf.Contains("clear_metadata") {
+		if proc.clearMeta, err = conf.FieldBool("clear_metadata"); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains("keep_metadata") {
+		keepMeta, err := conf.FieldStringList("keep_metadata")
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keepMeta {
+			proc.keepMeta[k] = struct{}{}
+		}
+	}
+	return proc, nil
+}
+
 type mutationProc struct {
-	exec *bloblang.Executor
-	log  *service.Logger
+	exec    *bloblang.Executor
+	threads int
+	timeout time.Duration
+	running chan struct{}
+	logDiff bool
+	log     *service.Logger
+
+	clearMeta bool
+	keepMeta  map[string]struct{}
+
+	mProcessed *service.MetricCounter
+	mDeleted   *service.MetricCounter
+	mErrored   *service.MetricCounter
+	mLatency   *service.MetricTimer
 }
 
-func newMutation(exec *bloblang.Executor, log *service.Logger) service.BatchProcessor {
+func newMutation(exec *bloblang.Executor, threads int, timeout time.Duration, logDiff bool, mgr *service.Resources) *mutationProc {
 	return &mutationProc{
-		exec: exec,
-		log:  log,
+		exec:     exec,
+		threads:  threads,
+		timeout:  timeout,
+		running:  make(chan struct{}, threads),
+		logDiff:  logDiff,
+		log:      mgr.Logger(),
+		keepMeta: map[string]struct{}{},
+
+		mProcessed: mgr.Metrics().NewCounter("mutation_processed"),
+		mDeleted:   mgr.Metrics().NewCounter("mutation_deleted"),
+		mErrored:   mgr.Metrics().NewCounter("mutation_error"),
+		mLatency:   mgr.Metrics().NewTimer("mutation_latency_ns"),
 	}
 }
 
+// execMapping executes the mapping on a message of a batch. When a timeout is
+// configured the mapping is abandoned once it has elapsed or the context is
+// cancelled, otherwise the mapping is only skipped when the context is
+// cancelled before it begins.
+func (m *mutationProc) execMapping(ctx context.Context, batch service.MessageBatch, index int) (*service.Message, error) {
+	if m.timeout <= 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("mapping execution abandoned: %w", err)
+		}
+		return batch.BloblangMutate(index, m.exec)
+	}
+
+	ctx, done := context.WithTimeout(ctx, m.timeout)
+	defer done()
+
+	// Abandoned mappings cannot be interrupted, and so they keep hold of their
+	// slot until they complete, which caps the number of goroutines leaked by
+	// mappings that never complete.
+	select {
+	case m.running <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("mapping execution abandoned: %w", ctx.Err())
+	}
+
+	// An abandoned mapping continues to run in the background, so we execute
+	// it against a copy of the message in order to leave the original intact.
+	mapBatch := make(service.MessageBatch, len(batch))
+	copy(mapBatch, batch)
+	mapBatch[index] = batch[index].Copy()
+
+	type mappingResult struct {
+		msg *service.Message
+		err error
+	}
+	resChan := make(chan mappingResult, 1)
+	go func() {
+		defer func() { <-m.running }()
+		msg, err := mapBatch.BloblangMutate(index, m.exec)
+		resChan <- mappingResult{msg: msg, err: err}
+	}()
+
+	select {
+	case res := <-resChan:
+		return res.msg, res.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("mapping execution abandoned: %w", ctx.Err())
+	}
+}
+
+// mutate executes the mapping on a message of a batch, returning either the
+// mutated message, the original message flagged with an error, or nil if the
+// message was deleted.
+func (m *mutationProc) mutate(ctx context.Context, batch service.MessageBatch, index int) *service.Message {
+	var before *service.Message
+	if m.logDiff {
+		before = batch[index].DeepCopy()
+	}
+
+	t0 := time.Now()
+	newPart, err := m.execMapping(ctx, batch, index)
+	m.mLatency.Timing(time.Since(t0).Nanoseconds())
+	m.mProcessed.Incr(1)
+	if err != nil {
+		m.mErrored.Incr(1)
+		m.log.Error(err.Error())
+		batch[index].SetError(err)
+		return batch[index]
+	}
+	if newPart == nil {
+		m.mDeleted.Incr(1)
+		if m.logDiff {
+			m.log.Debug("Mapping deleted message")
+		}
+		return nil
+	}
+	if m.logDiff {
+		m.logMutationDiff(before, newPart)
+	}
+	if m.clearMeta {
+		m.clearMetadata(newPart)
+	}
+	return newPart
+}
+
+// clearMetadata removes all metadata from a message other than the keys that
+// are configured to be kept.
+func (m *mutationProc) clearMetadata(msg *service.Message) {
+	var keys []string
+	_ = msg.MetaWalkMut(func(key string, _ any) error {
+		if _, keep := m.keepMeta[key]; !keep {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	for _, k := range keys {
+		msg.MetaDelete(k)
+	}
+}
+
+func mutationDiffValue(msg *service.Message) any {
+	if v, err := msg.AsStructured(); err == nil {
+		return v
+	}
+	b, _ := msg.AsBytes()
+	return string(b)
+}
+
+func (m *mutationProc) logMutationDiff(before, after *service.Message) {
+	diff := map[string]any{}
+	mutationDiff("root", mutationDiffValue(before), mutationDiffValue(after), diff)
+	if len(diff) == 0 {
+		m.log.Debug("Mapping did not change message contents")
+		return
+	}
+	diffBytes, err := json.Marshal(diff)
+	if err != nil {
+		m.log.Debugf("Failed to marshal mapping diff: %v", err)
+		return
+	}
+	m.log.Debugf("Mapping diff: %s", diffBytes)
+}
+
+// mutationDiff walks two values and records the paths of object fields that
+// were added, removed or changed between them. Values other than objects are
+// compared as a whole.
+func mutationDiff(path string, before, after any, diff map[string]any) {
+	beforeObj, beforeIsObj := before.(map[string]any)
+	afterObj, afterIsObj := after.(map[string]any)
+	if !beforeIsObj || !afterIsObj {
+		if !reflect.DeepEqual(before, after) {
+			addMutationDiff(diff, "changed", path, map[string]any{
+				"from": before,
+				"to":   after,
+			})
+		}
+		return
+	}
+
+	for k, bv := range beforeObj {
+		av, exists := afterObj[k]
+		if !exists {
+			addMutationDiff(diff, "removed", path+"."+k, bv)
+			continue
+		}
+		mutationDiff(path+"."+k, bv, av, diff)
+	}
+	for k, av := range afterObj {
+		if _, exists := beforeObj[k]; !exists {
+			addMutationDiff(diff, "added", path+"."+k, av)
+		}
+	}
+}
+
+func addMutationDiff(diff map[string]any, kind, path string, v any) {
+	paths, _ := diff[kind].(map[string]any)
+	if paths == nil {
+		paths = map[string]any{}
+		diff[kind] = paths
+	}
+	paths[path] = v
+}
+
 func (m *mutationProc) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	results := make([]*service.Message, len(batch))
+
+	max := m.threads
+	if len(batch) < max {
+		max = len(batch)
+	}
+
+	if max <= 1 {
+		for i := range batch {
+			results[i] = m.mutate(ctx, batch, i)
+		}
+	} else {
+		reqChan := make(chan int)
+		wg := sync.WaitGroup{}
+		wg.Add(max)
+
+		for i := 0; i < max; i++ {
+			// The mapping of a message may read other messages of the batch
+			// (e.g. with the `from` method), and so each worker maps against
+			// its own copy of the batch rather than one that is being mutated
+			// by other workers. Each mutated message is replaced with a fresh
+			// copy of the original so that subsequent mappings of the worker
+			// observe the batch as it was before processing.
+			workerBatch := batch.Copy()
+			go func() {
+				for index := range reqChan {
+					results[index] = m.mutate(ctx, workerBatch, index)
+					workerBatch[index] = batch[index].Copy()
+				}
+				wg.Done()
+			}()
+		}
+		for i := range batch {
+			reqChan <- i
+		}
+		close(reqChan)
+		wg.Wait()
+	}
+
 	newBatch := make(service.MessageBatch, 0, len(batch))
-	for i, msg := range batch {
-		newPart, err := batch.BloblangMutate(i, m.exec)
-		if err != nil {
-			m.log.Error(err.Error())
-			msg.SetError(err)
+	for _, msg := range results {
+		if msg != nil {
 			newBatch = append(newBatch, msg)
-			continue
-		}
-		if newPart != nil {
-			newBatch = append(newBatch, newPart)
 		}
 	}
 	if len(newBatch) == 0 {
@@ -2,12 +2,20 @@ package pure
 
 import (
 	"context"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/gabs/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
 	"github.com/benthosdev/benthos/v4/public/bloblang"
 	"github.com/benthosdev/benthos/v4/public/service"
 )
@@ -40,7 +48,7 @@ meta baz = "new meta"
 `)
 	require.NoError(t, err)
 
-	proc := newMutation(exec, nil)
+	proc := newMutation(exec, 1, 0, false, service.MockResources())
 
 	outBatches, err := proc.ProcessBatch(tCtx, service.MessageBatch{inMsg, inMsg2})
 	require.NoError(t, err)
@@ -107,7 +115,7 @@ func TestMutationCreateCustomObject(t *testing.T) {
 	exec, err := bloblang.Parse(`root.foos = this.foos`)
 	require.NoError(t, err)
 
-	proc := newMutation(exec, nil)
+	proc := newMutation(exec, 1, 0, false, service.MockResources())
 
 	outBatches, err := proc.ProcessBatch(tCtx, service.MessageBatch{part})
 	require.NoError(t, err)
@@ -137,7 +145,7 @@ root = match {
 `)
 	require.NoError(t, err)
 
-	proc := newMutation(exec, nil)
+	proc := newMutation(exec, 1, 0, false, service.MockResources())
 
 	outBatches, err := proc.ProcessBatch(tCtx, inBatch)
 	require.NoError(t, err)
@@ -169,7 +177,7 @@ func TestMutationCreateFilterAll(t *testing.T) {
 	exec, err := bloblang.Parse(`root = deleted()`)
 	require.NoError(t, err)
 
-	proc := newMutation(exec, nil)
+	proc := newMutation(exec, 1, 0, false, service.MockResources())
 
 	outBatches, err := proc.ProcessBatch(tCtx, inBatch)
 	assert.NoError(t, err)
@@ -186,7 +194,7 @@ func TestMutationCreateJSONError(t *testing.T) {
 	exec, err := bloblang.Parse(`foo = json().bar`)
 	require.NoError(t, err)
 
-	proc := newMutation(exec, nil)
+	proc := newMutation(exec, 1, 0, false, service.MockResources())
 
 	outBatches, err := proc.ProcessBatch(tCtx, msg)
 	require.NoError(t, err)
@@ -201,3 +209,413 @@ func TestMutationCreateJSONError(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, `failed assignment (line 1): invalid character 'h' in literal true (expecting 'r')`, err.Error())
 }
+
+func TestMutationThreadsPreservesOrder(t *testing.T) {
+	tCtx := context.Background()
+
+	var inBatch service.MessageBatch
+	for i := 0; i < 100; i++ {
+		inBatch = append(inBatch, service.NewMessage([]byte(strconv.Itoa(i))))
+	}
+
+	exec, err := bloblang.Parse(`
+root = if this % 10 == 0 { deleted() } else if this % 10 == 1 { throw("nope") } else { this * 2 }
+`)
+	require.NoError(t, err)
+
+	proc := newMutation(exec, 8, 0, false, service.MockResources())
+
+	outBatches, err := proc.ProcessBatch(tCtx, inBatch)
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 90)
+
+	var j int
+	for i := 0; i < 100; i++ {
+		if i%10 == 0 {
+			continue
+		}
+		msg := outBatches[0][j]
+		j++
+
+		msgBytes, err := msg.AsBytes()
+		require.NoError(t, err)
+		if i%10 == 1 {
+			assert.Equal(t, strconv.Itoa(i), string(msgBytes))
+			assert.Error(t, msg.GetError())
+			continue
+		}
+		assert.Equal(t, strconv.Itoa(i*2), string(msgBytes))
+		assert.NoError(t, msg.GetError())
+	}
+}
+
+func TestMutationThreadsReadBatch(t *testing.T) {
+	tCtx := context.Background()
+
+	var inBatch service.MessageBatch
+	for i := 0; i < 100; i++ {
+		inBatch = append(inBatch, service.NewMessage([]byte(`{"n":`+strconv.Itoa(i)+`}`)))
+	}
+
+	exec, err := bloblang.Parse(`
+root.n = this.n + 1
+root.prev = if batch_index() > 0 { json("n").from(batch_index() - 1) }
+`)
+	require.NoError(t, err)
+
+	proc := newMutation(exec, 8, 0, false, service.MockResources())
+
+	outBatches, err := proc.ProcessBatch(tCtx, inBatch)
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 100)
+
+	// Each mapping observes the other messages of the batch as they were
+	// before any were mutated.
+	for i, msg := range outBatches[0] {
+		msgBytes, err := msg.AsBytes()
+		require.NoError(t, err)
+		if i == 0 {
+			assert.Equal(t, `{"n":1}`, string(msgBytes))
+			continue
+		}
+		assert.Equal(t, `{"n":`+strconv.Itoa(i+1)+`,"prev":`+strconv.Itoa(i-1)+`}`, string(msgBytes))
+	}
+}
+
+func TestMutationConfigLinting(t *testing.T) {
+	configTests := []struct {
+		name        string
+		config      string
+		errContains string
+	}{
+		{
+			name:   "mapping",
+			config: `mutation: 'root = content().uppercase()'`,
+		},
+		{
+			name: "object",
+			config: `
+mutation:
+  mapping: 'root = content().uppercase()'
+  threads: 2
+`,
+		},
+		{
+			name:        "bad mapping",
+			config:      `mutation: 'root = ('`,
+			errContains: "expected",
+		},
+		{
+			name: "object bad mapping",
+			config: `
+mutation:
+  mapping: 'root = ('
+`,
+			errContains: "expected",
+		},
+		{
+			name: "object missing mapping",
+			config: `
+mutation:
+  threads: 2
+`,
+			errContains: "field mapping is required",
+		},
+		{
+			name: "object unknown field",
+			config: `
+mutation:
+  mapping: 'root = this'
+  nope: true
+`,
+			errContains: "field nope not recognised",
+		},
+		{
+			name: "array",
+			config: `
+mutation:
+  - 'root = this'
+`,
+			errContains: "expected string or object value",
+		},
+	}
+
+	env := service.NewEnvironment()
+	for _, test := range configTests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			strm := env.NewStreamBuilder()
+			err := strm.AddProcessorYAML(test.config)
+			if test.errContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+			}
+		})
+	}
+}
+
+func TestMutationConfigShorthand(t *testing.T) {
+	for _, conf := range []string{
+		`mutation: 'root = content().uppercase()'`,
+		`
+mutation:
+  mapping: 'root = content().uppercase()'
+  threads: 2
+`,
+	} {
+		pConf := processor.NewConfig()
+		require.NoError(t, yaml.Unmarshal([]byte(conf), &pConf))
+
+		proc, err := mock.NewManager().NewProcessor(pConf)
+		require.NoError(t, err)
+
+		outBatches, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{[]byte("foo"), []byte("bar")}))
+		require.NoError(t, err)
+		require.Len(t, outBatches, 1)
+		assert.Equal(t, "FOO", string(outBatches[0].Get(0).AsBytes()))
+		assert.Equal(t, "BAR", string(outBatches[0].Get(1).AsBytes()))
+	}
+}
+
+func TestMutationTimeout(t *testing.T) {
+	tCtx := context.Background()
+
+	exec, err := bloblang.Parse(`
+root.sum = range(0, 10000000).fold(0, item -> item.tally + item.value)
+`)
+	require.NoError(t, err)
+
+	proc := newMutation(exec, 1, time.Millisecond*10, false, service.MockResources())
+
+	outBatches, err := proc.ProcessBatch(tCtx, service.MessageBatch{
+		service.NewMessage([]byte(`{"foo":"bar"}`)),
+	})
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 1)
+
+	msgBytes, err := outBatches[0][0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":"bar"}`, string(msgBytes))
+	assert.ErrorIs(t, outBatches[0][0].GetError(), context.DeadlineExceeded)
+
+	exec, err = bloblang.Parse(`root.foo = this.foo.uppercase()`)
+	require.NoError(t, err)
+
+	proc = newMutation(exec, 1, time.Second*10, false, service.MockResources())
+
+	outBatches, err = proc.ProcessBatch(tCtx, service.MessageBatch{
+		service.NewMessage([]byte(`{"foo":"bar"}`)),
+	})
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 1)
+
+	msgBytes, err = outBatches[0][0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":"BAR"}`, string(msgBytes))
+	assert.NoError(t, outBatches[0][0].GetError())
+}
+
+func TestMutationCancelledContext(t *testing.T) {
+	exec, err := bloblang.Parse(`root.foo = this.foo.uppercase()`)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, timeout := range []time.Duration{0, time.Second * 10} {
+		proc := newMutation(exec, 1, timeout, false, service.MockResources())
+
+		outBatches, err := proc.ProcessBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte(`{"foo":"bar"}`)),
+		})
+		require.NoError(t, err)
+		require.Len(t, outBatches, 1)
+		require.Len(t, outBatches[0], 1)
+
+		msgBytes, err := outBatches[0][0].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, `{"foo":"bar"}`, string(msgBytes), timeout)
+		assert.ErrorIs(t, outBatches[0][0].GetError(), context.Canceled, timeout)
+	}
+}
+
+func TestMutationTimeoutCapsAbandoned(t *testing.T) {
+	var running int32
+	release := make(chan struct{})
+
+	env := bloblang.NewEnvironment()
+	require.NoError(t, env.RegisterFunction("block", func(args ...any) (bloblang.Function, error) {
+		return func() (any, error) {
+			atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			<-release
+			return "done", nil
+		}, nil
+	}))
+
+	exec, err := env.Parse(`root.foo = block()`)
+	require.NoError(t, err)
+
+	proc := newMutation(exec, 2, time.Millisecond*10, false, service.MockResources())
+
+	var batch service.MessageBatch
+	for i := 0; i < 10; i++ {
+		batch = append(batch, service.NewMessage([]byte(`{"foo":"bar"}`)))
+	}
+
+	outBatches, err := proc.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 10)
+	for _, msg := range outBatches[0] {
+		assert.ErrorIs(t, msg.GetError(), context.DeadlineExceeded)
+	}
+
+	// Only as many mappings as there are threads should have been started.
+	assert.Equal(t, int32(2), atomic.LoadInt32(&running))
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&running) == 0
+	}, time.Second*5, time.Millisecond)
+
+	outBatches, err = proc.ProcessBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"foo":"bar"}`)),
+	})
+	require.NoError(t, err)
+
+	msgBytes, err := outBatches[0][0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":"done"}`, string(msgBytes))
+}
+
+func TestMutationMetrics(t *testing.T) {
+	pConf := processor.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+mutation: 'root = if content() == "delete" { deleted() } else if content() == "error" { throw("nope") }'
+`), &pConf))
+
+	mockMetrics := metrics.NewLocal()
+
+	mgr := mock.NewManager()
+	mgr.M = mockMetrics
+
+	proc, err := mgr.NewProcessor(pConf)
+	require.NoError(t, err)
+
+	outBatches, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("delete"), []byte("error"), []byte("bar"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	assert.Equal(t, 3, outBatches[0].Len())
+
+	counters := mockMetrics.GetCounters()
+	assert.Equal(t, int64(4), counters["mutation_processed"])
+	assert.Equal(t, int64(1), counters["mutation_deleted"])
+	assert.Equal(t, int64(1), counters["mutation_error"])
+	assert.Contains(t, mockMetrics.GetTimings(), "mutation_latency_ns")
+}
+
+func TestMutationDiff(t *testing.T) {
+	diff := map[string]any{}
+	mutationDiff("root", map[string]any{
+		"a": "foo",
+		"b": map[string]any{
+			"c": "bar",
+			"d": "baz",
+		},
+		"e": "buz",
+	}, map[string]any{
+		"a": "foo",
+		"b": map[string]any{
+			"c": "BAR",
+			"f": "new",
+		},
+	}, diff)
+
+	assert.Equal(t, map[string]any{
+		"added": map[string]any{
+			"root.b.f": "new",
+		},
+		"removed": map[string]any{
+			"root.b.d": "baz",
+			"root.e":   "buz",
+		},
+		"changed": map[string]any{
+			"root.b.c": map[string]any{"from": "bar", "to": "BAR"},
+		},
+	}, diff)
+
+	diff = map[string]any{}
+	mutationDiff("root", "foo", map[string]any{"a": "foo"}, diff)
+	assert.Equal(t, map[string]any{
+		"changed": map[string]any{
+			"root": map[string]any{"from": "foo", "to": map[string]any{"a": "foo"}},
+		},
+	}, diff)
+}
+
+func TestMutationClearMetadata(t *testing.T) {
+	exec, err := bloblang.Parse(`
+root.foo = "bar"
+meta added = "yes"
+`)
+	require.NoError(t, err)
+
+	proc := newMutation(exec, 1, 0, false, service.MockResources())
+	proc.clearMeta = true
+	proc.keepMeta["kept"] = struct{}{}
+	proc.keepMeta["added"] = struct{}{}
+
+	inMsg := service.NewMessage([]byte(`{}`))
+	inMsg.MetaSet("kept", "a")
+	inMsg.MetaSet("removed", "b")
+
+	outBatches, err := proc.ProcessBatch(context.Background(), service.MessageBatch{inMsg})
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 1)
+
+	meta := map[string]string{}
+	require.NoError(t, outBatches[0][0].MetaWalk(func(k, v string) error {
+		meta[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]string{
+		"kept":  "a",
+		"added": "yes",
+	}, meta)
+}
+
+func TestMutationClearMetadataConfig(t *testing.T) {
+	pConf := processor.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+mutation:
+  mapping: 'root = this'
+  clear_metadata: true
+  keep_metadata: [ kept ]
+`), &pConf))
+
+	proc, err := mock.NewManager().NewProcessor(pConf)
+	require.NoError(t, err)
+
+	part := message.NewPart([]byte(`{}`))
+	part.MetaSetMut("kept", "a")
+	part.MetaSetMut("removed", "b")
+
+	outBatches, err := proc.ProcessBatch(context.Background(), message.Batch{part})
+	require.NoError(t, err)
+	require.Len(t, outBatches, 1)
+	require.Len(t, outBatches[0], 1)
+
+	assert.Equal(t, "a", outBatches[0].Get(0).MetaGetStr("kept"))
+	_, exists := outBatches[0].Get(0).MetaGetMut("removed")
+	assert.False(t, exists)
+}
@@ -0,0 +1,239 @@
+package pure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	vfpFieldFields       = "fields"
+	vfpFieldName         = "name"
+	vfpFieldType         = "type"
+	vfpFieldKind         = "kind"
+	vfpFieldOptional     = "optional"
+	vfpFieldChildren     = "children"
+	vfpFieldAllowUnknown = "allow_unknown"
+)
+
+func validateFieldsProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.11.0").
+		Categories("Utility").
+		Summary("Checks that messages are JSON documents matching a declared set of fields but does not change the payload under any circumstances.").
+		Description(`
+Fields are declared in the same way as the fields of [templates](/docs/configuration/templating), with the addition of `+"`children`"+` for declaring the fields of nested objects. Each message is checked using the same rules as config linting, where a field is expected to be present unless it is optional, and scalar values must match the type of their field. Values of the type `+"`float`"+` may also be integers.
+
+Messages that are not valid JSON, or do not match the declared fields, are flagged as having failed with an error listing each problem found along with its line and column within the message. These messages can be caught using error handling methods outlined [here](/docs/configuration/error_handling).
+
+This processor does not support the full JSON Schema specification, and is intended for simple checks that do not warrant the `+"[`json_schema` processor](/docs/components/processors/json_schema)"+`.`).
+		Field(service.NewObjectListField(vfpFieldFields,
+			service.NewStringField(vfpFieldName).
+				Description("The name of the field."),
+			service.NewStringEnumField(vfpFieldType, "string", "int", "float", "bool", "object", "unknown").
+				Description("The scalar type of the field, where `unknown` accepts any value."),
+			service.NewStringEnumField(vfpFieldKind, "scalar", "list", "map").
+				Description("The kind of the field, where `list` and `map` fields contain any number of values of the field type.").
+				Default("scalar"),
+			service.NewBoolField(vfpFieldOptional).
+				Description("Whether the field can be omitted.").
+				Default(false),
+			service.NewAnyListField(vfpFieldChildren).
+				Description("The fields of an `object` typed field, declared in the same format as this list.").
+				Optional(),
+		).Description("The fields that each message must match.")).
+		Field(service.NewBoolField(vfpFieldAllowUnknown).
+			Description("Whether objects may contain fields that have not been declared.").
+			Advanced().
+			Default(false)).
+		Example("Order Events", `
+Here we check that order events contain an ID, a list of tags and a customer object, and drop any that do not after logging the reason:`, `
+pipeline:
+  processors:
+    - validate_fields:
+        fields:
+          - name: id
+            type: string
+          - name: tags
+            type: string
+            kind: list
+            optional: true
+          - name: customer
+            type: object
+            children:
+              - name: name
+                type: string
+              - name: age
+                type: int
+                optional: true
+    - catch:
+        - log:
+            level: ERROR
+            message: "Validation failed due to: ${!error()}"
+        - mapping: 'root = deleted()'
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"validate_fields", validateFieldsProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newValidateFieldsFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// validateFieldSpecFromParsed converts a declared field into a docs field spec,
+// recursing into the declared children of object fields. Children are parsed
+// without the defaults of the top level fields as their structure cannot be
+// expressed in the config spec.
+func validateFieldSpecFromParsed(conf *service.ParsedConfig) (f docs.FieldSpec, err error) {
+	var name, fType string
+	if name, err = conf.FieldString(vfpFieldName); err != nil {
+		return
+	}
+	if fType, err = conf.FieldString(vfpFieldType); err != nil {
+		return
+	}
+	switch fType {
+	case "string", "int", "float", "bool", "object", "unknown":
+	default:
+		return f, fmt.Errorf("field %v: unrecognised type: %v", name, fType)
+	}
+	f = docs.FieldAnything(name, "").HasType(docs.FieldType(fType))
+
+	if conf.Contains(vfpFieldKind) {
+		var kind string
+		if kind, err = conf.FieldString(vfpFieldKind); err != nil {
+			return
+		}
+		switch kind {
+		case "scalar":
+		case "list":
+			f = f.Array()
+		case "map":
+			f = f.Map()
+		default:
+			return f, fmt.Errorf("field %v: unrecognised kind: %v", name, kind)
+		}
+	}
+
+	if conf.Contains(vfpFieldOptional) {
+		var optional bool
+		if optional, err = conf.FieldBool(vfpFieldOptional); err != nil {
+			return
+		}
+		if optional {
+			f = f.Optional()
+		}
+	}
+
+	if conf.Contains(vfpFieldChildren) {
+		var childConfs []*service.ParsedConfig
+		if childConfs, err = conf.FieldAnyList(vfpFieldChildren); err != nil {
+			return
+		}
+		if len(childConfs) == 0 {
+			return
+		}
+		if fType != "object" {
+			return f, fmt.Errorf("field %v: children can only be declared for object fields", name)
+		}
+		children := make([]docs.FieldSpec, len(childConfs))
+		for i, c := range childConfs {
+			if children[i], err = validateFieldSpecFromParsed(c); err != nil {
+				return f, fmt.Errorf("field %v: %w", name, err)
+			}
+		}
+		f = f.WithChildren(children...)
+	}
+	return
+}
+
+type validateFieldsProc struct {
+	spec         docs.FieldSpec
+	allowUnknown bool
+}
+
+func newValidateFieldsFromParsed(conf *service.ParsedConfig) (*validateFieldsProc, error) {
+	fieldConfs, err := conf.FieldObjectList(vfpFieldFields)
+	if err != nil {
+		return nil, err
+	}
+	children := make([]docs.FieldSpec, len(fieldConfs))
+	for i, c := range fieldConfs {
+		if children[i], err = validateFieldSpecFromParsed(c); err != nil {
+			return nil, err
+		}
+	}
+
+	v := &validateFieldsProc{
+		spec: docs.FieldObject("", "").WithChildren(children...),
+	}
+	if v.allowUnknown, err = conf.FieldBool(vfpFieldAllowUnknown); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// validate returns the lints of a JSON document against the declared fields.
+func (v *validateFieldsProc) validate(body []byte) ([]docs.Lint, error) {
+	if !json.Valid(body) {
+		return nil, errors.New("message is not a valid JSON document")
+	}
+
+	// JSON documents are also valid YAML, which gives us nodes with line and
+	// column positions to lint against.
+	var node yaml.Node
+	if err := yaml.Unmarshal(body, &node); err != nil {
+		return nil, err
+	}
+
+	lintCtx := docs.NewLintContext()
+	lintCtx.StrictStructure = true
+
+	var lints []docs.Lint
+	for _, l := range v.spec.LintYAML(lintCtx, &node) {
+		if v.allowUnknown && l.Type == docs.LintUnknown {
+			continue
+		}
+		lints = append(lints, l)
+	}
+	return lints, nil
+}
+
+func (v *validateFieldsProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	body, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	lints, err := v.validate(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(lints) > 0 {
+		errs := make([]string, len(lints))
+		for i, l := range lints {
+			errs[i] = l.Error()
+		}
+		msg.SetError(fmt.Errorf("message failed validation: %v", strings.Join(errs, ", ")))
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (v *validateFieldsProc) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,132 @@
+package pure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func TestValidateFields(t *testing.T) {
+	conf, err := validateFieldsProcConfig().ParseYAML(`
+fields:
+  - name: id
+    type: string
+  - name: count
+    type: int
+    optional: true
+  - name: tags
+    type: string
+    kind: list
+    optional: true
+  - name: customer
+    type: object
+    children:
+      - name: name
+        type: string
+      - name: score
+        type: float
+        optional: true
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newValidateFieldsFromParsed(conf)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "valid",
+			input: `{"id":"foo","count":5,"tags":["a","b"],"customer":{"name":"bar","score":10}}`,
+		},
+		{
+			name:  "valid without optional fields",
+			input: `{"id":"foo","customer":{"name":"bar"}}`,
+		},
+		{
+			name:  "missing fields",
+			input: `{"count":5}`,
+			err:   "message failed validation: (1,1) field id is required, (1,1) field customer is required",
+		},
+		{
+			name:  "wrong scalar types",
+			input: `{"id":10,"count":"5","customer":{"name":"bar","score":true}}`,
+			err:   "message failed validation: (1,7) expected string value, got 10, (1,18) expected int value, got 5, (1,55) expected float value, got true",
+		},
+		{
+			name: "wrong kinds",
+			input: `{
+  "id": "foo",
+  "tags": "a",
+  "customer": ["bar"]
+}`,
+			err: "message failed validation: (3,1) expected array value, (4,1) expected object value",
+		},
+		{
+			name:  "unknown field",
+			input: `{"id":"foo","customer":{"name":"bar","nope":true}}`,
+			err:   "message failed validation: (1,1) field nope not recognised",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			batch, err := proc.Process(context.Background(), service.NewMessage([]byte(test.input)))
+			require.NoError(t, err)
+			require.Len(t, batch, 1)
+
+			body, err := batch[0].AsBytes()
+			require.NoError(t, err)
+			assert.Equal(t, test.input, string(body))
+
+			if test.err == "" {
+				assert.NoError(t, batch[0].GetError())
+			} else {
+				assert.EqualError(t, batch[0].GetError(), test.err)
+			}
+		})
+	}
+
+	_, err = proc.Process(context.Background(), service.NewMessage([]byte(`id: foo`)))
+	require.EqualError(t, err, "message is not a valid JSON document")
+}
+
+func TestValidateFieldsAllowUnknown(t *testing.T) {
+	conf, err := validateFieldsProcConfig().ParseYAML(`
+fields:
+  - name: id
+    type: string
+allow_unknown: true
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newValidateFieldsFromParsed(conf)
+	require.NoError(t, err)
+
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte(`{"id":"foo","bar":"baz"}`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	assert.NoError(t, batch[0].GetError())
+}
+
+func TestValidateFieldsBadChildren(t *testing.T) {
+	conf, err := validateFieldsProcConfig().ParseYAML(`
+fields:
+  - name: id
+    type: string
+    children:
+      - name: foo
+        type: string
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newValidateFieldsFromParsed(conf)
+	require.EqualError(t, err, "field id: children can only be declared for object fields")
+}
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"fmt"
+
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
@@ -29,9 +31,61 @@ func NewResourceConfig() ResourceConfig {
 	}
 }
 
+// ResourceLabel identifies a resource by its kind, such as `cache`, and label.
+type ResourceLabel struct {
+	Kind  string
+	Label string
+}
+
+// ResourceLabels returns the labels of all resources within the config.
+func (r *ResourceConfig) ResourceLabels() []ResourceLabel {
+	var labels []ResourceLabel
+	for _, c := range r.ResourceInputs {
+		labels = append(labels, ResourceLabel{Kind: "input", Label: c.Label})
+	}
+	for _, c := range r.ResourceProcessors {
+		labels = append(labels, ResourceLabel{Kind: "processor", Label: c.Label})
+	}
+	for _, c := range r.ResourceOutputs {
+		labels = append(labels, ResourceLabel{Kind: "output", Label: c.Label})
+	}
+	for _, c := range r.ResourceCaches {
+		labels = append(labels, ResourceLabel{Kind: "cache", Label: c.Label})
+	}
+	for _, c := range r.ResourceRateLimits {
+		labels = append(labels, ResourceLabel{Kind: "rate_limit", Label: c.Label})
+	}
+	return labels
+}
+
+// ErrResourceLabelCollision is returned when merging resource configs that
+// both contain a resource of the same kind and label.
+type ErrResourceLabelCollision struct {
+	ResourceLabel
+}
+
+// Error returns a human readable error string.
+func (e *ErrResourceLabelCollision) Error() string {
+	return fmt.Sprintf("%v resource label %v is defined more than once", e.Kind, e.Label)
+}
+
 // AddFrom takes another Config and adds all of its resources to itself. If
-// there are any resource name collisions an error is returned.
+// there are any resource name collisions an error is returned and neither
+// config is modified.
 func (r *ResourceConfig) AddFrom(extra *ResourceConfig) error {
+	existing := map[ResourceLabel]struct{}{}
+	for _, l := range r.ResourceLabels() {
+		existing[l] = struct{}{}
+	}
+	for _, l := range extra.ResourceLabels() {
+		if l.Label == "" {
+			continue
+		}
+		if _, exists := existing[l]; exists {
+			return &ErrResourceLabelCollision{ResourceLabel: l}
+		}
+	}
+
 	r.ResourceInputs = append(r.ResourceInputs, extra.ResourceInputs...)
 	r.ResourceProcessors = append(r.ResourceProcessors, extra.ResourceProcessors...)
 	r.ResourceOutputs = append(r.ResourceOutputs, extra.ResourceOutputs...)
@@ -0,0 +1,49 @@
+package manager_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+)
+
+func TestResourceConfigAddFrom(t *testing.T) {
+	newCache := func(label string) cache.Config {
+		c := cache.NewConfig()
+		c.Label = label
+		return c
+	}
+
+	conf := manager.NewResourceConfig()
+	conf.ResourceCaches = append(conf.ResourceCaches, newCache("foo"))
+
+	extra := manager.NewResourceConfig()
+	extra.ResourceCaches = append(extra.ResourceCaches, newCache("bar"))
+	rl := ratelimit.NewConfig()
+	rl.Label = "foo"
+	extra.ResourceRateLimits = append(extra.ResourceRateLimits, rl)
+
+	require.NoError(t, conf.AddFrom(&extra))
+	assert.Equal(t, []manager.ResourceLabel{
+		{Kind: "cache", Label: "foo"},
+		{Kind: "cache", Label: "bar"},
+		{Kind: "rate_limit", Label: "foo"},
+	}, conf.ResourceLabels())
+
+	collision := manager.NewResourceConfig()
+	collision.ResourceCaches = append(collision.ResourceCaches, newCache("baz"), newCache("bar"))
+
+	err := conf.AddFrom(&collision)
+	require.EqualError(t, err, "cache resource label bar is defined more than once")
+
+	var cErr *manager.ErrResourceLabelCollision
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, manager.ResourceLabel{Kind: "cache", Label: "bar"}, cErr.ResourceLabel)
+
+	// The config is not modified when a collision is found.
+	assert.Len(t, conf.ResourceCaches, 2)
+}
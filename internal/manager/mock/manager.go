@@ -81,6 +81,12 @@ func (m *Manager) StoreCache(ctx context.Context, name string, conf cache.Config
 	return component.ErrInvalidType("cache", conf.Type)
 }
 
+// RemoveCache removes a mock cache resource if it exists.
+func (m *Manager) RemoveCache(ctx context.Context, name string) error {
+	delete(m.Caches, name)
+	return nil
+}
+
 // NewInput always errors on invalid type.
 func (m *Manager) NewInput(conf input.Config) (input.Streamed, error) {
 	return bundle.AllInputs.Init(conf, m)
@@ -91,6 +97,12 @@ func (m *Manager) StoreInput(ctx context.Context, name string, conf input.Config
 	return component.ErrInvalidType("input", conf.Type)
 }
 
+// RemoveInput removes a mock input resource if it exists.
+func (m *Manager) RemoveInput(ctx context.Context, name string) error {
+	delete(m.Inputs, name)
+	return nil
+}
+
 // NewProcessor always errors on invalid type.
 func (m *Manager) NewProcessor(conf processor.Config) (processor.V1, error) {
 	return bundle.AllProcessors.Init(conf, m)
@@ -101,6 +113,12 @@ func (m *Manager) StoreProcessor(ctx context.Context, name string, conf processo
 	return component.ErrInvalidType("processor", conf.Type)
 }
 
+// RemoveProcessor removes a mock processor resource if it exists.
+func (m *Manager) RemoveProcessor(ctx context.Context, name string) error {
+	delete(m.Processors, name)
+	return nil
+}
+
 // NewOutput always errors on invalid type.
 func (m *Manager) NewOutput(conf output.Config, pipelines ...processor.PipelineConstructorFunc) (output.Streamed, error) {
 	return bundle.AllOutputs.Init(conf, m, pipelines...)
@@ -111,6 +129,12 @@ func (m *Manager) StoreOutput(ctx context.Context, name string, conf output.Conf
 	return component.ErrInvalidType("output", conf.Type)
 }
 
+// RemoveOutput removes a mock output resource if it exists.
+func (m *Manager) RemoveOutput(ctx context.Context, name string) error {
+	delete(m.Outputs, name)
+	return nil
+}
+
 // NewRateLimit always errors on invalid type.
 func (m *Manager) NewRateLimit(conf ratelimit.Config) (ratelimit.V1, error) {
 	return bundle.AllRateLimits.Init(conf, m)
@@ -121,6 +145,12 @@ func (m *Manager) StoreRateLimit(ctx context.Context, name string, conf ratelimi
 	return component.ErrInvalidType("rate_limit", conf.Type)
 }
 
+// RemoveRateLimit removes a mock rate limit resource if it exists.
+func (m *Manager) RemoveRateLimit(ctx context.Context, name string) error {
+	delete(m.RateLimits, name)
+	return nil
+}
+
 // Path always returns empty.
 func (m *Manager) Path() []string { return nil }
 
@@ -499,6 +499,25 @@ func (t *Type) StoreCache(ctx context.Context, name string, conf cache.Config) e
 	return nil
 }
 
+// RemoveCache attempts to close and remove an existing cache resource.
+func (t *Type) RemoveCache(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	c, ok := t.caches[name]
+	if !ok {
+		return ErrResourceNotFound(name)
+	}
+	if c != nil {
+		if err := c.Close(ctx); err != nil {
+			return err
+		}
+	}
+
+	delete(t.caches, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ProbeInput returns true if an input resource exists under the provided name.
@@ -566,6 +585,26 @@ func (t *Type) StoreInput(ctx context.Context, name string, conf input.Config) e
 	return nil
 }
 
+// RemoveInput attempts to close and remove an existing input resource.
+func (t *Type) RemoveInput(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	i, exists := t.inputs[name]
+	if !exists {
+		return ErrResourceNotFound(name)
+	}
+	if i != nil {
+		i.TriggerStopConsuming()
+		if err := i.WaitForClose(ctx); err != nil {
+			return err
+		}
+	}
+
+	delete(t.inputs, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ProbeProcessor returns true if a processor resource exists under the provided
@@ -631,6 +670,25 @@ func (t *Type) StoreProcessor(ctx context.Context, name string, conf processor.C
 	return nil
 }
 
+// RemoveProcessor attempts to close and remove an existing processor resource.
+func (t *Type) RemoveProcessor(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	p, ok := t.processors[name]
+	if !ok {
+		return ErrResourceNotFound(name)
+	}
+	if p != nil {
+		if err := p.Close(ctx); err != nil {
+			return err
+		}
+	}
+
+	delete(t.processors, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ProbeOutput returns true if an output resource exists under the provided
@@ -699,6 +757,26 @@ func (t *Type) StoreOutput(ctx context.Context, name string, conf output.Config)
 	return nil
 }
 
+// RemoveOutput attempts to close and remove an existing output resource.
+func (t *Type) RemoveOutput(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	o, ok := t.outputs[name]
+	if !ok {
+		return ErrResourceNotFound(name)
+	}
+	if o != nil {
+		o.TriggerStopConsuming()
+		if err := o.WaitForClose(ctx); err != nil {
+			return err
+		}
+	}
+
+	delete(t.outputs, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // ProbeRateLimit returns true if a rate limit resource exists under the
@@ -760,6 +838,26 @@ func (t *Type) StoreRateLimit(ctx context.Context, name string, conf ratelimit.C
 	return nil
 }
 
+// RemoveRateLimit attempts to close and remove an existing rate limit
+// resource.
+func (t *Type) RemoveRateLimit(ctx context.Context, name string) error {
+	t.resourceLock.Lock()
+	defer t.resourceLock.Unlock()
+
+	r, ok := t.rateLimits[name]
+	if !ok {
+		return ErrResourceNotFound(name)
+	}
+	if r != nil {
+		if err := r.Close(ctx); err != nil {
+			return err
+		}
+	}
+
+	delete(t.rateLimits, name)
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // TriggerStopConsuming instructs the manager to stop resource inputs and
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"sort"
 	"strings"
-	"sync"
 
 	"github.com/gorilla/mux"
 	"gopkg.in/yaml.v3"
@@ -178,79 +177,7 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	toDelete := []string{}
-	toUpdate := map[string]stream.Config{}
-	toCreate := map[string]stream.Config{}
-
-	for id := range infos {
-		if newConf, exists := newSet[id]; !exists {
-			toDelete = append(toDelete, id)
-		} else {
-			toUpdate[id] = newConf
-		}
-	}
-	for id, conf := range newSet {
-		if _, exists := infos[id]; !exists {
-			toCreate[id] = conf
-		}
-	}
-
-	wg := sync.WaitGroup{}
-	wg.Add(len(toDelete))
-	wg.Add(len(toUpdate))
-	wg.Add(len(toCreate))
-
-	errDelete := make([]error, len(toDelete))
-	errUpdate := make([]error, len(toUpdate))
-	errCreate := make([]error, len(toCreate))
-
-	for i, id := range toDelete {
-		go func(sid string, j int) {
-			errDelete[j] = m.Delete(r.Context(), sid)
-			wg.Done()
-		}(id, i)
-	}
-	i := 0
-	for id, conf := range toUpdate {
-		newConf := conf
-		go func(sid string, sconf *stream.Config, j int) {
-			errUpdate[j] = m.Update(r.Context(), sid, *sconf)
-			wg.Done()
-		}(id, &newConf, i)
-		i++
-	}
-	i = 0
-	for id, conf := range toCreate {
-		newConf := conf
-		go func(sid string, sconf *stream.Config, j int) {
-			errCreate[j] = m.Create(sid, *sconf)
-			wg.Done()
-		}(id, &newConf, i)
-		i++
-	}
-
-	wg.Wait()
-
-	errs := []string{}
-	for _, err := range errDelete {
-		if err != nil {
-			errs = append(errs, fmt.Sprintf("failed to delete stream: %v", err))
-		}
-	}
-	for _, err := range errUpdate {
-		if err != nil {
-			errs = append(errs, fmt.Sprintf("failed to update stream: %v", err))
-		}
-	}
-	for _, err := range errCreate {
-		if err != nil {
-			errs = append(errs, fmt.Sprintf("failed to create stream: %v", err))
-		}
-	}
-
-	if len(errs) > 0 {
-		requestErr = errors.New(strings.Join(errs, "\n"))
-	}
+	requestErr = m.Reconcile(r.Context(), newSet)
 }
 
 // HandleStreamCRUD is an http.HandleFunc for performing CRUD operations on
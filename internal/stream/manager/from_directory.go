@@ -1,75 +1,280 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/filepath/ifs"
 	"github.com/benthosdev/benthos/v4/internal/stream"
 )
 
 //------------------------------------------------------------------------------
 
-// LoadStreamConfigsFromDirectory reads a map of stream ids to configurations
-// by walking a directory of .json and .yaml files.
-//
-// Deprecated: The streams builder is using ./internal/config now.
-func LoadStreamConfigsFromDirectory(replaceEnvVars bool, dir string) (map[string]stream.Config, error) {
-	streamMap := map[string]stream.Config{}
+type directoryConfig struct {
+	nestedIDs   bool
+	idTransform func(id string) string
+}
 
-	dir = filepath.Clean(dir)
+// DirectoryOpt is an option for loading stream configs from a directory.
+type DirectoryOpt func(*directoryConfig)
 
-	if info, err := ifs.OS().Stat(dir); err != nil {
-		if os.IsNotExist(err) {
-			return streamMap, nil
+// OptDirectoryNestedIDs sets whether stream ids derived from files within
+// nested directories preserve the directory structure with forward slash
+// separators, e.g. `a/b/c.yaml` becomes `a/b/c`. Since slashes cannot appear
+// within file names these ids are unambiguous. This is disabled by default, in
+// which case separators are replaced with underscores, e.g. `a_b_c`.
+func OptDirectoryNestedIDs(b bool) DirectoryOpt {
+	return func(c *directoryConfig) {
+		c.nestedIDs = b
+	}
+}
+
+// OptDirectoryIDTransform sets a function that customises the stream id of each
+// file, which is called with the id derived from the path of the file and
+// returns the id to use instead, e.g. in order to prefix all ids with the name
+// of an environment. Ids are still checked for collisions after being
+// transformed, and a transform resulting in an empty id is an error.
+func OptDirectoryIDTransform(fn func(id string) string) DirectoryOpt {
+	return func(c *directoryConfig) {
+		c.idTransform = fn
+	}
+}
+
+// streamIDFromPath derives a stream id from the path of a config file relative
+// to a directory, returning false if the file is not a stream config.
+func streamIDFromPath(dir, path string, dConf directoryConfig) (string, bool, error) {
+	var ext string
+	switch {
+	case strings.HasSuffix(path, ".yaml"):
+		ext = ".yaml"
+	case strings.HasSuffix(path, ".json"):
+		ext = ".json"
+	case strings.HasSuffix(path, ".yaml.gz"):
+		ext = ".yaml.gz"
+	case strings.HasSuffix(path, ".json.gz"):
+		ext = ".json.gz"
+	default:
+		return "", false, nil
+	}
+
+	id, err := filepath.Rel(dir, path)
+	if err != nil {
+		return "", false, err
+	}
+	id = strings.Trim(id, string(filepath.Separator))
+	if dConf.nestedIDs {
+		id = filepath.ToSlash(id)
+	} else {
+		id = strings.ReplaceAll(id, string(filepath.Separator), "_")
+	}
+	id = strings.TrimSuffix(id, ext)
+	if dConf.idTransform != nil {
+		if id = dConf.idTransform(id); id == "" {
+			return "", false, fmt.Errorf("stream id of file %v transformed to an empty string", path)
 		}
-		return nil, err
-	} else if !info.IsDir() {
-		return streamMap, nil
 	}
+	return id, true, nil
+}
 
-	err := fs.WalkDir(ifs.OS(), dir, func(path string, info fs.DirEntry, werr error) error {
+// walkStreamConfigFiles walks a directory and calls a closure with the stream id
+// and path of each stream config file found.
+func walkStreamConfigFiles(dir string, dConf directoryConfig, fn func(id, path string) error) error {
+	return fs.WalkDir(ifs.OS(), dir, func(path string, info fs.DirEntry, werr error) error {
 		if werr != nil {
 			return werr
 		}
-		if info.IsDir() ||
-			(!strings.HasSuffix(info.Name(), ".yaml") &&
-				!strings.HasSuffix(info.Name(), ".json")) {
+		if info.IsDir() {
 			return nil
 		}
 
-		var id string
-		if id, werr = filepath.Rel(dir, path); werr != nil {
+		id, isStream, werr := streamIDFromPath(dir, path, dConf)
+		if werr != nil {
 			return werr
 		}
-		id = strings.Trim(id, string(filepath.Separator))
-		id = strings.ReplaceAll(id, string(filepath.Separator), "_")
+		if !isStream {
+			return nil
+		}
+		return fn(id, path)
+	})
+}
 
-		if strings.HasSuffix(info.Name(), ".yaml") {
-			id = strings.TrimSuffix(id, ".yaml")
-		} else {
-			id = strings.TrimSuffix(id, ".json")
+// LoadStreamConfigsFromDirectory reads a map of stream ids to configurations
+// by walking a directory of .json and .yaml files, which may also be gzip
+// compressed with the extensions .json.gz and .yaml.gz. Any linting issues
+// found within the files are returned keyed by the stream id of the file.
+//
+// Deprecated: The streams builder is using ./internal/config now.
+func LoadStreamConfigsFromDirectory(replaceEnvVars bool, dir string, opts ...DirectoryOpt) (map[string]stream.Config, map[string][]docs.Lint, error) {
+	var dConf directoryConfig
+	for _, opt := range opts {
+		opt(&dConf)
+	}
+
+	streamMap := map[string]stream.Config{}
+	streamPaths := map[string]string{}
+	lintsMap := map[string][]docs.Lint{}
+
+	dir = filepath.Clean(dir)
+
+	if info, err := ifs.OS().Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return streamMap, lintsMap, nil
 		}
+		return nil, nil, err
+	} else if !info.IsDir() {
+		return streamMap, lintsMap, nil
+	}
 
-		if _, exists := streamMap[id]; exists {
-			return fmt.Errorf("stream id (%v) collision from file: %v", id, path)
+	err := walkStreamConfigFiles(dir, dConf, func(id, path string) error {
+		if existingPath, exists := streamPaths[id]; exists {
+			return fmt.Errorf("stream id (%v) collision between files %v and %v", id, existingPath, path)
 		}
+		streamPaths[id] = path
 
 		conf := config.New()
-		if _, readerr := config.ReadFileLinted(path, config.LintOptions{}, &conf); readerr != nil {
-			// TODO: Read and report linting errors.
+		lints, readerr := config.ReadFileLinted(path, config.LintOptions{}, &conf)
+		if readerr != nil {
 			return readerr
 		}
+		if len(lints) > 0 {
+			lintsMap[id] = lints
+		}
 
 		streamMap[id] = conf.Config
 		return nil
 	})
-
-	return streamMap, err
+	if err != nil {
+		return nil, nil, err
+	}
+	return streamMap, lintsMap, nil
 }
 
 //------------------------------------------------------------------------------
+
+type watchedStreamFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+func (w watchedStreamFile) changedFrom(prev watchedStreamFile) bool {
+	return w.path != prev.path || !w.modTime.Equal(prev.modTime) || w.size != prev.size
+}
+
+// WatchDirectory creates streams from a directory of .json and .yaml files,
+// deriving stream ids in the same way as LoadStreamConfigsFromDirectory, and
+// then polls the directory at the given period until the context is cancelled.
+// Streams are created for new files, updated when their file is modified, and
+// stopped and removed when their file is deleted. Streams that were not created
+// from the directory are left untouched.
+func (m *Type) WatchDirectory(ctx context.Context, dir string, period time.Duration, opts ...DirectoryOpt) {
+	var dConf directoryConfig
+	for _, opt := range opts {
+		opt(&dConf)
+	}
+
+	dir = filepath.Clean(dir)
+	known := map[string]watchedStreamFile{}
+	m.syncDirectory(ctx, dir, dConf, known)
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.syncDirectory(ctx, dir, dConf, known)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// syncDirectory walks a directory of stream configs and applies any changes
+// since the last sync, which are tracked within the known map of files that
+// have been applied successfully.
+func (m *Type) syncDirectory(ctx context.Context, dir string, dConf directoryConfig, known map[string]watchedStreamFile) {
+	logger := m.manager.Logger()
+
+	seen := map[string]watchedStreamFile{}
+	if _, err := ifs.OS().Stat(dir); err == nil {
+		if err = walkStreamConfigFiles(dir, dConf, func(id, path string) error {
+			if existing, exists := seen[id]; exists {
+				logger.Errorf("Stream id (%v) collision between files %v and %v, ignoring the latter", id, existing.path, path)
+				return nil
+			}
+			info, err := ifs.OS().Stat(path)
+			if err != nil {
+				logger.Errorf("Failed to stat stream config %v: %v", path, err)
+				return nil
+			}
+			seen[id] = watchedStreamFile{
+				path:    path,
+				modTime: info.ModTime(),
+				size:    info.Size(),
+			}
+			return nil
+		}); err != nil {
+			logger.Errorf("Failed to walk stream config directory %v: %v", dir, err)
+			return
+		}
+	} else if !os.IsNotExist(err) {
+		logger.Errorf("Failed to stat stream config directory %v: %v", dir, err)
+		return
+	}
+
+	for id, prev := range known {
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		if err := m.Delete(ctx, id); err != nil && !errors.Is(err, ErrStreamDoesNotExist) {
+			logger.Errorf("Failed to remove stream %v: %v", id, err)
+			continue
+		}
+		logger.Infof("Removed stream %v as its config %v was deleted", id, prev.path)
+		delete(known, id)
+	}
+
+	for id, file := range seen {
+		prev, exists := known[id]
+		if exists && !file.changedFrom(prev) {
+			continue
+		}
+
+		// Files are only tracked once their stream has been applied, and
+		// therefore files that fail to be read or applied are retried by the
+		// next sync, and are never considered the owner of a stream that they
+		// failed to create.
+		conf := config.New()
+		lints, err := config.ReadFileLinted(file.path, config.LintOptions{}, &conf)
+		if err != nil {
+			logger.Errorf("Failed to read stream config %v: %v", file.path, err)
+			continue
+		}
+		for _, l := range lints {
+			logger.Warnf("%v%v", file.path, l.Error())
+		}
+
+		if exists {
+			if err = m.Update(ctx, id, conf.Config); errors.Is(err, ErrStreamDoesNotExist) {
+				err = m.Create(id, conf.Config)
+			}
+		} else {
+			err = m.Create(id, conf.Config)
+		}
+		if err != nil {
+			logger.Errorf("Failed to apply stream %v from config %v: %v", id, file.path, err)
+			continue
+		}
+		known[id] = file
+		logger.Infof("Applied stream %v from config %v", id, file.path)
+	}
+}
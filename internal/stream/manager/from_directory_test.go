@@ -1,12 +1,20 @@
 package manager_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	bmanager "github.com/benthosdev/benthos/v4/internal/manager"
 	"github.com/benthosdev/benthos/v4/internal/stream"
 	"github.com/benthosdev/benthos/v4/internal/stream/manager"
 
@@ -30,12 +38,38 @@ input:
   inproc: meow
 `), 0o666))
 
+	bazPath := filepath.Join(testDir, "baz.yaml")
+	require.NoError(t, os.WriteFile(bazPath, []byte(`
+input:
+  generate:
+    mapping: root = {}
+nope: true
+`), 0o666))
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write([]byte(`
+input:
+  generate:
+    mapping: root = {}
+`))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "buz.yaml.gz"), gzipped.Bytes(), 0o666))
+
 	var actConfs map[string]stream.Config
-	actConfs, err := manager.LoadStreamConfigsFromDirectory(true, testDir)
+	actConfs, actLints, err := manager.LoadStreamConfigsFromDirectory(true, testDir)
 	require.NoError(t, err)
 
 	require.Contains(t, actConfs, "foo")
 	require.Contains(t, actConfs, "bar_test")
+	require.Contains(t, actConfs, "baz")
+	require.Contains(t, actConfs, "buz")
+	assert.Equal(t, "generate", actConfs["buz"].Input.Type)
+
+	assert.Equal(t, map[string][]docs.Lint{
+		"baz": {docs.NewLintError(5, docs.LintUnknown, "field nope not recognised")},
+	}, actLints)
 
 	if exp, act := "generate", actConfs["foo"].Input.Type; exp != act {
 		t.Errorf("Wrong value in loaded set: %v != %v", act, exp)
@@ -44,3 +78,201 @@ input:
 		t.Errorf("Wrong value in loaded set: %v != %v", act, exp)
 	}
 }
+
+func TestFromDirectoryNestedIDs(t *testing.T) {
+	testDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(testDir, "a", "b"), 0o777))
+	for _, p := range []string{
+		filepath.Join(testDir, "a", "b.yaml"),
+		filepath.Join(testDir, "a_b.yaml"),
+		filepath.Join(testDir, "a", "b", "c.json"),
+	} {
+		require.NoError(t, os.WriteFile(p, []byte(`{"input":{"generate":{"mapping":"root = {}"}}}`), 0o666))
+	}
+
+	_, _, err := manager.LoadStreamConfigsFromDirectory(true, testDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream id (a_b) collision")
+
+	actConfs, _, err := manager.LoadStreamConfigsFromDirectory(true, testDir, manager.OptDirectoryNestedIDs(true))
+	require.NoError(t, err)
+
+	var ids []string
+	for id := range actConfs {
+		ids = append(ids, id)
+	}
+	assert.ElementsMatch(t, []string{"a/b", "a_b", "a/b/c"}, ids)
+}
+
+func TestFromDirectoryExtensionCollision(t *testing.T) {
+	testDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "foo.json"), []byte(`{"input":{"generate":{"mapping":"root = {}"}}}`), 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "foo.yaml"), []byte(`{"input":{"generate":{"mapping":"root = {}"}}}`), 0o666))
+
+	_, _, err := manager.LoadStreamConfigsFromDirectory(true, testDir, manager.OptDirectoryNestedIDs(true))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream id (foo) collision")
+}
+
+func TestFromDirectoryIDTransform(t *testing.T) {
+	testDir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(testDir, "a"), 0o777))
+	for _, p := range []string{
+		filepath.Join(testDir, "foo.yaml"),
+		filepath.Join(testDir, "a", "bar.yaml"),
+	} {
+		require.NoError(t, os.WriteFile(p, []byte(`{"input":{"generate":{"mapping":"root = {}"}}}`), 0o666))
+	}
+
+	actConfs, _, err := manager.LoadStreamConfigsFromDirectory(true, testDir, manager.OptDirectoryIDTransform(func(id string) string {
+		return "prod_" + id
+	}))
+	require.NoError(t, err)
+
+	var ids []string
+	for id := range actConfs {
+		ids = append(ids, id)
+	}
+	assert.ElementsMatch(t, []string{"prod_foo", "prod_a_bar"}, ids)
+
+	// Stripping the directory prefix results in a collision with a top level
+	// file of the same name.
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "bar.yaml"), []byte(`{"input":{"generate":{"mapping":"root = {}"}}}`), 0o666))
+	_, _, err = manager.LoadStreamConfigsFromDirectory(true, testDir, manager.OptDirectoryIDTransform(func(id string) string {
+		return strings.TrimPrefix(id, "a_")
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream id (bar) collision")
+
+	_, _, err = manager.LoadStreamConfigsFromDirectory(true, testDir, manager.OptDirectoryIDTransform(func(id string) string {
+		return ""
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transformed to an empty string")
+}
+
+func TestWatchDirectory(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	testDir := t.TempDir()
+	fooPath := filepath.Join(testDir, "foo.yaml")
+
+	writeConf := func(mapping string) {
+		require.NoError(t, os.WriteFile(fooPath, []byte(`
+input:
+  generate:
+    interval: 1s
+    mapping: '`+mapping+`'
+output:
+  drop: {}
+`), 0o666))
+	}
+	writeConf("root = deleted()")
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res)
+
+	watchCtx, watchDone := context.WithCancel(ctx)
+	watchExited := make(chan struct{})
+	go func() {
+		mgr.WatchDirectory(watchCtx, testDir, time.Millisecond*10)
+		close(watchExited)
+	}()
+
+	streamMapping := func() string {
+		info, err := mgr.Read("foo")
+		if err != nil {
+			return ""
+		}
+		return info.Config().Input.Generate.Mapping
+	}
+
+	assert.Eventually(t, func() bool {
+		return streamMapping() == "root = deleted()"
+	}, time.Second*5, time.Millisecond*10)
+
+	writeConf("root = {\"changed\":true}")
+	assert.Eventually(t, func() bool {
+		return streamMapping() == `root = {"changed":true}`
+	}, time.Second*5, time.Millisecond*10)
+
+	require.NoError(t, os.Remove(fooPath))
+	assert.Eventually(t, func() bool {
+		_, err := mgr.Read("foo")
+		return err != nil
+	}, time.Second*5, time.Millisecond*10)
+
+	watchDone()
+	<-watchExited
+	require.NoError(t, mgr.Stop(ctx))
+}
+
+func TestWatchDirectoryRetries(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	testDir := t.TempDir()
+	fileConf := []byte(`
+input:
+  generate:
+    interval: 1s
+    mapping: 'root = deleted()'
+output:
+  drop: {}
+`)
+	barPath, bazPath := filepath.Join(testDir, "bar.yaml"), filepath.Join(testDir, "baz.yaml")
+	require.NoError(t, os.WriteFile(barPath, fileConf, 0o666))
+	require.NoError(t, os.WriteFile(bazPath, fileConf, 0o666))
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res)
+
+	// Streams created through the API block the files of the same ids from
+	// being applied.
+	apiConf := stream.NewConfig()
+	apiConf.Input.Type = "generate"
+	apiConf.Input.Generate.Mapping = "root = deleted()"
+	apiConf.Output.Type = "drop"
+	require.NoError(t, mgr.Create("bar", apiConf))
+	require.NoError(t, mgr.Create("baz", apiConf))
+
+	apiBar, err := mgr.Read("bar")
+	require.NoError(t, err)
+
+	watchCtx, watchDone := context.WithCancel(ctx)
+	watchExited := make(chan struct{})
+	go func() {
+		mgr.WatchDirectory(watchCtx, testDir, time.Millisecond*10)
+		close(watchExited)
+	}()
+
+	// A file that failed to be applied is retried by later polls even though
+	// it hasn't changed.
+	time.Sleep(time.Millisecond * 100)
+	require.NoError(t, mgr.Delete(ctx, "baz"))
+	assert.Eventually(t, func() bool {
+		_, err := mgr.Read("baz")
+		return err == nil
+	}, time.Second*5, time.Millisecond*10)
+
+	// Deleting a file that failed to be applied leaves the stream of the
+	// same id untouched.
+	require.NoError(t, os.Remove(barPath))
+	time.Sleep(time.Millisecond * 100)
+
+	bar, err := mgr.Read("bar")
+	require.NoError(t, err)
+	assert.Same(t, apiBar, bar)
+
+	watchDone()
+	<-watchExited
+	require.NoError(t, mgr.Stop(ctx))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -89,7 +90,8 @@ type Type struct {
 	manager    bundle.NewManagement
 	apiEnabled bool
 
-	lock sync.Mutex
+	lock         sync.Mutex
+	reconcileMut sync.Mutex
 }
 
 // New creates a new stream manager.Type.
@@ -231,6 +233,150 @@ func (m *Type) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Reconcile replaces the full set of managed streams with the provided map of
+// stream ids to configs in a single call. Streams with unchanged configs are
+// left running.
+//
+// Streams that are not present within the map are stopped and removed, and
+// streams with changed configs are replaced, before any new streams are built.
+// A stream is only built once the streams it replaces have stopped, as they
+// may claim the same resources, which is also the case when a stream is
+// renamed. If any of these steps fail then the previous set of streams is
+// restored. Failures are returned together as a single error.
+//
+// Calls to Reconcile are serialised, and therefore concurrent callers always
+// observe one set being applied before the next begins.
+func (m *Type) Reconcile(ctx context.Context, confs map[string]stream.Config) error {
+	m.reconcileMut.Lock()
+	defer m.reconcileMut.Unlock()
+
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return component.ErrTypeClosed
+	}
+	previous := make(map[string]*StreamStatus, len(m.streams))
+	for id, wrapper := range m.streams {
+		previous[id] = wrapper
+	}
+	m.lock.Unlock()
+
+	toDelete := map[string]stream.Config{}
+	toUpdate := map[string]stream.Config{}
+	toCreate := map[string]stream.Config{}
+	for id, wrapper := range previous {
+		if conf, exists := confs[id]; !exists {
+			toDelete[id] = wrapper.config
+		} else if !reflect.DeepEqual(wrapper.config, conf) {
+			toUpdate[id] = conf
+		}
+	}
+	for id, conf := range confs {
+		if _, exists := previous[id]; !exists {
+			toCreate[id] = conf
+		}
+	}
+
+	var errDelete, errUpdate []error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errDelete = applyConcurrently(toDelete, func(id string, _ stream.Config) error {
+			return m.Delete(ctx, id)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		errUpdate = applyConcurrently(toUpdate, func(id string, conf stream.Config) error {
+			return m.Update(ctx, id, conf)
+		})
+	}()
+	wg.Wait()
+
+	if len(errDelete) > 0 || len(errUpdate) > 0 {
+		m.restore(ctx, previous)
+		return reconcileErr(errDelete, errUpdate, nil)
+	}
+
+	errCreate := applyConcurrently(toCreate, func(id string, conf stream.Config) error {
+		return m.Create(id, conf)
+	})
+	if len(errCreate) > 0 {
+		m.restore(ctx, previous)
+		return reconcileErr(nil, nil, errCreate)
+	}
+	return nil
+}
+
+// applyConcurrently calls fn for each of a map of stream ids to configs
+// concurrently, and returns the errors of any calls that failed.
+func applyConcurrently(streams map[string]stream.Config, fn func(id string, conf stream.Config) error) []error {
+	var errsMut sync.Mutex
+	var errs []error
+
+	var wg sync.WaitGroup
+	wg.Add(len(streams))
+	for id, conf := range streams {
+		go func(id string, conf stream.Config) {
+			defer wg.Done()
+			if err := fn(id, conf); err != nil {
+				errsMut.Lock()
+				errs = append(errs, err)
+				errsMut.Unlock()
+			}
+		}(id, conf)
+	}
+	wg.Wait()
+	return errs
+}
+
+// restore returns the managed streams to a previous set after a failed
+// reconcile, removing streams that have been created and recreating streams
+// that have been removed or replaced. Streams that are still running from the
+// previous set, including those that failed to stop, are left as they are.
+func (m *Type) restore(ctx context.Context, previous map[string]*StreamStatus) {
+	m.lock.Lock()
+	toDelete := map[string]stream.Config{}
+	toCreate := map[string]stream.Config{}
+	for id, wrapper := range m.streams {
+		if previous[id] != wrapper {
+			toDelete[id] = wrapper.config
+		}
+	}
+	for id, wrapper := range previous {
+		if m.streams[id] != wrapper {
+			toCreate[id] = wrapper.config
+		}
+	}
+	m.lock.Unlock()
+
+	for _, err := range applyConcurrently(toDelete, func(id string, _ stream.Config) error {
+		return m.Delete(ctx, id)
+	}) {
+		m.manager.Logger().Errorf("Failed to remove stream during restore: %v\n", err)
+	}
+	for _, err := range applyConcurrently(toCreate, func(id string, conf stream.Config) error {
+		return m.Create(id, conf)
+	}) {
+		m.manager.Logger().Errorf("Failed to restore stream: %v\n", err)
+	}
+}
+
+func reconcileErr(errDelete, errUpdate, errCreate []error) error {
+	var errs []string
+	for _, err := range errDelete {
+		errs = append(errs, fmt.Sprintf("failed to delete stream: %v", err))
+	}
+	for _, err := range errUpdate {
+		errs = append(errs, fmt.Sprintf("failed to update stream: %v", err))
+	}
+	for _, err := range errCreate {
+		errs = append(errs, fmt.Sprintf("failed to create stream: %v", err))
+	}
+	return errors.New(strings.Join(errs, "\n"))
+}
+
 //------------------------------------------------------------------------------
 
 // Stop attempts to gracefully shut down all active streams and close the
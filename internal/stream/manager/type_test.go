@@ -2,14 +2,21 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	bmanager "github.com/benthosdev/benthos/v4/internal/manager"
+	"github.com/benthosdev/benthos/v4/internal/message"
 	"github.com/benthosdev/benthos/v4/internal/stream"
 )
 
@@ -105,3 +112,186 @@ func TestTypeBasicClose(t *testing.T) {
 		t.Errorf("Unexpected error: %v != %v", act, exp)
 	}
 }
+
+func TestTypeReconcile(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := New(res)
+
+	require.NoError(t, mgr.Create("foo", harmlessConf()))
+	require.NoError(t, mgr.Create("bar", harmlessConf()))
+	require.NoError(t, mgr.Create("baz", harmlessConf()))
+
+	barBefore, err := mgr.Read("bar")
+	require.NoError(t, err)
+	bazBefore, err := mgr.Read("baz")
+	require.NoError(t, err)
+
+	changedConf := harmlessConf()
+	changedConf.Buffer.Type = "memory"
+
+	require.NoError(t, mgr.Reconcile(ctx, map[string]stream.Config{
+		"bar": harmlessConf(),
+		"baz": changedConf,
+		"buz": harmlessConf(),
+	}))
+
+	_, err = mgr.Read("foo")
+	require.ErrorIs(t, err, ErrStreamDoesNotExist)
+
+	barAfter, err := mgr.Read("bar")
+	require.NoError(t, err)
+	require.Same(t, barBefore, barAfter)
+
+	bazAfter, err := mgr.Read("baz")
+	require.NoError(t, err)
+	require.NotSame(t, bazBefore, bazAfter)
+	require.Equal(t, changedConf, bazAfter.Config())
+
+	buz, err := mgr.Read("buz")
+	require.NoError(t, err)
+	require.True(t, buz.IsRunning())
+
+	badConf := harmlessConf()
+	badConf.Input.Type = "nope"
+
+	running := map[string]*StreamStatus{}
+	for _, id := range []string{"bar", "baz", "buz"} {
+		running[id], err = mgr.Read(id)
+		require.NoError(t, err)
+	}
+
+	// A new stream that fails to build restores the removed streams.
+	err = mgr.Reconcile(ctx, map[string]stream.Config{
+		"bar": harmlessConf(),
+		"qux": badConf,
+	})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "failed to create stream: "), err.Error())
+
+	_, err = mgr.Read("qux")
+	require.ErrorIs(t, err, ErrStreamDoesNotExist)
+
+	barAfter, err = mgr.Read("bar")
+	require.NoError(t, err)
+	require.Same(t, running["bar"], barAfter)
+
+	for _, id := range []string{"baz", "buz"} {
+		after, err := mgr.Read(id)
+		require.NoError(t, err, id)
+		require.Equal(t, running[id].Config(), after.Config(), id)
+		require.True(t, after.IsRunning(), id)
+	}
+
+	// A changed stream that fails to build restores the previous set.
+	err = mgr.Reconcile(ctx, map[string]stream.Config{
+		"bar": harmlessConf(),
+		"baz": badConf,
+		"qux": harmlessConf(),
+	})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "failed to update stream: "), err.Error())
+
+	_, err = mgr.Read("qux")
+	require.ErrorIs(t, err, ErrStreamDoesNotExist)
+
+	barAfter, err = mgr.Read("bar")
+	require.NoError(t, err)
+	require.Same(t, running["bar"], barAfter)
+
+	for _, id := range []string{"baz", "buz"} {
+		after, err := mgr.Read(id)
+		require.NoError(t, err, id)
+		require.Equal(t, running[id].Config(), after.Config(), id)
+		require.True(t, after.IsRunning(), id)
+	}
+
+	require.NoError(t, mgr.Stop(ctx))
+	require.Equal(t, component.ErrTypeClosed, mgr.Reconcile(ctx, map[string]stream.Config{}))
+}
+
+// claimingInput is an input that holds an exclusive claim on a resource until
+// it is closed, like an input that binds to a port.
+type claimingInput struct {
+	tChan     chan message.Transaction
+	closeOnce sync.Once
+	release   func()
+}
+
+func (c *claimingInput) TransactionChan() <-chan message.Transaction {
+	return c.tChan
+}
+
+func (c *claimingInput) Connected() bool {
+	return true
+}
+
+func (c *claimingInput) TriggerStopConsuming() {
+	c.closeOnce.Do(func() {
+		c.release()
+		close(c.tChan)
+	})
+}
+
+func (c *claimingInput) TriggerCloseNow() {
+	c.TriggerStopConsuming()
+}
+
+func (c *claimingInput) WaitForClose(ctx context.Context) error {
+	return nil
+}
+
+func TestTypeReconcileRenameClaimedResource(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	var claimMut sync.Mutex
+	var claimed bool
+
+	env := bundle.GlobalEnvironment.Clone()
+	require.NoError(t, env.InputAdd(func(c input.Config, mgr bundle.NewManagement) (input.Streamed, error) {
+		claimMut.Lock()
+		defer claimMut.Unlock()
+		if claimed {
+			return nil, errors.New("resource already claimed")
+		}
+		claimed = true
+		return &claimingInput{
+			tChan: make(chan message.Transaction),
+			release: func() {
+				claimMut.Lock()
+				claimed = false
+				claimMut.Unlock()
+			},
+		}, nil
+	}, docs.ComponentSpec{
+		Name: "claiming",
+	}))
+
+	res, err := bmanager.New(bmanager.NewResourceConfig(), bmanager.OptSetEnvironment(env))
+	require.NoError(t, err)
+
+	mgr := New(res)
+
+	conf := harmlessConf()
+	conf.Input.Type = "claiming"
+	require.NoError(t, mgr.Create("foo", conf))
+
+	// Renaming the stream releases the resource before it is claimed again.
+	require.NoError(t, mgr.Reconcile(ctx, map[string]stream.Config{
+		"bar": conf,
+	}))
+
+	_, err = mgr.Read("foo")
+	require.ErrorIs(t, err, ErrStreamDoesNotExist)
+
+	bar, err := mgr.Read("bar")
+	require.NoError(t, err)
+	require.True(t, bar.IsRunning())
+
+	require.NoError(t, mgr.Stop(ctx))
+}
@@ -58,9 +58,8 @@ func NewStringField(name string) *ConfigField {
 // NewDurationField describes a new duration string type config field, allowing
 // users to define a time interval with strings of the form 60s, 3m, etc.
 func NewDurationField(name string) *ConfigField {
-	// TODO: Add linting rule for duration
 	return &ConfigField{
-		field: docs.FieldString(name, ""),
+		field: docs.FieldDuration(name, ""),
 	}
 }
 
@@ -249,6 +248,14 @@ func (c *ConfigField) LintRule(blobl string) *ConfigField {
 	return c
 }
 
+// LintRuleOfType adds a custom linting rule to the field in the same way as
+// LintRule, where the resulting lints are of the provided type, which is
+// usually one registered with RegisterLintType.
+func (c *ConfigField) LintRuleOfType(t LintType, blobl string) *ConfigField {
+	c.field = c.field.LinterBlobl(blobl).LintsOfType(docs.LintType(t))
+	return c
+}
+
 //------------------------------------------------------------------------------
 
 // ConfigSpec describes the configuration specification for a plugin
@@ -328,6 +335,17 @@ func (c *ConfigSpec) Beta() *ConfigSpec {
 	return c
 }
 
+// Experimental sets a documentation label on the component indicating that its
+// configuration spec is experimental and may change in backwards incompatible
+// ways. Unlike the default experimental label of plugins, explicitly marking a
+// component as experimental also results in a warning being logged the first
+// time it is constructed.
+func (c *ConfigSpec) Experimental() *ConfigSpec {
+	c.component.Status = docs.StatusExperimental
+	c.component.ExperimentalWarning = true
+	return c
+}
+
 // Deprecated sets a documentation label on the component indicating that it is
 // now deprecated. Plugins are considered experimental by default.
 func (c *ConfigSpec) Deprecated() *ConfigSpec {
@@ -432,6 +450,14 @@ func (c *ConfigSpec) LintRule(blobl string) *ConfigSpec {
 	return c
 }
 
+// LintRuleOfType adds a custom linting rule to the ConfigSpec in the same way
+// as LintRule, where the resulting lints are of the provided type, which is
+// usually one registered with RegisterLintType.
+func (c *ConfigSpec) LintRuleOfType(t LintType, blobl string) *ConfigSpec {
+	c.component.Config = c.component.Config.LinterBlobl(blobl).LintsOfType(docs.LintType(t))
+	return c
+}
+
 //------------------------------------------------------------------------------
 
 // ConfigView is a struct returned by a Benthos service environment when walking
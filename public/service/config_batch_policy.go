@@ -24,6 +24,13 @@ type BatchPolicy struct {
 	Check    string
 	Period   string
 
+	// Jitter is a maximum random duration added to the period of each batch,
+	// and JitterSeed optionally seeds the random durations. These are only
+	// parsed by FieldBatchPolicy when present in the config, as they are not
+	// included in NewBatchPolicyField.
+	Jitter     string
+	JitterSeed int64
+
 	// Only available when using NewBatchPolicyField.
 	procs []processor.Config
 }
@@ -34,6 +41,8 @@ func (b BatchPolicy) toInternal() batchconfig.Config {
 	batchConf.Count = b.Count
 	batchConf.Check = b.Check
 	batchConf.Period = b.Period
+	batchConf.Jitter = b.Jitter
+	batchConf.JitterSeed = b.JitterSeed
 	batchConf.Processors = b.procs
 	return batchConf
 }
@@ -140,6 +149,18 @@ func (p *ParsedConfig) FieldBatchPolicy(path ...string) (conf BatchPolicy, err e
 	if conf.Period, err = p.FieldString(append(path, "period")...); err != nil {
 		return conf, err
 	}
+	if p.Contains(append(path, "jitter")...) {
+		if conf.Jitter, err = p.FieldString(append(path, "jitter")...); err != nil {
+			return conf, err
+		}
+	}
+	if p.Contains(append(path, "jitter_seed")...) {
+		var seed int
+		if seed, err = p.FieldInt(append(path, "jitter_seed")...); err != nil {
+			return conf, err
+		}
+		conf.JitterSeed = int64(seed)
+	}
 
 	procsNode, exists := p.field(append(path, "processors")...)
 	if !exists {
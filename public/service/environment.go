@@ -76,6 +76,27 @@ func (e *Environment) NewStreamBuilder() *StreamBuilder {
 	return sb
 }
 
+// LintYAML lints a YAML config against the components known to the
+// environment, returning any lints found. This allows configs to be validated
+// without writing them to disk or building a stream from them. When
+// resourcesOnly is true the config is linted as a file containing only
+// resources, otherwise it is linted as a full stream config.
+//
+// Linting can be disabled entirely for a config that begins with the comment
+// `# BENTHOS LINT DISABLE`, and specific lint types can be disabled with one or
+// more `# benthos-lint-disable <type>...` comments.
+func (e *Environment) LintYAML(conf []byte, resourcesOnly bool) ([]Lint, error) {
+	dLints, err := config.LintYAMLBytes(e.getLintContext(), conf, resourcesOnly)
+	if err != nil {
+		return nil, err
+	}
+	var lints []Lint
+	for _, l := range dLints {
+		lints = append(lints, convertDocsLint(l))
+	}
+	return lints, nil
+}
+
 //------------------------------------------------------------------------------
 
 func (e *Environment) getBloblangParserEnv() *ibloblang.Environment {
@@ -87,6 +108,13 @@ func (e *Environment) getBloblangParserEnv() *ibloblang.Environment {
 	return ibloblang.GlobalEnvironment()
 }
 
+func (e *Environment) getLintContext() docs.LintContext {
+	ctx := docs.NewLintContext()
+	ctx.DocsProvider = e.internal
+	ctx.BloblangEnv = e.getBloblangParserEnv().Deactivated()
+	return ctx
+}
+
 //------------------------------------------------------------------------------
 
 // RegisterBatchBuffer attempts to register a new buffer plugin by providing a
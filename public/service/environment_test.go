@@ -147,3 +147,53 @@ logger:
 	require.NoError(t, strm.StopWithin(time.Second))
 	assert.Equal(t, []string{"meow"}, received)
 }
+
+func TestEnvironmentLintYAML(t *testing.T) {
+	env := service.NewEnvironment()
+	require.NoError(t, env.RegisterProcessor(
+		"lint_test_processor", service.NewConfigSpec().Field(service.NewStringField("foo")),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return nil, errors.New("nope")
+		},
+	))
+
+	lints, err := env.LintYAML([]byte(`
+pipeline:
+  processors:
+    - lint_test_processor:
+        foo: a
+        bar: b
+`), false)
+	require.NoError(t, err)
+	require.Len(t, lints, 1)
+	assert.Equal(t, 6, lints[0].Line)
+	assert.Equal(t, service.LintUnknown, lints[0].Type)
+	assert.Equal(t, "field bar not recognised", lints[0].What)
+
+	lints, err = env.LintYAML([]byte(`
+processor_resources:
+  - label: foo
+    lint_test_processor:
+      foo: a
+`), true)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	lints, err = env.LintYAML([]byte(`
+pipeline:
+  processors: []
+`), true)
+	require.NoError(t, err)
+	require.Len(t, lints, 1)
+	assert.Equal(t, "field pipeline not recognised", lints[0].What)
+
+	lints, err = env.LintYAML([]byte(`# benthos-lint-disable unknown
+pipeline:
+  processors: []
+`), true)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	_, err = env.LintYAML([]byte(`{`), false)
+	require.Error(t, err)
+}
@@ -42,3 +42,13 @@ func (i *InterpolatedString) String(m *Message) string {
 func (i *InterpolatedString) Bytes(m *Message) []byte {
 	return i.expr.Bytes(0, fauxOldMessage{m.part})
 }
+
+// Static returns the value of the interpolated string along with true when it
+// contains no dynamic interpolation functions, in which case the value is the
+// same for all messages. Otherwise an empty string and false are returned.
+func (i *InterpolatedString) Static() (string, bool) {
+	if i.expr.NumDynamicExpressions() > 0 {
+		return "", false
+	}
+	return i.expr.String(0, fauxOldMessage{message.NewPart(nil)}), true
+}
@@ -47,3 +47,19 @@ func TestInterpolatedString(t *testing.T) {
 		})
 	}
 }
+
+func TestInterpolatedStringStatic(t *testing.T) {
+	i, err := NewInterpolatedString(`foo bar`)
+	require.NoError(t, err)
+
+	v, ok := i.Static()
+	assert.True(t, ok)
+	assert.Equal(t, "foo bar", v)
+
+	i, err = NewInterpolatedString(`foo ${! meta("bar") }`)
+	require.NoError(t, err)
+
+	v, ok = i.Static()
+	assert.False(t, ok)
+	assert.Equal(t, "", v)
+}
@@ -12,6 +12,39 @@ import (
 // NOTE: These should be kept in sync with ./internal/docs/field.go:586.
 type LintType int
 
+// RegisterLintType registers a named lint type that can be assigned to the
+// lints of custom linting rules with LintRuleOfType, allowing them to be
+// distinguished from other custom lints. The name of a lint type is returned by
+// its String method, and can be used in order to disable lints of the type
+// with a `# benthos-lint-disable <type>` comment within a config.
+//
+// Registering a name that is already registered returns the same type. An
+// error is returned when the name contains characters other than lowercase
+// alphanumerics and underscores, or is the name of a core lint type.
+func RegisterLintType(name string) (LintType, error) {
+	t, err := docs.RegisterLintType(name)
+	if err != nil {
+		return LintCustom, err
+	}
+	return LintType(t), nil
+}
+
+// LintTypeFromString returns the lint type with a given name, which is either
+// a core lint type or one registered with RegisterLintType.
+func LintTypeFromString(name string) (LintType, error) {
+	t, err := docs.LintTypeFromString(name)
+	if err != nil {
+		return LintCustom, err
+	}
+	return convertDocsLintType(t), nil
+}
+
+// String returns the name of the lint type, such as `unknown` or
+// `invalid_option`.
+func (t LintType) String() string {
+	return docs.LintType(t).String()
+}
+
 const (
 	// LintCustom means a custom linting rule failed.
 	LintCustom LintType = iota
@@ -66,6 +99,21 @@ const (
 
 	// LintDeprecated means a field is deprecated and should not be used.
 	LintDeprecated LintType = iota
+
+	// LintMissingEnvVar means an environment variable was referenced without a
+	// default value but is not set.
+	LintMissingEnvVar LintType = iota
+
+	// LintBadInterpolation means an interpolation may resolve to a malformed
+	// value.
+	LintBadInterpolation LintType = iota
+
+	// LintPlaintextSecret means a secret field was set to a plaintext value
+	// rather than an environment variable interpolation.
+	LintPlaintextSecret LintType = iota
+
+	// LintUnusedResource means a resource is defined but never referenced.
+	LintUnusedResource LintType = iota
 )
 
 func convertDocsLintType(d docs.LintType) LintType {
@@ -102,8 +150,17 @@ func convertDocsLintType(d docs.LintType) LintType {
 		return LintExpectedScalar
 	case docs.LintDeprecated:
 		return LintDeprecated
+	case docs.LintMissingEnvVar:
+		return LintMissingEnvVar
+	case docs.LintBadInterpolation:
+		return LintBadInterpolation
+	case docs.LintPlaintextSecret:
+		return LintPlaintextSecret
+	case docs.LintUnusedResource:
+		return LintUnusedResource
 	}
-	return LintCustom
+	// Lint types registered by plugins share their values across packages.
+	return LintType(d)
 }
 
 // Lint represents a configuration file linting error.
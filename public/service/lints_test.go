@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func TestLintTypeRoundTrip(t *testing.T) {
+	assert.Equal(t, "unknown", LintUnknown.String())
+
+	lt, err := LintTypeFromString("unused_resource")
+	require.NoError(t, err)
+	assert.Equal(t, LintUnusedResource, lt)
+
+	custom, err := RegisterLintType("test_service_round_trip")
+	require.NoError(t, err)
+	assert.Equal(t, "test_service_round_trip", custom.String())
+
+	lt, err = LintTypeFromString("test_service_round_trip")
+	require.NoError(t, err)
+	assert.Equal(t, custom, lt)
+}
+
+func TestLintRuleOfType(t *testing.T) {
+	lt, err := RegisterLintType("test_service_no_cats")
+	require.NoError(t, err)
+
+	spec := NewConfigSpec().
+		Field(NewStringField("a").LintRuleOfType(lt, `root = if this.contains("meow") { [ "no cats allowed" ] }`))
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`a: hello meow`), &node))
+
+	lints := spec.component.Config.LintYAML(docs.NewLintContext(), node.Content[0])
+	require.Len(t, lints, 1)
+
+	lint := convertDocsLint(lints[0])
+	assert.Equal(t, lt, lint.Type)
+	assert.Equal(t, "no cats allowed", lint.What)
+}